@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+	"inventory-api/internal/services"
+)
+
+func main() {
+	filePath := flag.String("file", "", "path to the .csv or .xlsx file to import")
+	userEmail := flag.String("user", "", "email of the user the import is attributed to")
+	mapping := flag.String("map", "", "column mapping, e.g. name=Product Name,sku=Code")
+	dryRun := flag.Bool("dry-run", false, "validate the file without writing anything")
+	upsert := flag.Bool("upsert", false, "update items whose SKU already exists")
+	warehouseID := flag.String("warehouse", "", "warehouse ID the stock column applies to (default warehouse if empty)")
+	flag.Parse()
+	
+	if *filePath == "" || *userEmail == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	
+	if err := godotenv.Load(); err != nil {
+		log.Println("Warning: .env file not found")
+	}
+	
+	cfg := config.LoadConfig()
+	
+	database.ConnectDB(cfg)
+	
+	user, err := repositories.NewUserRepository().FindByEmail(*userEmail)
+	if err != nil {
+		log.Fatalf("User %s not found", *userEmail)
+	}
+	
+	opts := &models.ItemImportOptions{
+		Mapping:     map[string]string{},
+		DryRun:      *dryRun,
+		Upsert:      *upsert,
+		WarehouseID: *warehouseID,
+	}
+	for _, pair := range strings.Split(*mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		field, header, ok := strings.Cut(pair, "=")
+		if !ok {
+			log.Fatalf("Invalid mapping %q, expected field=Header", pair)
+		}
+		opts.Mapping[strings.TrimSpace(field)] = strings.TrimSpace(header)
+	}
+	
+	f, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatal("Failed to open file:", err)
+	}
+	defer f.Close()
+	
+	result, err := services.NewItemService(cfg).ImportItems(*filePath, f, opts, user.ID)
+	if err != nil {
+		log.Fatal("Import failed:", err)
+	}
+	
+	for _, rowErr := range result.Errors {
+		if rowErr.Field != "" {
+			fmt.Printf("row %d, %s: %s\n", rowErr.Row, rowErr.Field, rowErr.Message)
+		} else {
+			fmt.Printf("row %d: %s\n", rowErr.Row, rowErr.Message)
+		}
+	}
+	
+	fmt.Printf("%d rows, %d to create, %d to update, %d errors\n",
+		result.TotalRows, result.Created, result.Updated, len(result.Errors))
+	
+	switch {
+	case len(result.Errors) > 0:
+		fmt.Println("Nothing was imported.")
+		os.Exit(1)
+	case result.DryRun:
+		fmt.Println("Dry run only, nothing was imported.")
+	default:
+		fmt.Println("Import completed.")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"inventory-api/internal/config"
 	"inventory-api/internal/database"
 	"inventory-api/internal/models"
+	"inventory-api/internal/seeders"
 
 	"gorm.io/gorm"
 )
@@ -24,9 +25,45 @@ func main() {
 	log.Println("Starting fresh migration...")
 	
 	err := database.DB.Migrator().DropTable(
+		"role_permissions",
+		&models.IdempotencyKey{},
+		&models.OutboxEvent{},
+		&models.WebhookAttempt{},
+		&models.WebhookDelivery{},
+		&models.WebhookSubscription{},
+		&models.StocktakeCount{},
+		&models.StocktakeLine{},
+		&models.Stocktake{},
+		&models.KitBuild{},
+		&models.KitComponent{},
+		&models.AttributeDefinition{},
+		&models.ItemUnit{},
+		&models.Unit{},
+		&models.Category{},
+		&models.SerialEvent{},
+		&models.SerialNumber{},
+		&models.StockLot{},
+		&models.CostLayer{},
+		&models.StockAlert{},
+		&models.StockReservation{},
+		&models.SalesOrderLine{},
+		&models.SalesOrder{},
+		&models.PurchaseOrderLine{},
+		&models.PurchaseOrder{},
+		&models.Supplier{},
+		&models.StockMovement{},
+		&models.ItemStock{},
 		&models.User{},
 		&models.Item{},
 		&models.ActivityLog{},
+		&models.Permission{},
+		&models.Role{},
+		&models.UserSession{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.Warehouse{},
+		&models.Bin{},
+		&models.ItemStock{},
 	)
 	if err != nil {
 		log.Fatal("Failed to drop tables:", err)
@@ -37,14 +74,80 @@ func main() {
 		&models.User{},
 		&models.Item{},
 		&models.ActivityLog{},
+		&models.Permission{},
+		&models.Role{},
+		&models.UserSession{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.Warehouse{},
+		&models.Bin{},
+		&models.ItemStock{},
+		&models.StockMovement{},
+		&models.Supplier{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.SalesOrder{},
+		&models.SalesOrderLine{},
+		&models.StockReservation{},
+		&models.StockAlert{},
+		&models.CostLayer{},
+		&models.StockLot{},
+		&models.SerialNumber{},
+		&models.SerialEvent{},
+		&models.Category{},
+		&models.Unit{},
+		&models.ItemUnit{},
+		&models.AttributeDefinition{},
+		&models.KitComponent{},
+		&models.KitBuild{},
+		&models.Stocktake{},
+		&models.StocktakeLine{},
+		&models.StocktakeCount{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookAttempt{},
+		&models.OutboxEvent{},
+		&models.IdempotencyKey{},
 	)
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 	
+	if err := database.ProtectStockLedger(database.DB); err != nil {
+		log.Fatal("Failed to protect stock ledger:", err)
+	}
+	
 	log.Println("Fresh migration completed successfully!")
 	
+	if err := seeders.NewRoleSeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed roles:", err)
+	}
+	
+	if err := seeders.NewUnitSeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed units:", err)
+	}
+	
 	seedSampleData(database.DB)
+	
+	if err := seeders.NewCategorySeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed categories:", err)
+	}
+	
+	if err := seeders.NewWarehouseSeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed warehouses:", err)
+	}
+	
+	if err := seeders.NewStockLedgerSeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed stock ledger:", err)
+	}
+	
+	if err := seeders.NewCostLayerSeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed cost layers:", err)
+	}
+	
+	if err := seeders.NewSerialSeeder(database.DB).Run(); err != nil {
+		log.Fatal("Failed to seed serial numbers:", err)
+	}
 }
 
 func seedSampleData(db *gorm.DB) {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"log"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -11,7 +12,9 @@ import (
 	"inventory-api/internal/config"
 	"inventory-api/internal/controllers"
 	"inventory-api/internal/database"
+	"inventory-api/internal/jobs"
 	"inventory-api/internal/middleware"
+	"inventory-api/internal/models"
 	"inventory-api/internal/seeders"
 	"inventory-api/internal/services"
 )
@@ -27,39 +30,189 @@ func main() {
 	
 	runSeeders()
 	
-	services.NewItemService()
+	services.NewItemService(cfg)
 	
 	authController := controllers.NewAuthController(cfg)
-	itemController := controllers.NewItemController()
+	itemController := controllers.NewItemController(cfg)
 	activityController := controllers.NewActivityController()
+	roleController := controllers.NewRoleController()
+	userController := controllers.NewUserController()
+	warehouseController := controllers.NewWarehouseController()
+	stockLedgerController := controllers.NewStockLedgerController()
+	supplierController := controllers.NewSupplierController()
+	purchaseOrderController := controllers.NewPurchaseOrderController(cfg)
+	salesOrderController := controllers.NewSalesOrderController(cfg)
+	stockAlertController := controllers.NewStockAlertController()
+	webhookController := controllers.NewWebhookController(cfg)
+	streamController := controllers.NewStreamController()
+	reportController := controllers.NewReportController()
+	stocktakeController := controllers.NewStocktakeController(cfg)
+	lotController := controllers.NewLotController(cfg)
+	serialController := controllers.NewSerialController()
+	unitController := controllers.NewUnitController()
+	attributeController := controllers.NewAttributeController()
+	categoryController := controllers.NewCategoryController()
+	
+	jobs.Every("reservation-expiry", time.Minute, services.NewSalesOrderService(cfg).ExpireReservations)
+	jobs.Every("outbox-dispatch", time.Duration(cfg.OutboxPollIntervalSeconds)*time.Second, services.NewOutboxService(cfg).Dispatch)
+	jobs.Every("webhook-delivery", time.Duration(cfg.WebhookPollIntervalSeconds)*time.Second, services.NewWebhookService(cfg).DeliverPending)
+	jobs.Every("lot-quarantine", time.Hour, services.NewLotService(cfg).QuarantineExpired)
+	jobs.Every("idempotency-cleanup", time.Hour, services.NewIdempotencyService(cfg).PurgeExpired)
 	
 	app := fiber.New(fiber.Config{
 		AppName: "Inventory Management API",
 	})
 	
 	app.Use(cors.New(cors.Config{
-		AllowOrigins: "*",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, PATCH, DELETE",
+		AllowOrigins:  "*",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Last-Event-ID, If-Match, Idempotency-Key",
+		AllowMethods:  "GET, POST, PUT, PATCH, DELETE",
+		ExposeHeaders: "ETag, Idempotent-Replayed",
 	}))
 	app.Use(logger.New())
 	
 	api := app.Group("/api")
 	api.Post("/register", authController.Register)
 	api.Post("/login", authController.Login)
+	api.Post("/auth/refresh", authController.Refresh)
+	api.Get("/stream", middleware.TokenFromQuery(), middleware.JWTMiddleware(cfg), middleware.RequirePermission(models.PermissionItemRead), streamController.Stream)
 	
 	protected := api.Group("", middleware.JWTMiddleware(cfg))
+	protected.Post("/auth/logout", authController.Logout)
+	protected.Post("/auth/logout-all", authController.LogoutAll)
 	protected.Get("/profile", authController.Profile)
+	protected.Post("/profile/password", authController.ChangePassword)
 
-	protected.Get("/activities", activityController.GetAllActivities)
+	protected.Get("/activities", middleware.RequirePermission(models.PermissionActivityRead), activityController.GetAllActivities)
+	protected.Get("/activities/export", middleware.RequirePermission(models.PermissionActivityRead), activityController.ExportActivities)
+	
+	idempotent := middleware.Idempotency(cfg)
 	
 	items := protected.Group("/items")
-	items.Post("/", itemController.CreateItem)
-	items.Get("/", itemController.GetAllItems)
-	items.Get("/:id", itemController.GetItemByID)
-	items.Put("/:id", itemController.UpdateItem)
-	items.Patch("/:id/stock", itemController.UpdateStock)
-	items.Delete("/:id", itemController.DeleteItem)
+	items.Post("/", middleware.RequirePermission(models.PermissionItemCreate), idempotent, itemController.CreateItem)
+	items.Get("/", middleware.RequirePermission(models.PermissionItemRead), itemController.GetAllItems)
+	items.Get("/export", middleware.RequirePermission(models.PermissionItemRead), itemController.ExportItems)
+	items.Post("/import", middleware.RequirePermission(models.PermissionItemCreate, models.PermissionItemUpdate, models.PermissionStockAdjust), idempotent, itemController.ImportItems)
+	items.Get("/:id", middleware.RequirePermission(models.PermissionItemRead), itemController.GetItemByID)
+	items.Put("/:id", middleware.RequirePermission(models.PermissionItemUpdate), idempotent, itemController.UpdateItem)
+	items.Get("/:id/stock", middleware.RequirePermission(models.PermissionItemRead), itemController.GetItemStock)
+	items.Get("/:id/stock/as-of", middleware.RequirePermission(models.PermissionItemRead), stockLedgerController.GetStockAsOf)
+	items.Get("/:id/stock/reconcile", middleware.RequirePermission(models.PermissionItemRead), stockLedgerController.ReconcileItem)
+	items.Get("/:id/movements", middleware.RequirePermission(models.PermissionItemRead), stockLedgerController.GetMovements)
+	items.Get("/:id/lots", middleware.RequirePermission(models.PermissionItemRead), lotController.GetItemLots)
+	items.Get("/:id/serials", middleware.RequirePermission(models.PermissionItemRead), serialController.GetItemSerials)
+	items.Get("/:id/variants", middleware.RequirePermission(models.PermissionItemRead), itemController.GetItemVariants)
+	items.Get("/:id/units", middleware.RequirePermission(models.PermissionItemRead), itemController.GetItemUnits)
+	items.Put("/:id/units", middleware.RequirePermission(models.PermissionItemUpdate), idempotent, itemController.SetItemUnits)
+	items.Get("/:id/kit", middleware.RequirePermission(models.PermissionItemRead), itemController.GetKit)
+	items.Put("/:id/kit", middleware.RequirePermission(models.PermissionItemUpdate), idempotent, itemController.SetKitComponents)
+	items.Post("/:id/assemble", middleware.RequirePermission(models.PermissionStockAdjust), idempotent, itemController.AssembleKit)
+	items.Post("/:id/disassemble", middleware.RequirePermission(models.PermissionStockAdjust), idempotent, itemController.DisassembleKit)
+	items.Get("/:id/cost-layers", middleware.RequirePermission(models.PermissionReportRead), itemController.GetCostLayers)
+	items.Patch("/:id/stock", middleware.RequirePermission(models.PermissionStockAdjust), idempotent, itemController.UpdateStock)
+	items.Post("/:id/transfer", middleware.RequirePermission(models.PermissionStockAdjust), idempotent, itemController.TransferStock)
+	items.Get("/:id/reservations", middleware.RequirePermission(models.PermissionSalesRead), salesOrderController.GetItemReservations)
+	items.Delete("/:id", middleware.RequirePermission(models.PermissionItemDelete), idempotent, itemController.DeleteItem)
+	
+	protected.Get("/stock/reconcile", middleware.RequirePermission(models.PermissionItemRead), stockLedgerController.ReconcileAll)
+	protected.Get("/alerts", middleware.RequirePermission(models.PermissionItemRead), stockAlertController.GetAllAlerts)
+	protected.Get("/reports/valuation", middleware.RequirePermission(models.PermissionReportRead), reportController.GetValuation)
+	
+	protected.Get("/serials/:serial", middleware.RequirePermission(models.PermissionItemRead), serialController.LookupSerial)
+	
+	units := protected.Group("/units")
+	units.Get("/", middleware.RequirePermission(models.PermissionItemRead), unitController.GetAllUnits)
+	units.Post("/", middleware.RequirePermission(models.PermissionUnitManage), unitController.CreateUnit)
+	units.Delete("/:code", middleware.RequirePermission(models.PermissionUnitManage), unitController.DeleteUnit)
+	
+	categories := protected.Group("/categories")
+	categories.Get("/", middleware.RequirePermission(models.PermissionItemRead), categoryController.GetCategoryTree)
+	categories.Get("/:id", middleware.RequirePermission(models.PermissionItemRead), categoryController.GetCategoryByID)
+	categories.Post("/", middleware.RequirePermission(models.PermissionCategoryManage), categoryController.CreateCategory)
+	categories.Put("/:id", middleware.RequirePermission(models.PermissionCategoryManage), categoryController.UpdateCategory)
+	categories.Delete("/:id", middleware.RequirePermission(models.PermissionCategoryManage), categoryController.DeleteCategory)
+	
+	attributes := protected.Group("/attributes")
+	attributes.Get("/", middleware.RequirePermission(models.PermissionItemRead), attributeController.GetAllAttributes)
+	attributes.Post("/", middleware.RequirePermission(models.PermissionAttributeManage), attributeController.CreateAttribute)
+	attributes.Delete("/:id", middleware.RequirePermission(models.PermissionAttributeManage), attributeController.DeleteAttribute)
+	
+	lots := protected.Group("/lots")
+	lots.Get("/expiring", middleware.RequirePermission(models.PermissionItemRead), lotController.GetExpiringLots)
+	lots.Post("/:id/quarantine", middleware.RequirePermission(models.PermissionStockAdjust), idempotent, lotController.QuarantineLot)
+	lots.Post("/:id/release", middleware.RequirePermission(models.PermissionStockAdjust), idempotent, lotController.ReleaseLot)
+	
+	warehouses := protected.Group("/warehouses")
+	warehouses.Get("/", middleware.RequirePermission(models.PermissionItemRead), warehouseController.GetAllWarehouses)
+	warehouses.Get("/:id", middleware.RequirePermission(models.PermissionItemRead), warehouseController.GetWarehouseByID)
+	warehouses.Post("/", middleware.RequirePermission(models.PermissionWarehouseManage), warehouseController.CreateWarehouse)
+	warehouses.Put("/:id", middleware.RequirePermission(models.PermissionWarehouseManage), warehouseController.UpdateWarehouse)
+	warehouses.Delete("/:id", middleware.RequirePermission(models.PermissionWarehouseManage), warehouseController.DeleteWarehouse)
+	warehouses.Post("/:id/bins", middleware.RequirePermission(models.PermissionWarehouseManage), warehouseController.CreateBin)
+	warehouses.Delete("/:id/bins/:binId", middleware.RequirePermission(models.PermissionWarehouseManage), warehouseController.DeleteBin)
+	
+	suppliers := protected.Group("/suppliers")
+	suppliers.Get("/", middleware.RequirePermission(models.PermissionPurchaseRead), supplierController.GetAllSuppliers)
+	suppliers.Get("/:id", middleware.RequirePermission(models.PermissionPurchaseRead), supplierController.GetSupplierByID)
+	suppliers.Post("/", middleware.RequirePermission(models.PermissionPurchaseManage), supplierController.CreateSupplier)
+	suppliers.Put("/:id", middleware.RequirePermission(models.PermissionPurchaseManage), supplierController.UpdateSupplier)
+	
+	purchaseOrders := protected.Group("/purchase-orders")
+	purchaseOrders.Get("/", middleware.RequirePermission(models.PermissionPurchaseRead), purchaseOrderController.GetAllPurchaseOrders)
+	purchaseOrders.Get("/on-order", middleware.RequirePermission(models.PermissionPurchaseRead), purchaseOrderController.GetOnOrder)
+	purchaseOrders.Get("/:id", middleware.RequirePermission(models.PermissionPurchaseRead), purchaseOrderController.GetPurchaseOrderByID)
+	purchaseOrders.Post("/", middleware.RequirePermission(models.PermissionPurchaseManage), purchaseOrderController.CreatePurchaseOrder)
+	purchaseOrders.Put("/:id", middleware.RequirePermission(models.PermissionPurchaseManage), purchaseOrderController.UpdatePurchaseOrder)
+	purchaseOrders.Post("/:id/approve", middleware.RequirePermission(models.PermissionPurchaseApprove), purchaseOrderController.ApprovePurchaseOrder)
+	purchaseOrders.Post("/:id/cancel", middleware.RequirePermission(models.PermissionPurchaseApprove), purchaseOrderController.CancelPurchaseOrder)
+	purchaseOrders.Post("/:id/close", middleware.RequirePermission(models.PermissionPurchaseApprove), purchaseOrderController.ClosePurchaseOrder)
+	purchaseOrders.Post("/:id/receive", middleware.RequirePermission(models.PermissionPurchaseReceive), idempotent, purchaseOrderController.ReceivePurchaseOrder)
+	
+	salesOrders := protected.Group("/sales-orders")
+	salesOrders.Get("/", middleware.RequirePermission(models.PermissionSalesRead), salesOrderController.GetAllSalesOrders)
+	salesOrders.Get("/:id", middleware.RequirePermission(models.PermissionSalesRead), salesOrderController.GetSalesOrderByID)
+	salesOrders.Post("/", middleware.RequirePermission(models.PermissionSalesManage), salesOrderController.CreateSalesOrder)
+	salesOrders.Post("/:id/confirm", middleware.RequirePermission(models.PermissionSalesManage), salesOrderController.ConfirmSalesOrder)
+	salesOrders.Post("/:id/cancel", middleware.RequirePermission(models.PermissionSalesManage), salesOrderController.CancelSalesOrder)
+	salesOrders.Post("/:id/pick", middleware.RequirePermission(models.PermissionSalesFulfil), idempotent, salesOrderController.PickSalesOrder)
+	salesOrders.Post("/:id/pack", middleware.RequirePermission(models.PermissionSalesFulfil), salesOrderController.PackSalesOrder)
+	salesOrders.Post("/:id/ship", middleware.RequirePermission(models.PermissionSalesFulfil), idempotent, salesOrderController.ShipSalesOrder)
+	
+	stocktakes := protected.Group("/stocktakes")
+	stocktakes.Get("/", middleware.RequirePermission(models.PermissionStocktakeCount), stocktakeController.GetAllStocktakes)
+	stocktakes.Get("/:id", middleware.RequirePermission(models.PermissionStocktakeCount), stocktakeController.GetStocktakeByID)
+	stocktakes.Get("/:id/variance", middleware.RequirePermission(models.PermissionStocktakeCount), stocktakeController.GetVariance)
+	stocktakes.Post("/", middleware.RequirePermission(models.PermissionStocktakeManage), stocktakeController.CreateStocktake)
+	stocktakes.Post("/:id/counts", middleware.RequirePermission(models.PermissionStocktakeCount), stocktakeController.SubmitCounts)
+	stocktakes.Post("/:id/approve", middleware.RequirePermission(models.PermissionStocktakeApprove), idempotent, stocktakeController.ApproveStocktake)
+	stocktakes.Post("/:id/cancel", middleware.RequirePermission(models.PermissionStocktakeManage), stocktakeController.CancelStocktake)
+	
+	webhooks := protected.Group("/webhooks", middleware.RequirePermission(models.PermissionWebhookManage))
+	webhooks.Get("/deliveries/:deliveryId", webhookController.GetDeliveryByID)
+	webhooks.Post("/deliveries/:deliveryId/replay", webhookController.ReplayDelivery)
+	webhooks.Get("/", webhookController.GetAllWebhooks)
+	webhooks.Post("/", webhookController.CreateWebhook)
+	webhooks.Get("/:id", webhookController.GetWebhookByID)
+	webhooks.Put("/:id", webhookController.UpdateWebhook)
+	webhooks.Delete("/:id", webhookController.DeleteWebhook)
+	webhooks.Get("/:id/deliveries", webhookController.GetDeliveries)
+	
+	protected.Get("/permissions", middleware.RequirePermission(models.PermissionRoleManage), roleController.GetAllPermissions)
+	
+	roles := protected.Group("/roles", middleware.RequirePermission(models.PermissionRoleManage))
+	roles.Get("/", roleController.GetAllRoles)
+	roles.Post("/", roleController.CreateRole)
+	roles.Get("/:id", roleController.GetRoleByID)
+	roles.Put("/:id", roleController.UpdateRole)
+	roles.Delete("/:id", roleController.DeleteRole)
+	
+	users := protected.Group("/users", middleware.RequirePermission(models.PermissionUserManage))
+	users.Get("/", userController.GetAllUsers)
+	users.Get("/:id", userController.GetUserByID)
+	users.Put("/:id", userController.UpdateUser)
+	users.Patch("/:id/activate", userController.ActivateUser)
+	users.Patch("/:id/deactivate", userController.DeactivateUser)
+	users.Post("/:id/reset-password", userController.ResetPassword)
 
 	log.Printf("Server starting on port %s", cfg.AppPort)
 	if err := app.Listen(cfg.AppPort); err != nil {
@@ -74,10 +227,40 @@ func runSeeders() {
 		log.Fatal("Database connection is not initialized!")
 	}
 	
+	roleSeeder := seeders.NewRoleSeeder(database.DB)
+	if err := roleSeeder.Run(); err != nil {
+		log.Printf("Warning: Role seeder failed: %v", err)
+	}
+	
+	unitSeeder := seeders.NewUnitSeeder(database.DB)
+	if err := unitSeeder.Run(); err != nil {
+		log.Printf("Warning: Unit seeder failed: %v", err)
+	}
+	
 	sampleSeeder := seeders.NewSampleDataSeeder(database.DB)
 	if err := sampleSeeder.Run(); err != nil {
 		log.Printf("Warning: Sample data seeder failed: %v", err)
 	}
 	
+	categorySeeder := seeders.NewCategorySeeder(database.DB)
+	if err := categorySeeder.Run(); err != nil {
+		log.Printf("Warning: Category seeder failed: %v", err)
+	}
+	
+	warehouseSeeder := seeders.NewWarehouseSeeder(database.DB)
+	if err := warehouseSeeder.Run(); err != nil {
+		log.Printf("Warning: Warehouse seeder failed: %v", err)
+	}
+	
+	stockLedgerSeeder := seeders.NewStockLedgerSeeder(database.DB)
+	if err := stockLedgerSeeder.Run(); err != nil {
+		log.Printf("Warning: Stock ledger seeder failed: %v", err)
+	}
+	
+	costLayerSeeder := seeders.NewCostLayerSeeder(database.DB)
+	if err := costLayerSeeder.Run(); err != nil {
+		log.Printf("Warning: Cost layer seeder failed: %v", err)
+	}
+	
 	log.Println("=== All seeders completed ===")
 }
\ No newline at end of file
@@ -16,8 +16,28 @@ type Config struct {
 	DBName     string
 	DBSSLMode  string
 	
-	JWTSecret    string
-	JWTExpireHours int
+	JWTSecret               string
+	JWTAccessExpireMinutes  int
+	RefreshTokenExpireHours int
+	
+	ReservationTTLHours int
+	
+	CostingMethod string
+	
+	LotExpiryWarningDays int
+	
+	WebhookMaxAttempts         int
+	WebhookTimeoutSeconds      int
+	WebhookBackoffSeconds      int
+	WebhookPollIntervalSeconds int
+	
+	OutboxSinks               string
+	OutboxFilePath            string
+	OutboxPollIntervalSeconds int
+	
+	IdempotencyTTLHours int
+	
+	RequireIfMatch bool
 }
 
 func LoadConfig() *Config {
@@ -32,8 +52,28 @@ func LoadConfig() *Config {
 		DBName:     getEnv("DB_NAME", "inventory_db"),
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 		
-		JWTSecret:     getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
-		JWTExpireHours: getEnvAsInt("JWT_EXPIRE_HOURS", 24),
+		JWTSecret:               getEnv("JWT_SECRET", "your-super-secret-jwt-key"),
+		JWTAccessExpireMinutes:  getEnvAsInt("JWT_ACCESS_EXPIRE_MINUTES", 15),
+		RefreshTokenExpireHours: getEnvAsInt("REFRESH_TOKEN_EXPIRE_HOURS", 720),
+		
+		ReservationTTLHours: getEnvAsInt("RESERVATION_TTL_HOURS", 48),
+		
+		CostingMethod: getEnv("COSTING_METHOD", "FIFO"),
+		
+		LotExpiryWarningDays: getEnvAsInt("LOT_EXPIRY_WARNING_DAYS", 30),
+		
+		WebhookMaxAttempts:         getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeoutSeconds:      getEnvAsInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+		WebhookBackoffSeconds:      getEnvAsInt("WEBHOOK_BACKOFF_SECONDS", 30),
+		WebhookPollIntervalSeconds: getEnvAsInt("WEBHOOK_POLL_INTERVAL_SECONDS", 5),
+		
+		OutboxSinks:               getEnv("OUTBOX_SINKS", "log,webhook,stream"),
+		OutboxFilePath:            getEnv("OUTBOX_FILE_PATH", "storage/outbox/events.jsonl"),
+		OutboxPollIntervalSeconds: getEnvAsInt("OUTBOX_POLL_INTERVAL_SECONDS", 2),
+		
+		IdempotencyTTLHours: getEnvAsInt("IDEMPOTENCY_TTL_HOURS", 24),
+		
+		RequireIfMatch: getEnvAsBool("REQUIRE_IF_MATCH", false),
 	}
 }
 
@@ -51,4 +91,13 @@ func getEnvAsInt(key string, defaultValue int) int {
 		}
 	}
 	return defaultValue
+}
+
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value, exists := os.LookupEnv(key); exists {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
 }
\ No newline at end of file
@@ -1,21 +1,31 @@
 package controllers
 
 import (
+	"bufio"
+	"errors"
+	"fmt"
+	"log"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"inventory-api/internal/export"
+	"inventory-api/internal/models"
 	"inventory-api/internal/services"
 )
 
 type ActivityController struct {
 	activityService *services.ActivityService
+	exportService   *services.ExportService
 	responseService *services.ResponseService
 }
 
 func NewActivityController() *ActivityController {
 	return &ActivityController{
 		activityService: services.NewActivityService(),
+		exportService:   services.NewExportService(),
 		responseService: services.NewResponseService(),
 	}
 }
@@ -23,9 +33,11 @@ func NewActivityController() *ActivityController {
 func (ctrl *ActivityController) GetAllActivities(c *fiber.Ctx) error {
 	page, _ := strconv.Atoi(c.Query("page", "1"))
 	limit, _ := strconv.Atoi(c.Query("limit", "20"))
-	activityType := c.Query("type", "")
-	itemID := c.Query("item_id", "")
-	userID := c.Query("user_id", "")
+	
+	filter, err := parseActivityFilter(c)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
 
 	if page < 1 {
 		page = 1
@@ -34,7 +46,7 @@ func (ctrl *ActivityController) GetAllActivities(c *fiber.Ctx) error {
 		limit = 20
 	}
 	
-	activities, total, err := ctrl.activityService.GetAllActivities(page, limit, activityType, itemID, userID)
+	activities, total, err := ctrl.activityService.GetAllActivities(page, limit, filter)
 	if err != nil {
 		return ctrl.responseService.InternalServerError(c, "Failed to fetch activities", err.Error())
 	}
@@ -48,4 +60,58 @@ func (ctrl *ActivityController) GetAllActivities(c *fiber.Ctx) error {
 		limit,
 		total,
 	)
+}
+
+// ExportActivities streams the activity log for the list filters as
+// format=csv|xlsx|jsonl|pdf. The filters are checked before streaming
+// starts; an error after that ends the file with an error row.
+func (ctrl *ActivityController) ExportActivities(c *fiber.Ctx) error {
+	format := c.Query("format", export.FormatCSV)
+	if !export.IsFormat(format) {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", "format must be csv, xlsx, jsonl or pdf")
+	}
+	
+	filter, err := parseActivityFilter(c)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	format = strings.Clone(format)
+	
+	c.Set("Content-Type", export.ContentType(format))
+	c.Attachment(fmt.Sprintf("activities-%s.%s", time.Now().Format("20060102"), format))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		err := ctrl.exportService.ExportActivities(w, format, filter)
+		if err != nil {
+			log.Printf("Activity export failed: %v", err)
+		}
+		w.Flush()
+	})
+	
+	return nil
+}
+
+// parseActivityFilter reads the list filters, including a from/to range on
+// the activity date. Query values point into the request buffer, so they are
+// copied for exports that read them after the handler has returned.
+func parseActivityFilter(c *fiber.Ctx) (*models.ActivityFilter, error) {
+	filter := &models.ActivityFilter{
+		Type:          strings.Clone(c.Query("type")),
+		ItemID:        strings.Clone(c.Query("item_id")),
+		UserID:        strings.Clone(c.Query("user_id")),
+		ReferenceType: strings.Clone(c.Query("reference_type")),
+		ReferenceID:   strings.Clone(c.Query("reference_id")),
+		LotNumber:     strings.Clone(c.Query("lot_number")),
+	}
+	
+	var err error
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseTimeQuery(c, "to"); err != nil {
+		return nil, err
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return nil, errors.New("from must not be after to")
+	}
+	return filter, nil
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type AttributeController struct {
+	attributeService *services.AttributeService
+	responseService  *services.ResponseService
+}
+
+func NewAttributeController() *AttributeController {
+	return &AttributeController{
+		attributeService: services.NewAttributeService(),
+		responseService:  services.NewResponseService(),
+	}
+}
+
+func (ctrl *AttributeController) GetAllAttributes(c *fiber.Ctx) error {
+	attributes, err := ctrl.attributeService.GetAllAttributes(c.Query("category"))
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch attributes", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Attributes retrieved successfully", fiber.Map{
+		"attributes": attributes,
+	})
+}
+
+func (ctrl *AttributeController) CreateAttribute(c *fiber.Ctx) error {
+	var req models.CreateAttributeDefinitionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Category == "" || req.Key == "" || req.Type == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Category, key and type are required")
+	}
+	
+	attribute, err := ctrl.attributeService.CreateAttribute(&req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create attribute", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Attribute created successfully", fiber.Map{
+		"attribute": attribute,
+	})
+}
+
+func (ctrl *AttributeController) DeleteAttribute(c *fiber.Ctx) error {
+	if err := ctrl.attributeService.DeleteAttribute(c.Params("id")); err != nil {
+		return ctrl.responseService.NotFound(c, "Attribute not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Attribute deleted successfully", nil)
+}
\ No newline at end of file
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 
 	"inventory-api/internal/config"
@@ -9,15 +11,17 @@ import (
 )
 
 type AuthController struct {
-	authService    *services.AuthService
-	config         *config.Config
+	authService     *services.AuthService
+	userService     *services.UserService
+	config          *config.Config
 	responseService *services.ResponseService
 }
 
 func NewAuthController(cfg *config.Config) *AuthController {
 	return &AuthController{
-		authService:    services.NewAuthService(cfg),
-		config:         cfg,
+		authService:     services.NewAuthService(cfg),
+		userService:     services.NewUserService(),
+		config:          cfg,
 		responseService: services.NewResponseService(),
 	}
 }
@@ -54,16 +58,65 @@ func (ctrl *AuthController) Login(c *fiber.Ctx) error {
 		return ctrl.responseService.BadRequest(c, "Validation failed", "Email and password are required")
 	}
 	
-	token, err := ctrl.authService.Login(&req)
+	tokens, err := ctrl.authService.Login(&req, c.Get(fiber.HeaderUserAgent), c.IP())
 	if err != nil {
 		return ctrl.responseService.Unauthorized(c, "Login failed", err.Error())
 	}
 	
 	return ctrl.responseService.Success(c, fiber.StatusOK, "Login successful", fiber.Map{
-		"token": token,
+		"token":                tokens.AccessToken,
+		"access_token":         tokens.AccessToken,
+		"refresh_token":        tokens.RefreshToken,
+		"token_type":           tokens.TokenType,
+		"expires_in":           tokens.ExpiresIn,
+		"must_change_password": tokens.MustChangePassword,
 	})
 }
 
+func (ctrl *AuthController) Refresh(c *fiber.Ctx) error {
+	var req models.RefreshTokenRequest
+	
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.RefreshToken == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Refresh token is required")
+	}
+	
+	tokens, err := ctrl.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		return ctrl.responseService.Unauthorized(c, "Token refresh failed", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Token refreshed successfully", tokens)
+}
+
+func (ctrl *AuthController) Logout(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	sessionID, _ := c.Locals("sessionID").(string)
+	tokenID, _ := c.Locals("tokenID").(string)
+	expiresAt, _ := c.Locals("tokenExpiresAt").(time.Time)
+	
+	if err := ctrl.authService.Logout(userID, sessionID, tokenID, expiresAt); err != nil {
+		return ctrl.responseService.InternalServerError(c, "Logout failed", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Logout successful", nil)
+}
+
+func (ctrl *AuthController) LogoutAll(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	tokenID, _ := c.Locals("tokenID").(string)
+	expiresAt, _ := c.Locals("tokenExpiresAt").(time.Time)
+	
+	if err := ctrl.authService.LogoutAll(userID, tokenID, expiresAt); err != nil {
+		return ctrl.responseService.InternalServerError(c, "Logout failed", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Logged out from all sessions", nil)
+}
+
 func (ctrl *AuthController) Profile(c *fiber.Ctx) error {
 	userID, ok := c.Locals("userID").(string)
 	if !ok || userID == "" {
@@ -78,4 +131,26 @@ func (ctrl *AuthController) Profile(c *fiber.Ctx) error {
 	return ctrl.responseService.Success(c, fiber.StatusOK, "Profile retrieved successfully", fiber.Map{
 		"user": user,
 	})
-}
\ No newline at end of file
+}
+
+func (ctrl *AuthController) ChangePassword(c *fiber.Ctx) error {
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "Invalid user session")
+	}
+	
+	var req models.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Current password and new password are required")
+	}
+	
+	if err := ctrl.userService.ChangePassword(userID, &req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to change password", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Password changed successfully", nil)
+}
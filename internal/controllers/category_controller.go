@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type CategoryController struct {
+	categoryService *services.CategoryService
+	responseService *services.ResponseService
+}
+
+func NewCategoryController() *CategoryController {
+	return &CategoryController{
+		categoryService: services.NewCategoryService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *CategoryController) GetCategoryTree(c *fiber.Ctx) error {
+	categories, err := ctrl.categoryService.GetCategoryTree()
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch categories", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Categories retrieved successfully", fiber.Map{
+		"categories": categories,
+	})
+}
+
+func (ctrl *CategoryController) GetCategoryByID(c *fiber.Ctx) error {
+	category, err := ctrl.categoryService.GetCategoryByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Category not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Category retrieved successfully", fiber.Map{
+		"category": category,
+	})
+}
+
+func (ctrl *CategoryController) CreateCategory(c *fiber.Ctx) error {
+	var req models.CreateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Name == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Name is required")
+	}
+	
+	category, err := ctrl.categoryService.CreateCategory(&req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create category", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Category created successfully", fiber.Map{
+		"category": category,
+	})
+}
+
+func (ctrl *CategoryController) UpdateCategory(c *fiber.Ctx) error {
+	var req models.UpdateCategoryRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	category, err := ctrl.categoryService.UpdateCategory(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update category", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Category updated successfully", fiber.Map{
+		"category": category,
+	})
+}
+
+// DeleteCategory takes an optional move_items_to category ID, which moves
+// the category's items there first, merging the two.
+func (ctrl *CategoryController) DeleteCategory(c *fiber.Ctx) error {
+	if err := ctrl.categoryService.DeleteCategory(c.Params("id"), c.Query("move_items_to")); err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to delete category", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Category deleted successfully", nil)
+}
\ No newline at end of file
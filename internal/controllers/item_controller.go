@@ -1,10 +1,19 @@
 package controllers
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"inventory-api/internal/config"
+	"inventory-api/internal/export"
 	"inventory-api/internal/models"
 	"inventory-api/internal/services"
 )
@@ -12,14 +21,18 @@ import (
 type ItemController struct {
 	itemService     *services.ItemService
 	activityService *services.ActivityService
+	exportService   *services.ExportService
 	responseService *services.ResponseService
+	requireIfMatch  bool
 }
 
-func NewItemController() *ItemController {
+func NewItemController(cfg *config.Config) *ItemController {
 	return &ItemController{
-		itemService:     services.NewItemService(),
+		itemService:     services.NewItemService(cfg),
 		activityService: services.NewActivityService(),
+		exportService:   services.NewExportService(),
 		responseService: services.NewResponseService(),
+		requireIfMatch:  cfg.RequireIfMatch,
 	}
 }
 
@@ -33,7 +46,7 @@ func (ctrl *ItemController) CreateItem(c *fiber.Ctx) error {
 	if req.Name == "" {
 		return ctrl.responseService.BadRequest(c, "Validation failed", "Item name is required")
 	}
-
+	
 	fmt.Println("Item name:", req.Name)
 	
 	userID := c.Locals("userID")
@@ -55,15 +68,112 @@ func (ctrl *ItemController) CreateItem(c *fiber.Ctx) error {
 }
 
 func (ctrl *ItemController) GetAllItems(c *fiber.Ctx) error {
-	items, err := ctrl.itemService.GetAllItems()
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	filter, err := ctrl.parseItemFilter(c)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	
+	items, total, err := ctrl.itemService.GetAllItems(filter, page, limit)
 	if err != nil {
 		return ctrl.responseService.InternalServerError(c, "Failed to fetch items", err.Error())
 	}
 	
-	return ctrl.responseService.Success(c, fiber.StatusOK, "Items retrieved successfully", fiber.Map{
-		"items": items,
-		"count": len(items),
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Items retrieved successfully",
+		items,
+		page,
+		limit,
+		total,
+	)
+}
+
+// ExportItems streams the items matching the list filters as
+// format=csv|xlsx|jsonl|pdf.
+func (ctrl *ItemController) ExportItems(c *fiber.Ctx) error {
+	format := c.Query("format", export.FormatCSV)
+	if !export.IsFormat(format) {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", "format must be csv, xlsx, jsonl or pdf")
+	}
+	
+	filter, err := ctrl.parseItemFilter(c)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	
+	// Query values point into the request buffer, so copy the ones the
+	// stream writer uses after this handler has returned.
+	filter.Category = strings.Clone(filter.Category)
+	filter.Location = strings.Clone(filter.Location)
+	filter.Search = strings.Clone(filter.Search)
+	filter.ParentID = strings.Clone(filter.ParentID)
+	filter.CategoryID = strings.Clone(filter.CategoryID)
+	format = strings.Clone(format)
+	
+	c.Set("Content-Type", export.ContentType(format))
+	c.Attachment(fmt.Sprintf("items-%s.%s", time.Now().Format("20060102"), format))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := ctrl.exportService.ExportItems(w, format, filter); err != nil {
+			log.Printf("Item export failed: %v", err)
+		}
+		w.Flush()
+	})
+	
+	return nil
+}
+
+func (ctrl *ItemController) parseItemFilter(c *fiber.Ctx) (*models.ItemFilter, error) {
+	filter := &models.ItemFilter{
+		Category:   c.Query("category"),
+		CategoryID: c.Query("category_id"),
+		Location:   c.Query("location"),
+		Search:     strings.TrimSpace(c.Query("search")),
+		LowStock:   c.QueryBool("low_stock", false),
+		ParentID:   c.Query("parent_id"),
+	}
+	
+	// Custom attributes are filtered as attr.<key>=<value>. The values are
+	// copied out of the request buffer.
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		name := string(key)
+		if !strings.HasPrefix(name, "attr.") {
+			return
+		}
+		if filter.Attributes == nil {
+			filter.Attributes = make(map[string]string)
+		}
+		filter.Attributes[strings.TrimPrefix(name, "attr.")] = string(value)
 	})
+	
+	var err error
+	if filter.PriceMin, err = parseFloatQuery(c, "price_min"); err != nil {
+		return nil, err
+	}
+	if filter.PriceMax, err = parseFloatQuery(c, "price_max"); err != nil {
+		return nil, err
+	}
+	if filter.StockMin, err = parseIntQuery(c, "stock_min"); err != nil {
+		return nil, err
+	}
+	if filter.StockMax, err = parseIntQuery(c, "stock_max"); err != nil {
+		return nil, err
+	}
+	if filter.Sort, err = ctrl.itemService.ParseItemSort(c.Query("sort")); err != nil {
+		return nil, err
+	}
+	
+	return filter, nil
 }
 
 func (ctrl *ItemController) GetItemByID(c *fiber.Ctx) error {
@@ -74,6 +184,7 @@ func (ctrl *ItemController) GetItemByID(c *fiber.Ctx) error {
 		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
 	}
 	
+	c.Set(fiber.HeaderETag, item.ETag())
 	return ctrl.responseService.Success(c, fiber.StatusOK, "Item retrieved successfully", fiber.Map{
 		"item": item,
 	})
@@ -102,7 +213,15 @@ func (ctrl *ItemController) UpdateItem(c *fiber.Ctx) error {
 		return ctrl.responseService.Unauthorized(c, "Invalid user session", "Invalid user ID format")
 	}
 	
-	updatedItem, err := ctrl.itemService.UpdateItem(id, &req, userIDStr)
+	version, err := ctrl.ifMatchVersion(c)
+	if err != nil {
+		return ctrl.ifMatchError(c, err)
+	}
+	
+	updatedItem, err := ctrl.itemService.UpdateItem(id, &req, userIDStr, version)
+	if errors.Is(err, models.ErrVersionConflict) {
+		return ctrl.versionConflict(c, id)
+	}
 	if err != nil {
 		return ctrl.responseService.BadRequest(c, "Failed to update item", err.Error())
 	}
@@ -133,6 +252,7 @@ func (ctrl *ItemController) UpdateItem(c *fiber.Ctx) error {
 		}
 	}
 	
+	c.Set(fiber.HeaderETag, updatedItem.ETag())
 	return ctrl.responseService.Success(c, fiber.StatusOK, "Item updated successfully", fiber.Map{
 		"item":    updatedItem,
 		"changes": changes,
@@ -165,11 +285,20 @@ func (ctrl *ItemController) UpdateStock(c *fiber.Ctx) error {
 		return ctrl.responseService.Unauthorized(c, "Invalid user session", "Invalid user ID format")
 	}
 	
-	updatedItem, err := ctrl.itemService.UpdateStock(id, &req, userIDStr)
+	version, err := ctrl.ifMatchVersion(c)
+	if err != nil {
+		return ctrl.ifMatchError(c, err)
+	}
+	
+	updatedItem, err := ctrl.itemService.UpdateStock(id, &req, userIDStr, version)
+	if errors.Is(err, models.ErrVersionConflict) {
+		return ctrl.versionConflict(c, id)
+	}
 	if err != nil {
 		return ctrl.responseService.BadRequest(c, "Failed to update stock", err.Error())
 	}
 	
+	c.Set(fiber.HeaderETag, updatedItem.ETag())
 	return ctrl.responseService.Success(c, fiber.StatusOK, "Stock updated successfully", fiber.Map{
 		"item": updatedItem,
 	})
@@ -193,7 +322,15 @@ func (ctrl *ItemController) DeleteItem(c *fiber.Ctx) error {
 		return ctrl.responseService.Unauthorized(c, "Invalid user session", "Invalid user ID format")
 	}
 	
-	err = ctrl.itemService.DeleteItem(id, userIDStr)
+	version, err := ctrl.ifMatchVersion(c)
+	if err != nil {
+		return ctrl.ifMatchError(c, err)
+	}
+	
+	err = ctrl.itemService.DeleteItem(id, userIDStr, version)
+	if errors.Is(err, models.ErrVersionConflict) {
+		return ctrl.versionConflict(c, id)
+	}
 	if err != nil {
 		return ctrl.responseService.BadRequest(c, "Failed to delete item", err.Error())
 	}
@@ -204,4 +341,223 @@ func (ctrl *ItemController) DeleteItem(c *fiber.Ctx) error {
 			"name": item.Name,
 		},
 	})
-}
\ No newline at end of file
+}
+func (ctrl *ItemController) GetItemStock(c *fiber.Ctx) error {
+	id := c.Params("id")
+	
+	stocks, err := ctrl.itemService.GetItemStockLevels(id)
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stock levels retrieved successfully", fiber.Map{
+		"stocks": stocks,
+	})
+}
+
+func (ctrl *ItemController) GetCostLayers(c *fiber.Ctx) error {
+	layers, err := ctrl.itemService.GetCostLayers(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Cost layers retrieved successfully", fiber.Map{
+		"layers": layers,
+	})
+}
+
+func (ctrl *ItemController) GetItemVariants(c *fiber.Ctx) error {
+	variants, err := ctrl.itemService.GetItemVariants(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Item variants retrieved successfully", fiber.Map{
+		"variants": variants,
+	})
+}
+
+func (ctrl *ItemController) GetItemUnits(c *fiber.Ctx) error {
+	units, err := ctrl.itemService.GetItemUnits(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Item units retrieved successfully", units)
+}
+
+func (ctrl *ItemController) SetItemUnits(c *fiber.Ctx) error {
+	var req models.SetItemUnitsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	units, err := ctrl.itemService.SetItemUnits(c.Params("id"), &req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update item units", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Item units updated successfully", units)
+}
+
+// GetKit returns the item's bill of materials with its available-to-build
+// count, optionally at one warehouse_id and bin_id.
+func (ctrl *ItemController) GetKit(c *fiber.Ctx) error {
+	kit, err := ctrl.itemService.GetKit(c.Params("id"), c.Query("warehouse_id"), c.Query("bin_id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to fetch kit", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Kit retrieved successfully", kit)
+}
+
+func (ctrl *ItemController) SetKitComponents(c *fiber.Ctx) error {
+	var req models.SetKitComponentsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	kit, err := ctrl.itemService.SetKitComponents(c.Params("id"), &req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update kit", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Kit updated successfully", kit)
+}
+
+func (ctrl *ItemController) AssembleKit(c *fiber.Ctx) error {
+	return ctrl.buildKit(c, models.KitBuildAssemble)
+}
+
+func (ctrl *ItemController) DisassembleKit(c *fiber.Ctx) error {
+	return ctrl.buildKit(c, models.KitBuildDisassemble)
+}
+
+func (ctrl *ItemController) buildKit(c *fiber.Ctx, action models.KitBuildAction) error {
+	id := c.Params("id")
+	
+	var req models.KitBuildRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Quantity <= 0 {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Quantity must be greater than 0")
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	build := ctrl.itemService.AssembleKit
+	message := "Kit assembled successfully"
+	if action == models.KitBuildDisassemble {
+		build = ctrl.itemService.DisassembleKit
+		message = "Kit disassembled successfully"
+	}
+	
+	result, err := build(id, &req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to "+string(action)+" kit", err.Error())
+	}
+	
+	item, err := ctrl.itemService.GetItemByID(id)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch item", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, message, fiber.Map{
+		"item":  item,
+		"build": result,
+	})
+}
+
+func (ctrl *ItemController) TransferStock(c *fiber.Ctx) error {
+	id := c.Params("id")
+	
+	var req models.TransferStockRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Quantity <= 0 {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Quantity must be greater than 0")
+	}
+	
+	if req.FromWarehouseID == "" || req.ToWarehouseID == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Source and destination warehouses are required")
+	}
+	
+	userIDStr, ok := c.Locals("userID").(string)
+	if !ok || userIDStr == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	item, transferID, err := ctrl.itemService.TransferStock(id, &req, userIDStr)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to transfer stock", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stock transferred successfully", fiber.Map{
+		"item":        item,
+		"transfer_id": transferID,
+	})
+}
+
+// ImportItems accepts a multipart upload with a "file" part (.csv or .xlsx)
+// and optional "mapping" (JSON object of field to column header),
+// "dry_run", "upsert" and "warehouse_id" form values.
+func (ctrl *ItemController) ImportItems(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "A file is required")
+	}
+	
+	opts := models.ItemImportOptions{
+		DryRun:      c.FormValue("dry_run") == "true",
+		Upsert:      c.FormValue("upsert") == "true",
+		WarehouseID: c.FormValue("warehouse_id"),
+	}
+	if mapping := c.FormValue("mapping"); mapping != "" {
+		if err := json.Unmarshal([]byte(mapping), &opts.Mapping); err != nil {
+			return ctrl.responseService.BadRequest(c, "Invalid mapping", err.Error())
+		}
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	file, err := fileHeader.Open()
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to read file", err.Error())
+	}
+	defer file.Close()
+	
+	result, err := ctrl.itemService.ImportItems(fileHeader.Filename, file, &opts, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to import items", err.Error())
+	}
+	
+	if len(result.Errors) > 0 && !result.DryRun {
+		return ctrl.responseService.ValidationError(c, "Import rejected, no rows were written", result)
+	}
+	
+	message := "Items imported successfully"
+	if result.DryRun {
+		message = "Dry run completed"
+	}
+	return ctrl.responseService.Success(c, fiber.StatusOK, message, result)
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type LotController struct {
+	lotService      *services.LotService
+	responseService *services.ResponseService
+}
+
+func NewLotController(cfg *config.Config) *LotController {
+	return &LotController{
+		lotService:      services.NewLotService(cfg),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *LotController) GetItemLots(c *fiber.Ctx) error {
+	lots, err := ctrl.lotService.GetItemLots(c.Params("id"), c.QueryBool("include_empty", false))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Lots retrieved successfully", fiber.Map{
+		"lots": lots,
+	})
+}
+
+// GetExpiringLots lists lots expiring within ?days= days, defaulting to
+// LOT_EXPIRY_WARNING_DAYS. Quarantined lots are left out unless asked for.
+func (ctrl *LotController) GetExpiringLots(c *fiber.Ctx) error {
+	days := -1
+	if raw := c.Query("days"); raw != "" {
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 0 {
+			return ctrl.responseService.BadRequest(c, "Invalid query parameter", "days must be a non-negative integer")
+		}
+		days = value
+	}
+	
+	lots, err := ctrl.lotService.GetExpiring(days, c.Query("warehouse_id", ""), c.QueryBool("include_quarantined", false))
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch expiring lots", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Expiring lots retrieved successfully", fiber.Map{
+		"lots": lots,
+	})
+}
+
+func (ctrl *LotController) QuarantineLot(c *fiber.Ctx) error {
+	var req models.QuarantineLotRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+		}
+	}
+	
+	userID, _ := c.Locals("userID").(string)
+	
+	lot, err := ctrl.lotService.QuarantineLot(c.Params("id"), req.Reason, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to quarantine lot", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Lot quarantined successfully", fiber.Map{
+		"lot": lot,
+	})
+}
+
+func (ctrl *LotController) ReleaseLot(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	
+	lot, err := ctrl.lotService.ReleaseLot(c.Params("id"), userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to release lot", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Lot released successfully", fiber.Map{
+		"lot": lot,
+	})
+}
\ No newline at end of file
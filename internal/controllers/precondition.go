@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+)
+
+var errIfMatchRequired = errors.New("If-Match header with the item's ETag is required")
+
+// ifMatchVersion reads the item version a write is conditional on from the
+// If-Match header, which carries an ETag from GET /items/:id. "*" matches
+// any version and yields 0, as does a missing header unless the config
+// requires one.
+func (ctrl *ItemController) ifMatchVersion(c *fiber.Ctx) (int, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" {
+		if ctrl.requireIfMatch {
+			return 0, errIfMatchRequired
+		}
+		return 0, nil
+	}
+	if header == "*" {
+		return 0, nil
+	}
+	
+	tag := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+	version, err := strconv.Atoi(tag)
+	if err != nil || version < 1 {
+		return 0, errors.New("If-Match must be a single item ETag or *")
+	}
+	return version, nil
+}
+
+// ifMatchError answers a write whose If-Match header is missing (428) or
+// unreadable (400).
+func (ctrl *ItemController) ifMatchError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, errIfMatchRequired) {
+		return ctrl.responseService.Error(c, fiber.StatusPreconditionRequired, "Precondition required", err.Error())
+	}
+	return ctrl.responseService.BadRequest(c, "Invalid If-Match header", err.Error())
+}
+
+// versionConflict answers a write made against a stale version with the item
+// as it is now, so the client can merge and retry.
+func (ctrl *ItemController) versionConflict(c *fiber.Ctx, id string) error {
+	item, err := ctrl.itemService.GetItemByID(id)
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	c.Set(fiber.HeaderETag, item.ETag())
+	return ctrl.responseService.PreconditionFailed(c, "Item has been modified", fiber.Map{
+		"item": item,
+	}, models.ErrVersionConflict.Error())
+}
\ No newline at end of file
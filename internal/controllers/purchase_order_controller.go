@@ -0,0 +1,175 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type PurchaseOrderController struct {
+	orderService    *services.PurchaseOrderService
+	responseService *services.ResponseService
+}
+
+func NewPurchaseOrderController(cfg *config.Config) *PurchaseOrderController {
+	return &PurchaseOrderController{
+		orderService:    services.NewPurchaseOrderService(cfg),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *PurchaseOrderController) GetAllPurchaseOrders(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	status := c.Query("status", "")
+	supplierID := c.Query("supplier_id", "")
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	orders, total, err := ctrl.orderService.GetAllPurchaseOrders(page, limit, status, supplierID)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch purchase orders", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Purchase orders retrieved successfully",
+		orders,
+		page,
+		limit,
+		total,
+	)
+}
+
+func (ctrl *PurchaseOrderController) GetPurchaseOrderByID(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.GetPurchaseOrderByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Purchase order not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Purchase order retrieved successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
+
+func (ctrl *PurchaseOrderController) GetOnOrder(c *fiber.Ctx) error {
+	rows, err := ctrl.orderService.GetOnOrder(c.Query("item_id"))
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch on-order quantities", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "On-order quantities retrieved successfully", fiber.Map{
+		"items": rows,
+	})
+}
+
+func (ctrl *PurchaseOrderController) CreatePurchaseOrder(c *fiber.Ctx) error {
+	var req models.CreatePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.SupplierID == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Supplier is required")
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	order, err := ctrl.orderService.CreatePurchaseOrder(&req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create purchase order", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Purchase order created successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
+
+func (ctrl *PurchaseOrderController) UpdatePurchaseOrder(c *fiber.Ctx) error {
+	var req models.CreatePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	order, err := ctrl.orderService.UpdatePurchaseOrder(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update purchase order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Purchase order updated successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
+
+func (ctrl *PurchaseOrderController) ApprovePurchaseOrder(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	
+	order, err := ctrl.orderService.ApprovePurchaseOrder(c.Params("id"), userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to approve purchase order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Purchase order approved successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
+
+func (ctrl *PurchaseOrderController) CancelPurchaseOrder(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.CancelPurchaseOrder(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to cancel purchase order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Purchase order cancelled successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
+
+func (ctrl *PurchaseOrderController) ClosePurchaseOrder(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.ClosePurchaseOrder(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to close purchase order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Purchase order closed successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
+
+func (ctrl *PurchaseOrderController) ReceivePurchaseOrder(c *fiber.Ctx) error {
+	var req models.ReceivePurchaseOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if len(req.Lines) == 0 {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "At least one line is required")
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	order, err := ctrl.orderService.ReceivePurchaseOrder(c.Params("id"), &req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to receive purchase order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Goods received successfully", fiber.Map{
+		"purchase_order": order,
+	})
+}
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type ReportController struct {
+	valuationService *services.ValuationService
+	responseService  *services.ResponseService
+}
+
+func NewReportController() *ReportController {
+	return &ReportController{
+		valuationService: services.NewValuationService(),
+		responseService:  services.NewResponseService(),
+	}
+}
+
+func (ctrl *ReportController) GetValuation(c *fiber.Ctx) error {
+	filter := &models.ValuationFilter{
+		GroupBy:     c.Query("group_by", models.ValuationGroupCategory),
+		AsOf:        time.Now(),
+		WarehouseID: c.Query("warehouse_id"),
+		Category:    c.Query("category"),
+	}
+	
+	asOf, err := parseTimeQuery(c, "as_of")
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	if asOf != nil {
+		filter.AsOf = *asOf
+	}
+	if filter.From, err = parseTimeQuery(c, "from"); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	
+	valuation, err := ctrl.valuationService.GetValuation(filter)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to build valuation report", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Valuation retrieved successfully", valuation)
+}
\ No newline at end of file
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type RoleController struct {
+	roleService     *services.RoleService
+	responseService *services.ResponseService
+}
+
+func NewRoleController() *RoleController {
+	return &RoleController{
+		roleService:     services.NewRoleService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *RoleController) GetAllRoles(c *fiber.Ctx) error {
+	roles, err := ctrl.roleService.GetAllRoles()
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch roles", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Roles retrieved successfully", fiber.Map{
+		"roles": roles,
+	})
+}
+
+func (ctrl *RoleController) GetRoleByID(c *fiber.Ctx) error {
+	role, err := ctrl.roleService.GetRoleByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Role not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Role retrieved successfully", fiber.Map{
+		"role": role,
+	})
+}
+
+func (ctrl *RoleController) CreateRole(c *fiber.Ctx) error {
+	var req models.CreateRoleRequest
+	
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Name == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Role name is required")
+	}
+	
+	role, err := ctrl.roleService.CreateRole(&req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create role", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Role created successfully", fiber.Map{
+		"role": role,
+	})
+}
+
+func (ctrl *RoleController) UpdateRole(c *fiber.Ctx) error {
+	var req models.UpdateRoleRequest
+	
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	role, err := ctrl.roleService.UpdateRole(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update role", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Role updated successfully", fiber.Map{
+		"role": role,
+	})
+}
+
+func (ctrl *RoleController) DeleteRole(c *fiber.Ctx) error {
+	if err := ctrl.roleService.DeleteRole(c.Params("id")); err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to delete role", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Role deleted successfully", nil)
+}
+
+func (ctrl *RoleController) GetAllPermissions(c *fiber.Ctx) error {
+	permissions, err := ctrl.roleService.GetAllPermissions()
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch permissions", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Permissions retrieved successfully", fiber.Map{
+		"permissions": permissions,
+	})
+}
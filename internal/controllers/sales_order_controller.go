@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type SalesOrderController struct {
+	orderService    *services.SalesOrderService
+	responseService *services.ResponseService
+}
+
+func NewSalesOrderController(cfg *config.Config) *SalesOrderController {
+	return &SalesOrderController{
+		orderService:    services.NewSalesOrderService(cfg),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *SalesOrderController) GetAllSalesOrders(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	status := c.Query("status", "")
+	search := c.Query("search", "")
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	orders, total, err := ctrl.orderService.GetAllSalesOrders(page, limit, status, search)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch sales orders", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Sales orders retrieved successfully",
+		orders,
+		page,
+		limit,
+		total,
+	)
+}
+
+func (ctrl *SalesOrderController) GetSalesOrderByID(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.GetSalesOrderByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Sales order not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Sales order retrieved successfully", fiber.Map{
+		"sales_order": order,
+	})
+}
+
+func (ctrl *SalesOrderController) GetItemReservations(c *fiber.Ctx) error {
+	reservations, err := ctrl.orderService.GetItemReservations(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Reservations retrieved successfully", fiber.Map{
+		"reservations": reservations,
+	})
+}
+
+func (ctrl *SalesOrderController) CreateSalesOrder(c *fiber.Ctx) error {
+	var req models.CreateSalesOrderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.CustomerName == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Customer name is required")
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	order, err := ctrl.orderService.CreateSalesOrder(&req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create sales order", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Sales order created successfully", fiber.Map{
+		"sales_order": order,
+	})
+}
+
+func (ctrl *SalesOrderController) ConfirmSalesOrder(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.ConfirmSalesOrder(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to confirm sales order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Sales order confirmed and stock reserved", fiber.Map{
+		"sales_order": order,
+	})
+}
+
+func (ctrl *SalesOrderController) PickSalesOrder(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.PickSalesOrder(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to pick sales order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Sales order picked successfully", fiber.Map{
+		"sales_order": order,
+	})
+}
+
+func (ctrl *SalesOrderController) PackSalesOrder(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.PackSalesOrder(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to pack sales order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Sales order packed successfully", fiber.Map{
+		"sales_order": order,
+	})
+}
+
+func (ctrl *SalesOrderController) ShipSalesOrder(c *fiber.Ctx) error {
+	var req models.ShipSalesOrderRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+		}
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	order, err := ctrl.orderService.ShipSalesOrder(c.Params("id"), &req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to ship sales order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Sales order shipped successfully", fiber.Map{
+		"sales_order": order,
+	})
+}
+
+func (ctrl *SalesOrderController) CancelSalesOrder(c *fiber.Ctx) error {
+	order, err := ctrl.orderService.CancelSalesOrder(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to cancel sales order", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Sales order cancelled successfully", fiber.Map{
+		"sales_order": order,
+	})
+}
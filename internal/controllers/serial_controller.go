@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type SerialController struct {
+	serialService   *services.SerialService
+	responseService *services.ResponseService
+}
+
+func NewSerialController() *SerialController {
+	return &SerialController{
+		serialService:   services.NewSerialService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *SerialController) GetItemSerials(c *fiber.Ctx) error {
+	status := c.Query("status", "")
+	if status != "" && status != string(models.SerialStatusInStock) && status != string(models.SerialStatusOut) {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", "status must be in_stock or out")
+	}
+	
+	serials, err := ctrl.serialService.GetItemSerials(c.Params("id"), status)
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Serial numbers retrieved successfully", fiber.Map{
+		"serials": serials,
+	})
+}
+
+// LookupSerial returns the unit (or units, if items share a number) with the
+// given serial, including its movement history.
+func (ctrl *SerialController) LookupSerial(c *fiber.Ctx) error {
+	serials, err := ctrl.serialService.LookupSerial(c.Params("serial"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Serial number not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Serial number retrieved successfully", fiber.Map{
+		"serials": serials,
+	})
+}
\ No newline at end of file
@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type StockAlertController struct {
+	alertService    *services.StockAlertService
+	responseService *services.ResponseService
+}
+
+func NewStockAlertController() *StockAlertController {
+	return &StockAlertController{
+		alertService:    services.NewStockAlertService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *StockAlertController) GetAllAlerts(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	status := c.Query("status", string(models.StockAlertStatusOpen))
+	alertType := c.Query("type", "")
+	itemID := c.Query("item_id", "")
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if status == "all" {
+		status = ""
+	}
+	
+	alerts, total, err := ctrl.alertService.GetAllAlerts(page, limit, status, alertType, itemID)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch alerts", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Alerts retrieved successfully",
+		alerts,
+		page,
+		limit,
+		total,
+	)
+}
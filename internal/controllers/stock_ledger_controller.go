@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type StockLedgerController struct {
+	ledgerService   *services.StockLedgerService
+	responseService *services.ResponseService
+}
+
+func NewStockLedgerController() *StockLedgerController {
+	return &StockLedgerController{
+		ledgerService:   services.NewStockLedgerService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *StockLedgerController) GetMovements(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	from, err := parseTimeQuery(c, "from")
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	
+	movements, total, err := ctrl.ledgerService.GetMovements(c.Params("id"), page, limit, from, to)
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Stock movements retrieved successfully",
+		movements,
+		page,
+		limit,
+		total,
+	)
+}
+
+func (ctrl *StockLedgerController) GetStockAsOf(c *fiber.Ctx) error {
+	asOf := time.Now()
+	at, err := parseTimeQuery(c, "at")
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid query parameter", err.Error())
+	}
+	if at != nil {
+		asOf = *at
+	}
+	
+	stock, err := ctrl.ledgerService.GetStockAsOf(c.Params("id"), asOf)
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stock retrieved successfully", stock)
+}
+
+func (ctrl *StockLedgerController) ReconcileItem(c *fiber.Ctx) error {
+	result, err := ctrl.ledgerService.ReconcileItem(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Item not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stock reconciled successfully", result)
+}
+
+func (ctrl *StockLedgerController) ReconcileAll(c *fiber.Ctx) error {
+	onlyDrifting := !c.QueryBool("all", false)
+	
+	results, err := ctrl.ledgerService.ReconcileAll(onlyDrifting)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to reconcile stock", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stock reconciled successfully", fiber.Map{
+		"items":          results,
+		"drifting_count": countDrifting(results),
+	})
+}
+
+func countDrifting(results []models.StockReconciliation) int {
+	count := 0
+	for _, result := range results {
+		if !result.InSync {
+			count++
+		}
+	}
+	return count
+}
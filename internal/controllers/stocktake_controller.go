@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type StocktakeController struct {
+	stocktakeService *services.StocktakeService
+	responseService  *services.ResponseService
+}
+
+func NewStocktakeController(cfg *config.Config) *StocktakeController {
+	return &StocktakeController{
+		stocktakeService: services.NewStocktakeService(cfg),
+		responseService:  services.NewResponseService(),
+	}
+}
+
+func (ctrl *StocktakeController) GetAllStocktakes(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	status := c.Query("status", "")
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	stocktakes, total, err := ctrl.stocktakeService.GetAllStocktakes(page, limit, status)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch stocktakes", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Stocktakes retrieved successfully",
+		stocktakes,
+		page,
+		limit,
+		total,
+	)
+}
+
+func (ctrl *StocktakeController) GetStocktakeByID(c *fiber.Ctx) error {
+	stocktake, err := ctrl.stocktakeService.GetStocktakeByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Stocktake not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stocktake retrieved successfully", fiber.Map{
+		"stocktake": stocktake,
+	})
+}
+
+func (ctrl *StocktakeController) CreateStocktake(c *fiber.Ctx) error {
+	var req models.CreateStocktakeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	stocktake, err := ctrl.stocktakeService.CreateStocktake(&req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create stocktake", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Stocktake created successfully", fiber.Map{
+		"stocktake": stocktake,
+	})
+}
+
+func (ctrl *StocktakeController) SubmitCounts(c *fiber.Ctx) error {
+	var req models.SubmitStocktakeCountsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if len(req.Counts) == 0 {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "At least one count is required")
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	stocktake, err := ctrl.stocktakeService.SubmitCounts(c.Params("id"), &req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to submit counts", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Counts recorded successfully", fiber.Map{
+		"stocktake": stocktake,
+	})
+}
+
+func (ctrl *StocktakeController) GetVariance(c *fiber.Ctx) error {
+	report, err := ctrl.stocktakeService.GetVariance(c.Params("id"), c.QueryBool("only_variances", false))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Stocktake not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Variance report retrieved successfully", report)
+}
+
+func (ctrl *StocktakeController) ApproveStocktake(c *fiber.Ctx) error {
+	userID, _ := c.Locals("userID").(string)
+	
+	stocktake, err := ctrl.stocktakeService.ApproveStocktake(c.Params("id"), userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to approve stocktake", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stocktake approved and adjustments posted", fiber.Map{
+		"stocktake": stocktake,
+	})
+}
+
+func (ctrl *StocktakeController) CancelStocktake(c *fiber.Ctx) error {
+	stocktake, err := ctrl.stocktakeService.CancelStocktake(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to cancel stocktake", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Stocktake cancelled successfully", fiber.Map{
+		"stocktake": stocktake,
+	})
+}
\ No newline at end of file
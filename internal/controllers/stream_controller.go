@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/services"
+	"inventory-api/internal/stream"
+)
+
+const streamHeartbeatInterval = 15 * time.Second
+
+type StreamController struct {
+	streamService    *services.StreamService
+	responseService  *services.ResponseService
+	websocketHandler fiber.Handler
+}
+
+func NewStreamController() *StreamController {
+	ctrl := &StreamController{
+		streamService:   services.NewStreamService(),
+		responseService: services.NewResponseService(),
+	}
+	ctrl.websocketHandler = websocket.New(ctrl.serveWebSocket)
+	return ctrl
+}
+
+// Stream serves /api/stream as a WebSocket when the client asks for an
+// upgrade and as Server-Sent Events otherwise. Both accept the item_id,
+// category and type filters and resume from Last-Event-ID.
+func (ctrl *StreamController) Stream(c *fiber.Ctx) error {
+	if websocket.IsWebSocketUpgrade(c) {
+		return ctrl.websocketHandler(c)
+	}
+	
+	lastEventID := c.Get("Last-Event-ID", c.Query("last_event_id"))
+	filter := stream.ParseFilter(c.Query("item_id"), c.Query("category"), c.Query("type"))
+	
+	sub, replay, err := ctrl.streamService.Subscribe(filter, lastEventID)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to open stream", err.Error())
+	}
+	
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+	
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer ctrl.streamService.Unsubscribe(sub)
+		
+		seen := make(map[string]bool, len(replay))
+		for _, event := range replay {
+			seen[event.ID] = true
+			if err := writeServerSentEvent(w, event); err != nil {
+				return
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+		
+		heartbeat := time.NewTicker(streamHeartbeatInterval)
+		defer heartbeat.Stop()
+		
+		for {
+			select {
+			case event, ok := <-sub.Events:
+				if !ok {
+					return
+				}
+				if seen[event.ID] {
+					continue
+				}
+				if err := writeServerSentEvent(w, event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := w.WriteString(": ping\n\n"); err != nil {
+					return
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	
+	return nil
+}
+
+func writeServerSentEvent(w *bufio.Writer, event *stream.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
+
+func (ctrl *StreamController) serveWebSocket(conn *websocket.Conn) {
+	lastEventID := conn.Headers("Last-Event-ID", conn.Query("last_event_id"))
+	filter := stream.ParseFilter(conn.Query("item_id"), conn.Query("category"), conn.Query("type"))
+	
+	sub, replay, err := ctrl.streamService.Subscribe(filter, lastEventID)
+	if err != nil {
+		conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "failed to open stream"))
+		return
+	}
+	defer ctrl.streamService.Unsubscribe(sub)
+	
+	// The client never sends anything we act on, but reading is how a
+	// close frame or a dropped connection is noticed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	
+	seen := make(map[string]bool, len(replay))
+	for _, event := range replay {
+		seen[event.ID] = true
+		if err := conn.WriteJSON(event); err != nil {
+			return
+		}
+	}
+	
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+	
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client too slow, reconnect with last_event_id"))
+				return
+			}
+			if seen[event.ID] {
+				continue
+			}
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
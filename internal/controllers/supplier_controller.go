@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type SupplierController struct {
+	supplierService *services.SupplierService
+	responseService *services.ResponseService
+}
+
+func NewSupplierController() *SupplierController {
+	return &SupplierController{
+		supplierService: services.NewSupplierService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *SupplierController) GetAllSuppliers(c *fiber.Ctx) error {
+	var active *bool
+	if raw := c.Query("active"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ctrl.responseService.BadRequest(c, "Invalid query parameter", "active must be true or false")
+		}
+		active = &value
+	}
+	
+	suppliers, err := ctrl.supplierService.GetAllSuppliers(c.Query("search"), active)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch suppliers", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Suppliers retrieved successfully", fiber.Map{
+		"suppliers": suppliers,
+	})
+}
+
+func (ctrl *SupplierController) GetSupplierByID(c *fiber.Ctx) error {
+	supplier, err := ctrl.supplierService.GetSupplierByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Supplier not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Supplier retrieved successfully", fiber.Map{
+		"supplier": supplier,
+	})
+}
+
+func (ctrl *SupplierController) CreateSupplier(c *fiber.Ctx) error {
+	var req models.CreateSupplierRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Code == "" || req.Name == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Code and name are required")
+	}
+	
+	supplier, err := ctrl.supplierService.CreateSupplier(&req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create supplier", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Supplier created successfully", fiber.Map{
+		"supplier": supplier,
+	})
+}
+
+func (ctrl *SupplierController) UpdateSupplier(c *fiber.Ctx) error {
+	var req models.UpdateSupplierRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	supplier, err := ctrl.supplierService.UpdateSupplier(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update supplier", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Supplier updated successfully", fiber.Map{
+		"supplier": supplier,
+	})
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type UnitController struct {
+	unitService     *services.UnitService
+	responseService *services.ResponseService
+}
+
+func NewUnitController() *UnitController {
+	return &UnitController{
+		unitService:     services.NewUnitService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *UnitController) GetAllUnits(c *fiber.Ctx) error {
+	units, err := ctrl.unitService.GetAllUnits()
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch units", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Units retrieved successfully", fiber.Map{
+		"units": units,
+	})
+}
+
+func (ctrl *UnitController) CreateUnit(c *fiber.Ctx) error {
+	var req models.CreateUnitRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Code == "" || req.Name == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Code and name are required")
+	}
+	
+	unit, err := ctrl.unitService.CreateUnit(&req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create unit", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Unit created successfully", fiber.Map{
+		"unit": unit,
+	})
+}
+
+func (ctrl *UnitController) DeleteUnit(c *fiber.Ctx) error {
+	if err := ctrl.unitService.DeleteUnit(c.Params("code")); err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to delete unit", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Unit deleted successfully", nil)
+}
\ No newline at end of file
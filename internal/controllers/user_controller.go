@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type UserController struct {
+	userService     *services.UserService
+	responseService *services.ResponseService
+}
+
+func NewUserController() *UserController {
+	return &UserController{
+		userService:     services.NewUserService(),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *UserController) GetAllUsers(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	search := c.Query("search", "")
+	role := c.Query("role", "")
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	var active *bool
+	if raw := c.Query("active"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return ctrl.responseService.BadRequest(c, "Invalid query parameter", "active must be true or false")
+		}
+		active = &value
+	}
+	
+	users, total, err := ctrl.userService.GetAllUsers(page, limit, search, role, active)
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch users", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Users retrieved successfully",
+		users,
+		page,
+		limit,
+		total,
+	)
+}
+
+func (ctrl *UserController) GetUserByID(c *fiber.Ctx) error {
+	user, err := ctrl.userService.GetUserByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "User not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "User retrieved successfully", fiber.Map{
+		"user": user,
+	})
+}
+
+func (ctrl *UserController) UpdateUser(c *fiber.Ctx) error {
+	var req models.UpdateUserRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	actorID, _ := c.Locals("userID").(string)
+	
+	user, err := ctrl.userService.UpdateUser(c.Params("id"), &req, actorID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update user", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "User updated successfully", fiber.Map{
+		"user": user,
+	})
+}
+
+func (ctrl *UserController) ActivateUser(c *fiber.Ctx) error {
+	return ctrl.setActive(c, true)
+}
+
+func (ctrl *UserController) DeactivateUser(c *fiber.Ctx) error {
+	return ctrl.setActive(c, false)
+}
+
+func (ctrl *UserController) setActive(c *fiber.Ctx, active bool) error {
+	actorID, _ := c.Locals("userID").(string)
+	
+	user, err := ctrl.userService.SetActive(c.Params("id"), active, actorID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update user status", err.Error())
+	}
+	
+	message := "User activated successfully"
+	if !active {
+		message = "User deactivated successfully"
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, message, fiber.Map{
+		"user": user,
+	})
+}
+
+func (ctrl *UserController) ResetPassword(c *fiber.Ctx) error {
+	var req models.ResetPasswordRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+		}
+	}
+	
+	password, err := ctrl.userService.ResetPassword(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to reset password", err.Error())
+	}
+	
+	data := fiber.Map{}
+	if req.Password == "" {
+		data["temporary_password"] = password
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Password reset successfully", data)
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type WarehouseController struct {
+	warehouseService *services.WarehouseService
+	responseService  *services.ResponseService
+}
+
+func NewWarehouseController() *WarehouseController {
+	return &WarehouseController{
+		warehouseService: services.NewWarehouseService(),
+		responseService:  services.NewResponseService(),
+	}
+}
+
+func (ctrl *WarehouseController) GetAllWarehouses(c *fiber.Ctx) error {
+	warehouses, err := ctrl.warehouseService.GetAllWarehouses()
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch warehouses", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Warehouses retrieved successfully", fiber.Map{
+		"warehouses": warehouses,
+	})
+}
+
+func (ctrl *WarehouseController) GetWarehouseByID(c *fiber.Ctx) error {
+	warehouse, err := ctrl.warehouseService.GetWarehouseByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Warehouse not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Warehouse retrieved successfully", fiber.Map{
+		"warehouse": warehouse,
+	})
+}
+
+func (ctrl *WarehouseController) CreateWarehouse(c *fiber.Ctx) error {
+	var req models.CreateWarehouseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Code == "" || req.Name == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Code and name are required")
+	}
+	
+	warehouse, err := ctrl.warehouseService.CreateWarehouse(&req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create warehouse", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Warehouse created successfully", fiber.Map{
+		"warehouse": warehouse,
+	})
+}
+
+func (ctrl *WarehouseController) UpdateWarehouse(c *fiber.Ctx) error {
+	var req models.UpdateWarehouseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	warehouse, err := ctrl.warehouseService.UpdateWarehouse(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update warehouse", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Warehouse updated successfully", fiber.Map{
+		"warehouse": warehouse,
+	})
+}
+
+func (ctrl *WarehouseController) DeleteWarehouse(c *fiber.Ctx) error {
+	if err := ctrl.warehouseService.DeleteWarehouse(c.Params("id")); err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to delete warehouse", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Warehouse deleted successfully", nil)
+}
+
+func (ctrl *WarehouseController) CreateBin(c *fiber.Ctx) error {
+	var req models.CreateBinRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Code == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Bin code is required")
+	}
+	
+	bin, err := ctrl.warehouseService.CreateBin(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create bin", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Bin created successfully", fiber.Map{
+		"bin": bin,
+	})
+}
+
+func (ctrl *WarehouseController) DeleteBin(c *fiber.Ctx) error {
+	if err := ctrl.warehouseService.DeleteBin(c.Params("id"), c.Params("binId")); err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to delete bin", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Bin deleted successfully", nil)
+}
@@ -0,0 +1,145 @@
+package controllers
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/services"
+)
+
+type WebhookController struct {
+	webhookService  *services.WebhookService
+	responseService *services.ResponseService
+}
+
+func NewWebhookController(cfg *config.Config) *WebhookController {
+	return &WebhookController{
+		webhookService:  services.NewWebhookService(cfg),
+		responseService: services.NewResponseService(),
+	}
+}
+
+func (ctrl *WebhookController) GetAllWebhooks(c *fiber.Ctx) error {
+	webhooks, err := ctrl.webhookService.GetAllWebhooks()
+	if err != nil {
+		return ctrl.responseService.InternalServerError(c, "Failed to fetch webhooks", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Webhooks retrieved successfully", fiber.Map{
+		"webhooks": webhooks,
+	})
+}
+
+func (ctrl *WebhookController) GetWebhookByID(c *fiber.Ctx) error {
+	webhook, err := ctrl.webhookService.GetWebhookByID(c.Params("id"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Webhook not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Webhook retrieved successfully", fiber.Map{
+		"webhook": webhook,
+	})
+}
+
+func (ctrl *WebhookController) CreateWebhook(c *fiber.Ctx) error {
+	var req models.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	if req.Name == "" || req.URL == "" {
+		return ctrl.responseService.BadRequest(c, "Validation failed", "Name and URL are required")
+	}
+	
+	userID, ok := c.Locals("userID").(string)
+	if !ok || userID == "" {
+		return ctrl.responseService.Unauthorized(c, "Authentication required", "User not authenticated")
+	}
+	
+	webhook, secret, err := ctrl.webhookService.CreateWebhook(&req, userID)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to create webhook", err.Error())
+	}
+	
+	return ctrl.responseService.Created(c, "Webhook created successfully", fiber.Map{
+		"webhook": webhook,
+		"secret":  secret,
+	})
+}
+
+func (ctrl *WebhookController) UpdateWebhook(c *fiber.Ctx) error {
+	var req models.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ctrl.responseService.BadRequest(c, "Invalid request body", err.Error())
+	}
+	
+	webhook, err := ctrl.webhookService.UpdateWebhook(c.Params("id"), &req)
+	if err != nil {
+		return ctrl.responseService.BadRequest(c, "Failed to update webhook", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Webhook updated successfully", fiber.Map{
+		"webhook": webhook,
+	})
+}
+
+func (ctrl *WebhookController) DeleteWebhook(c *fiber.Ctx) error {
+	if err := ctrl.webhookService.DeleteWebhook(c.Params("id")); err != nil {
+		return ctrl.responseService.NotFound(c, "Webhook not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Webhook deleted successfully", nil)
+}
+
+func (ctrl *WebhookController) GetDeliveries(c *fiber.Ctx) error {
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "20"))
+	status := c.Query("status", "")
+	
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	
+	deliveries, total, err := ctrl.webhookService.GetDeliveries(c.Params("id"), page, limit, status)
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Webhook not found", err.Error())
+	}
+	
+	return ctrl.responseService.SuccessWithPagination(
+		c,
+		fiber.StatusOK,
+		"Deliveries retrieved successfully",
+		deliveries,
+		page,
+		limit,
+		total,
+	)
+}
+
+func (ctrl *WebhookController) GetDeliveryByID(c *fiber.Ctx) error {
+	delivery, err := ctrl.webhookService.GetDeliveryByID(c.Params("deliveryId"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Delivery not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusOK, "Delivery retrieved successfully", fiber.Map{
+		"delivery": delivery,
+	})
+}
+
+func (ctrl *WebhookController) ReplayDelivery(c *fiber.Ctx) error {
+	delivery, err := ctrl.webhookService.ReplayDelivery(c.Params("deliveryId"))
+	if err != nil {
+		return ctrl.responseService.NotFound(c, "Delivery not found", err.Error())
+	}
+	
+	return ctrl.responseService.Success(c, fiber.StatusAccepted, "Delivery queued for replay", fiber.Map{
+		"delivery": delivery,
+	})
+}
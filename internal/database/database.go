@@ -44,11 +44,115 @@ func MigrateDB() {
 		&models.User{},
 		&models.Item{},
 		&models.ActivityLog{},
+		&models.Permission{},
+		&models.Role{},
+		&models.UserSession{},
+		&models.RefreshToken{},
+		&models.RevokedToken{},
+		&models.Warehouse{},
+		&models.Bin{},
+		&models.ItemStock{},
+		&models.StockMovement{},
+		&models.Supplier{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.SalesOrder{},
+		&models.SalesOrderLine{},
+		&models.StockReservation{},
+		&models.StockAlert{},
+		&models.CostLayer{},
+		&models.StockLot{},
+		&models.SerialNumber{},
+		&models.SerialEvent{},
+		&models.Category{},
+		&models.Unit{},
+		&models.ItemUnit{},
+		&models.AttributeDefinition{},
+		&models.KitComponent{},
+		&models.KitBuild{},
+		&models.Stocktake{},
+		&models.StocktakeLine{},
+		&models.StocktakeCount{},
+		&models.WebhookSubscription{},
+		&models.WebhookDelivery{},
+		&models.WebhookAttempt{},
+		&models.OutboxEvent{},
+		&models.IdempotencyKey{},
 	)
 	
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 	
+	// Replaced by idx_webhook_deliveries_first, which leaves replays out so
+	// a delivery can be replayed more than once.
+	if err := DB.Exec("DROP INDEX IF EXISTS idx_webhook_deliveries_event").Error; err != nil {
+		log.Fatal("Failed to drop old webhook delivery index:", err)
+	}
+	
+	if err := ProtectStockLedger(DB); err != nil {
+		log.Fatal("Failed to protect stock ledger:", err)
+	}
+	
+	if err := MigrateCategories(DB); err != nil {
+		log.Fatal("Failed to migrate categories:", err)
+	}
+	
+	if err := MigrateReservationWarehouses(DB); err != nil {
+		log.Fatal("Failed to migrate reservation warehouses:", err)
+	}
+	
 	fmt.Println("Database migration completed!")
-}
\ No newline at end of file
+}
+
+// ProtectStockLedger installs a trigger that rejects UPDATE and DELETE on
+// stock_movements, so the ledger stays append-only even for raw SQL.
+func ProtectStockLedger(db *gorm.DB) error {
+	return db.Exec(`
+		CREATE OR REPLACE FUNCTION stock_movements_append_only() RETURNS trigger AS $$
+		BEGIN
+			RAISE EXCEPTION 'stock_movements is append-only';
+		END;
+		$$ LANGUAGE plpgsql;
+		
+		DROP TRIGGER IF EXISTS stock_movements_append_only ON stock_movements;
+		CREATE TRIGGER stock_movements_append_only
+			BEFORE UPDATE OR DELETE ON stock_movements
+			FOR EACH ROW EXECUTE FUNCTION stock_movements_append_only();
+	`).Error
+}
+
+// MigrateCategories turns the category names of items not yet linked to a
+// category into category rows and links them. Names differing only in case
+// or surrounding spaces become one category, under the most used spelling.
+// It is safe to run repeatedly.
+func MigrateCategories(db *gorm.DB) error {
+	return db.Exec(`
+		INSERT INTO categories (id, name, description, created_at, updated_at)
+		SELECT gen_random_uuid(), MODE() WITHIN GROUP (ORDER BY TRIM(category)), '', NOW(), NOW()
+		FROM items
+		WHERE category_id IS NULL AND TRIM(COALESCE(category, '')) <> ''
+			AND NOT EXISTS (SELECT 1 FROM categories WHERE LOWER(categories.name) = LOWER(TRIM(items.category)))
+		GROUP BY LOWER(TRIM(category));
+		
+		UPDATE items SET category_id = categories.id, category = categories.name
+		FROM categories
+		WHERE items.category_id IS NULL AND LOWER(categories.name) = LOWER(TRIM(items.category));
+	`).Error
+}
+
+// MigrateReservationWarehouses assigns reservations made before they were
+// kept per warehouse to their order's warehouse, or the default warehouse
+// when the order names none. It is safe to run repeatedly.
+func MigrateReservationWarehouses(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE stock_reservations
+		SET warehouse_id = COALESCE(
+			NULLIF(sales_orders.warehouse_id, ''),
+			(SELECT id::text FROM warehouses WHERE is_default ORDER BY created_at LIMIT 1),
+			''
+		)
+		FROM sales_orders
+		WHERE sales_orders.id = stock_reservations.sales_order_id AND stock_reservations.warehouse_id = '';
+	`).Error
+}
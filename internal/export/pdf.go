@@ -0,0 +1,167 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pdfMargin     = 10.0
+	pdfRowHeight  = 6.0
+	pdfFontSize   = 8.0
+	pdfTitleSize  = 14.0
+	pdfCellMargin = 1.0
+)
+
+// pdfWriter lays rows out as a landscape A4 table and repeats the column
+// header on every page. gofpdf builds the document in memory, so the PDF is
+// written out on Close.
+type pdfWriter struct {
+	out     io.Writer
+	pdf     *gofpdf.Fpdf
+	title   string
+	columns []Column
+	widths  []float64
+	encode  func(string) string
+}
+
+func newPDFWriter(w io.Writer, title string) *pdfWriter {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.AliasNbPages("")
+	
+	p := &pdfWriter{out: w, pdf: pdf, title: title, encode: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin)
+		pdf.SetFont("Helvetica", "I", pdfFontSize)
+		pdf.CellFormat(0, pdfRowHeight/2, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+	return p
+}
+
+func (p *pdfWriter) WriteHeader(columns []Column) error {
+	p.columns = columns
+	
+	pageWidth, _ := p.pdf.GetPageSize()
+	available := pageWidth - 2*pdfMargin
+	
+	var total float64
+	for _, column := range columns {
+		total += columnWeight(column)
+	}
+	p.widths = make([]float64, len(columns))
+	for i, column := range columns {
+		p.widths[i] = available * columnWeight(column) / total
+	}
+	
+	p.pdf.AddPage()
+	p.pdf.SetFont("Helvetica", "B", pdfTitleSize)
+	p.pdf.CellFormat(0, 10, p.encode(p.title), "", 1, "L", false, 0, "")
+	p.pdf.SetFont("Helvetica", "", pdfFontSize)
+	p.pdf.CellFormat(0, 5, "Generated "+time.Now().Format("2006-01-02 15:04 MST"), "", 1, "L", false, 0, "")
+	p.pdf.Ln(2)
+	p.writeColumnHeader()
+	
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) WriteRow(record interface{}, cells []interface{}) error {
+	p.breakPageIfNeeded()
+	p.pdf.SetFont("Helvetica", "", pdfFontSize)
+	p.writeCells(cells, false)
+	return p.pdf.Error()
+}
+
+// WriteSubtotal prints a shaded, bold row. label fills the first cell.
+func (p *pdfWriter) WriteSubtotal(label string, cells []interface{}) error {
+	p.breakPageIfNeeded()
+	p.pdf.SetFont("Helvetica", "B", pdfFontSize)
+	p.pdf.SetFillColor(235, 235, 235)
+	
+	row := make([]interface{}, len(p.columns))
+	copy(row, cells)
+	row[0] = label
+	p.writeCells(row, true)
+	
+	return p.pdf.Error()
+}
+
+// Fail prints the error across the full width of the table.
+func (p *pdfWriter) Fail(err error) error {
+	if len(p.columns) == 0 {
+		p.pdf.AddPage()
+	}
+	p.breakPageIfNeeded()
+	p.pdf.SetFont("Helvetica", "B", pdfFontSize)
+	p.pdf.CellFormat(0, pdfRowHeight, p.encode(failureText(err)), "1", 1, "L", false, 0, "")
+	return p.pdf.Output(p.out)
+}
+
+func (p *pdfWriter) Close() error {
+	if len(p.columns) == 0 {
+		p.pdf.AddPage()
+	}
+	return p.pdf.Output(p.out)
+}
+
+func (p *pdfWriter) writeColumnHeader() {
+	p.pdf.SetFont("Helvetica", "B", pdfFontSize)
+	p.pdf.SetFillColor(210, 210, 210)
+	for i, column := range p.columns {
+		p.pdf.CellFormat(p.widths[i], pdfRowHeight, p.fit(column.Title, p.widths[i]), "1", 0, "L", true, 0, "")
+	}
+	p.pdf.Ln(-1)
+}
+
+func (p *pdfWriter) writeCells(cells []interface{}, fill bool) {
+	for i := range p.columns {
+		var cell interface{}
+		if i < len(cells) {
+			cell = cells[i]
+		}
+		
+		align := "L"
+		switch cell.(type) {
+		case int, int64, float64:
+			align = "R"
+		}
+		
+		p.pdf.CellFormat(p.widths[i], pdfRowHeight, p.fit(FormatCell(cell), p.widths[i]), "1", 0, align, fill, 0, "")
+	}
+	p.pdf.Ln(-1)
+}
+
+func (p *pdfWriter) breakPageIfNeeded() {
+	_, pageHeight := p.pdf.GetPageSize()
+	if p.pdf.GetY()+pdfRowHeight > pageHeight-2*pdfMargin {
+		p.pdf.AddPage()
+		p.writeColumnHeader()
+	}
+}
+
+// fit shortens text with an ellipsis until it fits in width. The core
+// fonts are single-byte encoded, so the text is cut per byte after
+// translation.
+func (p *pdfWriter) fit(text string, width float64) string {
+	text = p.encode(text)
+	limit := width - 2*pdfCellMargin
+	if p.pdf.GetStringWidth(text) <= limit {
+		return text
+	}
+	
+	for len(text) > 0 && p.pdf.GetStringWidth(text+"...") > limit {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+func columnWeight(column Column) float64 {
+	if column.Width <= 0 {
+		return 1
+	}
+	return column.Width
+}
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const (
+	FormatCSV   = "csv"
+	FormatXLSX  = "xlsx"
+	FormatJSONL = "jsonl"
+	FormatPDF   = "pdf"
+)
+
+// Column describes one column of a tabular export. Width is relative to
+// the other columns and only matters for PDF.
+type Column struct {
+	Title string
+	Width float64
+}
+
+// Writer receives an export one row at a time. cells is the tabular form of
+// a row and record the structured form, which JSON Lines writes as is.
+// Subtotals are only rendered by formats meant for printing. Fail is called
+// instead of Close when the export breaks off; it writes a last row naming
+// the error so the output cannot pass for a complete export.
+type Writer interface {
+	WriteHeader(columns []Column) error
+	WriteRow(record interface{}, cells []interface{}) error
+	WriteSubtotal(label string, cells []interface{}) error
+	Fail(err error) error
+	Close() error
+}
+
+func failureText(err error) string {
+	return "ERROR: export incomplete: " + err.Error()
+}
+
+// NewWriter returns a writer for format that writes to w. title is used by
+// formats that have a document or sheet title.
+func NewWriter(format string, w io.Writer, title string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return &csvWriter{w: csv.NewWriter(w)}, nil
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		return &jsonlWriter{w: bw, enc: json.NewEncoder(bw)}, nil
+	case FormatXLSX:
+		return newXLSXWriter(w, title)
+	case FormatPDF:
+		return newPDFWriter(w, title), nil
+	default:
+		return nil, fmt.Errorf("unsupported format: %s", format)
+	}
+}
+
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func IsFormat(format string) bool {
+	switch format {
+	case FormatCSV, FormatXLSX, FormatJSONL, FormatPDF:
+		return true
+	}
+	return false
+}
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func (c *csvWriter) WriteHeader(columns []Column) error {
+	titles := make([]string, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+	}
+	return c.w.Write(titles)
+}
+
+// WriteRow flushes every few hundred rows so the output reaches the client
+// while the export is still running.
+func (c *csvWriter) WriteRow(record interface{}, cells []interface{}) error {
+	if err := c.w.Write(formatCells(cells)); err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%500 == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) WriteSubtotal(label string, cells []interface{}) error {
+	return nil
+}
+
+func (c *csvWriter) Fail(err error) error {
+	if writeErr := c.w.Write([]string{failureText(err)}); writeErr != nil {
+		return writeErr
+	}
+	return c.Close()
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type jsonlWriter struct {
+	w    *bufio.Writer
+	enc  *json.Encoder
+	rows int
+}
+
+func (j *jsonlWriter) WriteHeader(columns []Column) error {
+	return nil
+}
+
+func (j *jsonlWriter) WriteRow(record interface{}, cells []interface{}) error {
+	if err := j.enc.Encode(record); err != nil {
+		return err
+	}
+	j.rows++
+	if j.rows%500 == 0 {
+		return j.w.Flush()
+	}
+	return nil
+}
+
+func (j *jsonlWriter) WriteSubtotal(label string, cells []interface{}) error {
+	return nil
+}
+
+func (j *jsonlWriter) Fail(err error) error {
+	if encodeErr := j.enc.Encode(map[string]string{"error": failureText(err)}); encodeErr != nil {
+		return encodeErr
+	}
+	return j.Close()
+}
+
+func (j *jsonlWriter) Close() error {
+	return j.w.Flush()
+}
+
+// FormatCell renders a cell value as text. Floats get two decimals and
+// times use RFC 3339.
+func FormatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func formatCells(cells []interface{}) []string {
+	text := make([]string, len(cells))
+	for i, cell := range cells {
+		text[i] = FormatCell(cell)
+	}
+	return text
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxWriter uses excelize's stream writer, which spills rows to a
+// temporary file instead of keeping the whole sheet in memory. The workbook
+// can only be written out once it is complete.
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	row    int
+}
+
+func newXLSXWriter(w io.Writer, title string) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	
+	sheet := sheetName(title)
+	if err := f.SetSheetName("Sheet1", sheet); err != nil {
+		f.Close()
+		return nil, err
+	}
+	
+	stream, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	
+	return &xlsxWriter{out: w, file: f, stream: stream}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(columns []Column) error {
+	titles := make([]interface{}, len(columns))
+	for i, column := range columns {
+		titles[i] = column.Title
+	}
+	return x.writeCells(titles)
+}
+
+func (x *xlsxWriter) WriteRow(record interface{}, cells []interface{}) error {
+	return x.writeCells(cells)
+}
+
+func (x *xlsxWriter) WriteSubtotal(label string, cells []interface{}) error {
+	return nil
+}
+
+// writeCells keeps numbers and times typed so they stay sortable and
+// summable in a spreadsheet.
+func (x *xlsxWriter) writeCells(cells []interface{}) error {
+	x.row++
+	
+	axis, err := excelize.CoordinatesToCellName(1, x.row)
+	if err != nil {
+		return err
+	}
+	return x.stream.SetRow(axis, cells)
+}
+
+func (x *xlsxWriter) Fail(err error) error {
+	if writeErr := x.writeCells([]interface{}{failureText(err)}); writeErr != nil {
+		x.file.Close()
+		return writeErr
+	}
+	return x.Close()
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.file.Close()
+	
+	if err := x.stream.Flush(); err != nil {
+		return err
+	}
+	return x.file.Write(x.out)
+}
+
+// sheetName trims title to the 31 characters Excel allows.
+func sheetName(title string) string {
+	if title == "" {
+		return "Sheet1"
+	}
+	if len(title) > 31 {
+		return title[:31]
+	}
+	return title
+}
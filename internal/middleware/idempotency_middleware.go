@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/services"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// Idempotency makes a mutating request safe to retry when it carries an
+// Idempotency-Key header: the first response is stored for
+// IDEMPOTENCY_TTL_HOURS and replayed to any retry with the same key and
+// payload. Reusing a key for a different payload is rejected with 422.
+// Requests without the header pass straight through. It must run after
+// JWTMiddleware.
+func Idempotency(cfg *config.Config) fiber.Handler {
+	idempotencyService := services.NewIdempotencyService(cfg)
+	responseService := services.NewResponseService()
+	
+	return func(c *fiber.Ctx) error {
+		key := strings.TrimSpace(c.Get(HeaderIdempotencyKey))
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return responseService.BadRequest(c, "Invalid Idempotency-Key header", "Idempotency-Key must be at most 255 characters")
+		}
+		
+		userID, _ := c.Locals("userID").(string)
+		record, claimed, err := idempotencyService.Begin(userID, key, c.Method(), c.OriginalURL(), requestHash(c))
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrIdempotencyKeyReused):
+				return responseService.Error(c, fiber.StatusUnprocessableEntity, "Idempotency key reused", err.Error())
+			case errors.Is(err, services.ErrIdempotencyKeyInFlight):
+				return responseService.Error(c, fiber.StatusConflict, "Request already in progress", err.Error())
+			}
+			return responseService.InternalServerError(c, "Failed to check idempotency key", err.Error())
+		}
+		
+		if !claimed {
+			c.Set(HeaderIdempotentReplayed, "true")
+			if record.ETag != "" {
+				c.Set(fiber.HeaderETag, record.ETag)
+			}
+			if record.ContentType != "" {
+				c.Set(fiber.HeaderContentType, record.ContentType)
+			}
+			return c.Status(record.StatusCode).Send(record.ResponseBody)
+		}
+		
+		if err := c.Next(); err != nil {
+			if releaseErr := idempotencyService.Release(record); releaseErr != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, releaseErr)
+			}
+			return err
+		}
+		
+		response := c.Response()
+		err = idempotencyService.Complete(record,
+			response.StatusCode(),
+			string(response.Header.ContentType()),
+			string(response.Header.Peek(fiber.HeaderETag)),
+			response.Body(),
+		)
+		if err != nil {
+			// A key left in progress would answer every retry with a
+			// conflict, so it is dropped and the next retry runs afresh.
+			log.Printf("Failed to store response for idempotency key %s: %v", key, err)
+			if releaseErr := idempotencyService.Release(record); releaseErr != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, releaseErr)
+			}
+		}
+		return nil
+	}
+}
+
+// requestHash fingerprints what a retry must repeat exactly: the method, the
+// URL with its query and the body.
+func requestHash(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.OriginalURL()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
\ No newline at end of file
@@ -6,10 +6,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 
 	"inventory-api/internal/config"
+	"inventory-api/internal/repositories"
 	"inventory-api/internal/utils"
 )
 
 func JWTMiddleware(cfg *config.Config) fiber.Handler {
+	userRepo := repositories.NewUserRepository()
+	sessionRepo := repositories.NewSessionRepository()
+	
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 		if authHeader == "" {
@@ -27,11 +31,53 @@ func JWTMiddleware(cfg *config.Config) fiber.Handler {
 			})
 		}
 		
-		c.Locals("userID", claims.UserID)
-		c.Locals("userName", claims.UserName)
-		c.Locals("userEmail", claims.Email)
-		c.Locals("userRole", claims.Role)
+		if claims.ID == "" || claims.SessionID == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid token",
+			})
+		}
+		
+		revoked, err := sessionRepo.IsTokenRevoked(claims.ID)
+		if err != nil || revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Token has been revoked",
+			})
+		}
+		
+		session, err := sessionRepo.FindSessionByID(claims.SessionID)
+		if err != nil || session.RevokedAt != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Session has been revoked",
+			})
+		}
+		
+		user, err := userRepo.FindByID(claims.UserID)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Invalid token",
+			})
+		}
+		
+		if !user.IsActive {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Account is deactivated",
+			})
+		}
+		
+		if user.MustChangePassword && !strings.HasPrefix(c.Path(), "/api/profile") && !strings.HasPrefix(c.Path(), "/api/auth") {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Password change required",
+			})
+		}
+		
+		c.Locals("userID", user.ID)
+		c.Locals("userName", user.Name)
+		c.Locals("userEmail", user.Email)
+		c.Locals("userRole", user.Role)
+		c.Locals("sessionID", claims.SessionID)
+		c.Locals("tokenID", claims.ID)
+		c.Locals("tokenExpiresAt", claims.ExpiresAt.Time)
 		
 		return c.Next()
 	}
-}
\ No newline at end of file
+}
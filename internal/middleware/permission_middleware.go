@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"inventory-api/internal/services"
+)
+
+// RequirePermission only lets the request through when the caller's role
+// holds every listed permission. It must run after JWTMiddleware.
+func RequirePermission(permissions ...string) fiber.Handler {
+	roleService := services.NewRoleService()
+	responseService := services.NewResponseService()
+	
+	return func(c *fiber.Ctx) error {
+		role, _ := c.Locals("userRole").(string)
+		
+		allowed, err := roleService.HasPermissions(role, permissions...)
+		if err != nil {
+			return responseService.InternalServerError(c, "Failed to check permissions", err.Error())
+		}
+		if !allowed {
+			return responseService.Forbidden(c, "Access denied", "Requires permission: "+strings.Join(permissions, ", "))
+		}
+		
+		return c.Next()
+	}
+}
@@ -10,28 +10,76 @@ import (
 type ActivityType string
 
 const (
-	ActivityTypeStockIncrement ActivityType = "STOCK_INCREMENT"
-	ActivityTypeStockDecrement ActivityType = "STOCK_DECREMENT"
-	ActivityTypeItemCreated    ActivityType = "ITEM_CREATED"
-	ActivityTypeItemUpdated    ActivityType = "ITEM_UPDATED"
-	ActivityTypeItemDeleted    ActivityType = "ITEM_DELETED"
+	ActivityTypeStockIncrement  ActivityType = "STOCK_INCREMENT"
+	ActivityTypeStockDecrement  ActivityType = "STOCK_DECREMENT"
+	ActivityTypeItemCreated     ActivityType = "ITEM_CREATED"
+	ActivityTypeItemUpdated     ActivityType = "ITEM_UPDATED"
+	ActivityTypeItemDeleted     ActivityType = "ITEM_DELETED"
+	ActivityTypeTransferOut     ActivityType = "STOCK_TRANSFER_OUT"
+	ActivityTypeTransferIn      ActivityType = "STOCK_TRANSFER_IN"
+	ActivityTypeStockAdjustment ActivityType = "STOCK_ADJUSTMENT"
+	ActivityTypeLotQuarantined  ActivityType = "LOT_QUARANTINED"
+	ActivityTypeLotReleased     ActivityType = "LOT_RELEASED"
+	ActivityTypeKitAssembled    ActivityType = "KIT_ASSEMBLED"
+	ActivityTypeKitDisassembled ActivityType = "KIT_DISASSEMBLED"
 )
 
+var ActivityTypes = map[ActivityType]bool{
+	ActivityTypeStockIncrement:  true,
+	ActivityTypeStockDecrement:  true,
+	ActivityTypeItemCreated:     true,
+	ActivityTypeItemUpdated:     true,
+	ActivityTypeItemDeleted:     true,
+	ActivityTypeTransferOut:     true,
+	ActivityTypeTransferIn:      true,
+	ActivityTypeStockAdjustment: true,
+	ActivityTypeLotQuarantined:  true,
+	ActivityTypeLotReleased:     true,
+	ActivityTypeKitAssembled:    true,
+	ActivityTypeKitDisassembled: true,
+}
+
+const (
+	ReferenceTypeTransfer = "TRANSFER"
+)
+
+// ActivityLog quantities are in the item's base unit. For stock changes,
+// EnteredQuantity and EnteredUnit keep the quantity as the user gave it.
 type ActivityLog struct {
-	ID          string       `gorm:"type:uuid;primaryKey" json:"id"`
-	UserID      string       `gorm:"not null" json:"user_id"`
-	UserName    string       `gorm:"not null" json:"user_name"`
-	ItemID      string       `gorm:"not null" json:"item_id"`
-	ItemName    string       `gorm:"not null" json:"item_name"`
-	Action      ActivityType `gorm:"not null" json:"action"`
-	Quantity    int          `json:"quantity"`
-	OldStock    int          `json:"old_stock"`
-	NewStock    int          `json:"new_stock"`
-	Description string       `json:"description"`
-	CreatedAt   time.Time    `json:"created_at"`
+	ID              string       `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID          string       `gorm:"not null" json:"user_id"`
+	UserName        string       `gorm:"not null" json:"user_name"`
+	ItemID          string       `gorm:"not null" json:"item_id"`
+	ItemName        string       `gorm:"not null" json:"item_name"`
+	Action          ActivityType `gorm:"not null" json:"action"`
+	Quantity        int          `json:"quantity"`
+	EnteredQuantity int          `json:"entered_quantity,omitempty"`
+	EnteredUnit     string       `json:"entered_unit,omitempty"`
+	OldStock        int          `json:"old_stock"`
+	NewStock        int          `json:"new_stock"`
+	Description     string       `json:"description"`
+	WarehouseID     string       `gorm:"index" json:"warehouse_id,omitempty"`
+	BinID           string       `json:"bin_id,omitempty"`
+	ReferenceType   string       `gorm:"index:idx_activity_logs_reference" json:"reference_type,omitempty"`
+	ReferenceID     string       `gorm:"index:idx_activity_logs_reference" json:"reference_id,omitempty"`
+	LotNumber       string       `gorm:"index" json:"lot_number,omitempty"`
+	CreatedAt       time.Time    `gorm:"index" json:"created_at"`
 }
 
 func (a *ActivityLog) BeforeCreate(tx *gorm.DB) error {
 	a.ID = uuid.New().String()
 	return nil
+}
+
+// ActivityFilter narrows the activity list and its export. Empty fields do
+// not narrow; From and To bound created_at inclusively.
+type ActivityFilter struct {
+	Type          string
+	ItemID        string
+	UserID        string
+	ReferenceType string
+	ReferenceID   string
+	LotNumber     string
+	From          *time.Time
+	To            *time.Time
 }
\ No newline at end of file
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttributeType string
+
+const (
+	AttributeTypeString AttributeType = "string"
+	AttributeTypeNumber AttributeType = "number"
+	AttributeTypeEnum   AttributeType = "enum"
+	AttributeTypeDate   AttributeType = "date"
+)
+
+var AttributeTypes = map[AttributeType]bool{
+	AttributeTypeString: true,
+	AttributeTypeNumber: true,
+	AttributeTypeEnum:   true,
+	AttributeTypeDate:   true,
+}
+
+// Attributes holds an item's custom attribute values by key.
+type Attributes map[string]interface{}
+
+// AttributeDefinition declares a typed custom attribute for the items of one
+// category. Item values are kept in Item.Attributes under Key.
+type AttributeDefinition struct {
+	ID        string        `gorm:"type:uuid;primaryKey" json:"id"`
+	Category  string        `gorm:"not null;uniqueIndex:idx_attribute_definitions_category_key" json:"category"`
+	Key       string        `gorm:"not null;uniqueIndex:idx_attribute_definitions_category_key" json:"key"`
+	Label     string        `json:"label"`
+	Type      AttributeType `gorm:"size:16;not null" json:"type"`
+	Options   []string      `gorm:"type:text;serializer:json" json:"options,omitempty"`
+	Required  bool          `gorm:"not null;default:false" json:"required"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (d *AttributeDefinition) BeforeCreate(tx *gorm.DB) error {
+	d.ID = uuid.New().String()
+	return nil
+}
+
+type CreateAttributeDefinitionRequest struct {
+	Category string   `json:"category" validate:"required"`
+	Key      string   `json:"key" validate:"required"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type" validate:"required,oneof=string number enum date"`
+	Options  []string `json:"options"`
+	Required bool     `json:"required"`
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Category is a node of the category tree. Names are unique regardless of
+// case; items keep the name in Item.Category alongside CategoryID.
+type Category struct {
+	ID          string      `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string      `gorm:"uniqueIndex;not null" json:"name"`
+	Description string      `json:"description"`
+	ParentID    *string     `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Children    []*Category `gorm:"-" json:"children,omitempty"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+func (c *Category) BeforeCreate(tx *gorm.DB) error {
+	c.ID = uuid.New().String()
+	return nil
+}
+
+type CreateCategoryRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	ParentID    string `json:"parent_id"`
+}
+
+// UpdateCategoryRequest renames or moves a category. An empty ParentID
+// moves it to the root; a nil one leaves it where it is.
+type UpdateCategoryRequest struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	ParentID    *string `json:"parent_id"`
+}
\ No newline at end of file
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CostingMethod string
+
+const (
+	CostingMethodFIFO    CostingMethod = "FIFO"
+	CostingMethodLIFO    CostingMethod = "LIFO"
+	CostingMethodAverage CostingMethod = "AVERAGE"
+)
+
+var CostingMethods = map[CostingMethod]bool{
+	CostingMethodFIFO:    true,
+	CostingMethodLIFO:    true,
+	CostingMethodAverage: true,
+}
+
+// CostLayer is a quantity received into one warehouse at one unit cost.
+// Decrements consume Remaining from the open layers of that warehouse in
+// the order the item's costing method dictates.
+type CostLayer struct {
+	ID          string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID      string    `gorm:"type:uuid;not null;index:idx_cost_layers_item_warehouse" json:"item_id"`
+	WarehouseID string    `gorm:"type:uuid;not null;index:idx_cost_layers_item_warehouse" json:"warehouse_id"`
+	MovementID  string    `gorm:"type:uuid" json:"movement_id,omitempty"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	Remaining   int       `gorm:"not null" json:"remaining"`
+	UnitCost    float64   `gorm:"type:decimal(14,4);not null;default:0" json:"unit_cost"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (l *CostLayer) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New().String()
+	return nil
+}
+
+const (
+	ValuationGroupCategory  = "category"
+	ValuationGroupWarehouse = "warehouse"
+)
+
+type ValuationFilter struct {
+	GroupBy     string
+	AsOf        time.Time
+	From        *time.Time
+	WarehouseID string
+	Category    string
+}
+
+// ValuationLine is one category or warehouse. Category lines include their
+// subcategories, and ParentID places them in the tree.
+type ValuationLine struct {
+	Key      string  `json:"key"`
+	Name     string  `json:"name"`
+	ParentID string  `json:"parent_id,omitempty"`
+	Quantity int     `json:"quantity"`
+	Value    float64 `json:"value"`
+	COGS     float64 `json:"cogs"`
+}
+
+// InventoryValuation is the on-hand value at AsOf. COGS covers issues
+// between From (or the start of the ledger) and AsOf.
+type InventoryValuation struct {
+	GroupBy       string          `json:"group_by"`
+	AsOf          time.Time       `json:"as_of"`
+	From          *time.Time      `json:"from,omitempty"`
+	TotalQuantity int             `json:"total_quantity"`
+	TotalValue    float64         `json:"total_value"`
+	TotalCOGS     float64         `json:"total_cogs"`
+	Lines         []ValuationLine `json:"lines"`
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey records a mutating request sent with an Idempotency-Key
+// header, so a retry can be answered with the original response. Keys are
+// scoped to the user who sent them. StatusCode stays 0 while the first
+// request is still being handled.
+type IdempotencyKey struct {
+	ID           string    `gorm:"type:uuid;primaryKey" json:"id"`
+	UserID       string    `gorm:"type:uuid;not null;uniqueIndex:idx_idempotency_keys_user_key" json:"user_id"`
+	Key          string    `gorm:"not null;uniqueIndex:idx_idempotency_keys_user_key" json:"key"`
+	Method       string    `gorm:"not null" json:"method"`
+	Path         string    `gorm:"not null" json:"path"`
+	RequestHash  string    `gorm:"not null" json:"request_hash"`
+	StatusCode   int       `gorm:"not null;default:0" json:"status_code"`
+	ContentType  string    `json:"content_type,omitempty"`
+	ETag         string    `gorm:"column:etag" json:"etag,omitempty"`
+	ResponseBody []byte    `gorm:"type:bytea" json:"-"`
+	ExpiresAt    time.Time `gorm:"not null;index" json:"expires_at"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (k *IdempotencyKey) BeforeCreate(tx *gorm.DB) error {
+	k.ID = uuid.New().String()
+	return nil
+}
+
+// Completed reports whether the original request has finished and its
+// response is stored.
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
\ No newline at end of file
@@ -3,6 +3,7 @@ package models
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -10,21 +11,39 @@ import (
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict means an item changed after the version a write was
+// based on.
+var ErrVersionConflict = errors.New("item has been modified since it was read")
+
+// Item.Version goes up with every write to the row, stock changes included,
+// and is exposed to clients as the ETag.
 type Item struct {
-	ID          string    `gorm:"type:uuid;primaryKey" json:"id"`
-	Name        string    `gorm:"not null" json:"name"`
-	Description string    `json:"description"`
-	Category    string    `json:"category"`
-	Stock       int       `gorm:"not null;default:0" json:"stock"`
-	MinStock    int       `gorm:"default:10" json:"min_stock"`
-	MaxStock    int       `gorm:"default:100" json:"max_stock"`
-	Price       float64   `gorm:"type:decimal(10,2)" json:"price"`
-	SKU         string    `gorm:"uniqueIndex" json:"sku"`
-	Location    string    `json:"location"`
-	CreatedBy   string    `gorm:"not null" json:"created_by"`
-	Creator     *User     `gorm:"foreignKey:CreatedBy;references:ID" json:"creator,omitempty"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             string        `gorm:"type:uuid;primaryKey" json:"id"`
+	Name           string        `gorm:"not null" json:"name"`
+	Description    string        `json:"description"`
+	Category       string        `json:"category"`
+	CategoryID     *string       `gorm:"type:uuid;index" json:"category_id,omitempty"`
+	Stock          int           `gorm:"not null;default:0" json:"stock"`
+	ReservedStock  int           `gorm:"not null;default:0" json:"reserved_stock"`
+	AvailableStock int           `gorm:"-" json:"available_stock"`
+	MinStock       int           `gorm:"default:10" json:"min_stock"`
+	MaxStock       int           `gorm:"default:100" json:"max_stock"`
+	Price          float64       `gorm:"type:decimal(10,2)" json:"price"`
+	SKU            string        `gorm:"uniqueIndex" json:"sku"`
+	Location       string        `json:"location"`
+	CostingMethod  CostingMethod `gorm:"size:16;not null;default:''" json:"costing_method,omitempty"`
+	LotTracked     bool          `gorm:"not null;default:false" json:"lot_tracked"`
+	Serialized     bool          `gorm:"not null;default:false" json:"serialized"`
+	BaseUnit       string        `gorm:"size:16;not null;default:'EA'" json:"base_unit"`
+	PurchaseUnit   string        `gorm:"size:16;not null;default:''" json:"purchase_unit,omitempty"`
+	IssueUnit      string        `gorm:"size:16;not null;default:''" json:"issue_unit,omitempty"`
+	ParentID       *string       `gorm:"type:uuid;index" json:"parent_id,omitempty"`
+	Attributes     Attributes    `gorm:"type:jsonb;not null;default:'{}';serializer:json" json:"attributes"`
+	Version        int           `gorm:"not null;default:1" json:"version"`
+	CreatedBy      string        `gorm:"not null" json:"created_by"`
+	Creator        *User         `gorm:"foreignKey:CreatedBy;references:ID" json:"creator,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
 }
 
 func randomString(n int) string {
@@ -48,30 +67,113 @@ func (i *Item) BeforeCreate(tx *gorm.DB) error {
     return nil
 }
 
+// ETag is the entity tag of the item's current version.
+func (i *Item) ETag() string {
+	return fmt.Sprintf("\"%d\"", i.Version)
+}
+
+func (i *Item) AfterFind(tx *gorm.DB) error {
+	i.AvailableStock = i.Stock - i.ReservedStock
+	return nil
+}
+
+// CreateItemRequest and UpdateItemRequest name a category by CategoryID or,
+// case-insensitively, by Category; an unknown name creates a root category.
 type CreateItemRequest struct {
 	Name        string  `json:"name" validate:"required"`
 	Description string  `json:"description"`
 	Category    string  `json:"category"`
+	CategoryID  string  `json:"category_id"`
 	Stock       int     `json:"stock" validate:"min=0"`
 	MinStock    int     `json:"min_stock" validate:"min=0"`
 	MaxStock    int     `json:"max_stock" validate:"min=0"`
 	Price       float64 `json:"price" validate:"min=0"`
 	SKU         string  `json:"sku"`
 	Location    string  `json:"location"`
+	WarehouseID string  `json:"warehouse_id"`
+	BinID       string  `json:"bin_id"`
+	
+	CostingMethod string `json:"costing_method" validate:"omitempty,oneof=FIFO LIFO AVERAGE"`
+	
+	// LotTracked items need a lot number and expiry date for opening stock.
+	LotTracked bool   `json:"lot_tracked"`
+	LotNumber  string `json:"lot_number"`
+	ExpiresAt  string `json:"expires_at"`
+	
+	// Serialized items need one serial number per unit of opening stock.
+	Serialized    bool     `json:"serialized"`
+	SerialNumbers []string `json:"serial_numbers"`
+	
+	// BaseUnit is the unit stock is kept in, EA when empty.
+	BaseUnit string `json:"base_unit"`
+	
+	// ParentID makes the item a variant of another item, whose category it
+	// takes when none is given. Attributes are checked against the category.
+	ParentID   string     `json:"parent_id"`
+	Attributes Attributes `json:"attributes"`
 }
 
 type UpdateItemRequest struct {
 	Name        string  `json:"name"`
 	Description string  `json:"description"`
 	Category    string  `json:"category"`
+	CategoryID  string  `json:"category_id"`
 	MinStock    int     `json:"min_stock" validate:"min=0"`
 	MaxStock    int     `json:"max_stock" validate:"min=0"`
 	Price       float64 `json:"price" validate:"min=0"`
 	Location    string  `json:"location"`
+	
+	CostingMethod string `json:"costing_method" validate:"omitempty,oneof=FIFO LIFO AVERAGE"`
+	LotTracked    *bool  `json:"lot_tracked"`
+	Serialized    *bool  `json:"serialized"`
+	
+	// Attributes are merged into the item's; a null value removes one.
+	Attributes Attributes `json:"attributes"`
 }
 
 type UpdateStockRequest struct {
 	Quantity int    `json:"quantity" validate:"required"`
 	Type     string `json:"type" validate:"required,oneof=increment decrement"`
 	Reason   string `json:"reason"`
-}
\ No newline at end of file
+	
+	WarehouseID string `json:"warehouse_id"`
+	BinID       string `json:"bin_id"`
+	
+	UnitCost      float64 `json:"unit_cost" validate:"min=0"`
+	ReasonCode    string  `json:"reason_code"`
+	ReferenceType string  `json:"reference_type"`
+	ReferenceID   string  `json:"reference_id"`
+	
+	// LotNumber and ExpiresAt (YYYY-MM-DD) are required on increments of
+	// lot-tracked items. Decrements without a lot are picked FEFO.
+	LotNumber string `json:"lot_number"`
+	ExpiresAt string `json:"expires_at"`
+	
+	// SerialNumbers names each unit coming in or going out of a serialized
+	// item; there must be exactly one per unit of quantity.
+	SerialNumbers []string `json:"serial_numbers"`
+	
+	// Unit is the unit Quantity and UnitCost are given in. Without it they
+	// are in the item's base unit.
+	Unit string `json:"unit"`
+}
+
+type ItemFilter struct {
+	Category string
+	Location string
+	Search   string
+	PriceMin *float64
+	PriceMax *float64
+	StockMin *int
+	StockMax *int
+	LowStock bool
+	Sort     []string
+	
+	// Category, by name, and CategoryID also match subcategories.
+	CategoryID string
+	
+	// ParentID lists the variants of one item. Attributes matches custom
+	// attribute values exactly, given as attr.<key>=<value>.
+	ParentID   string
+	Attributes map[string]string
+}
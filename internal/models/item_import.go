@@ -0,0 +1,27 @@
+package models
+
+// ItemImportOptions controls how an uploaded item sheet is applied.
+// Mapping maps an item field (name, sku, stock, ...) to the header of the
+// column that holds it; unmapped fields are looked up by their own name.
+// The stock column is the quantity in WarehouseID, or the default warehouse.
+type ItemImportOptions struct {
+	Mapping     map[string]string `json:"mapping"`
+	DryRun      bool              `json:"dry_run"`
+	Upsert      bool              `json:"upsert"`
+	WarehouseID string            `json:"warehouse_id"`
+}
+
+type ItemImportRowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ItemImportResult struct {
+	DryRun    bool                 `json:"dry_run"`
+	Imported  bool                 `json:"imported"`
+	TotalRows int                  `json:"total_rows"`
+	Created   int                  `json:"created"`
+	Updated   int                  `json:"updated"`
+	Errors    []ItemImportRowError `json:"errors"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type KitBuildAction string
+
+const (
+	KitBuildAssemble    KitBuildAction = "assemble"
+	KitBuildDisassemble KitBuildAction = "disassemble"
+)
+
+const ReferenceTypeKitBuild = "KIT_BUILD"
+
+// KitComponent is one line of a kit's bill of materials: one kit is made of
+// Quantity base units of the component item.
+type KitComponent struct {
+	ID          string    `gorm:"type:uuid;primaryKey" json:"id"`
+	KitID       string    `gorm:"type:uuid;not null;uniqueIndex:idx_kit_components_kit_component" json:"kit_id"`
+	ComponentID string    `gorm:"type:uuid;not null;uniqueIndex:idx_kit_components_kit_component;index" json:"component_id"`
+	Component   *Item     `gorm:"foreignKey:ComponentID" json:"component,omitempty"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (k *KitComponent) BeforeCreate(tx *gorm.DB) error {
+	k.ID = uuid.New().String()
+	return nil
+}
+
+// KitBuild records one assembly or disassembly at one location. The
+// activities and movements it caused reference it as KIT_BUILD.
+type KitBuild struct {
+	ID          string         `gorm:"type:uuid;primaryKey" json:"id"`
+	KitID       string         `gorm:"type:uuid;not null;index" json:"kit_id"`
+	Action      KitBuildAction `gorm:"not null" json:"action"`
+	Quantity    int            `gorm:"not null" json:"quantity"`
+	WarehouseID string         `gorm:"type:uuid" json:"warehouse_id"`
+	BinID       string         `json:"bin_id,omitempty"`
+	Description string         `json:"description"`
+	UserID      string         `gorm:"type:uuid;not null" json:"user_id"`
+	UserName    string         `json:"user_name"`
+	CreatedAt   time.Time      `json:"created_at"`
+}
+
+func (b *KitBuild) BeforeCreate(tx *gorm.DB) error {
+	b.ID = uuid.New().String()
+	return nil
+}
+
+type KitComponentRequest struct {
+	ItemID   string `json:"item_id" validate:"required"`
+	Quantity int    `json:"quantity" validate:"required,min=1"`
+}
+
+// SetKitComponentsRequest replaces a kit's bill of materials. An empty list
+// makes the item an ordinary item again.
+type SetKitComponentsRequest struct {
+	Components []KitComponentRequest `json:"components"`
+}
+
+type KitBuildRequest struct {
+	Quantity    int    `json:"quantity" validate:"required,min=1"`
+	WarehouseID string `json:"warehouse_id"`
+	BinID       string `json:"bin_id"`
+	Description string `json:"description"`
+	
+	// SerialNumbers lists the serials that move for each serialized item,
+	// kit or component, keyed by item ID.
+	SerialNumbers map[string][]string `json:"serial_numbers"`
+}
+
+// KitComponentAvailability is how many kits one component allows for.
+type KitComponentAvailability struct {
+	ItemID    string `json:"item_id"`
+	ItemName  string `json:"item_name"`
+	Quantity  int    `json:"quantity"`
+	Available int    `json:"available"`
+	Buildable int    `json:"buildable"`
+}
+
+// Kit is a kit's bill of materials with the number of kits its components'
+// available stock allows for.
+type Kit struct {
+	KitID            string                     `json:"kit_id"`
+	WarehouseID      string                     `json:"warehouse_id,omitempty"`
+	BinID            string                     `json:"bin_id,omitempty"`
+	AvailableToBuild int                        `json:"available_to_build"`
+	Components       []KitComponentAvailability `json:"components"`
+}
\ No newline at end of file
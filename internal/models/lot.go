@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LotStatus string
+
+const (
+	LotStatusAvailable   LotStatus = "available"
+	LotStatusQuarantined LotStatus = "quarantined"
+)
+
+// StockLot is the quantity of one lot of a lot-tracked item at one location.
+// For such items the lot quantities at a location add up to its ItemStock.
+type StockLot struct {
+	ID               string     `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID           string     `gorm:"type:uuid;not null;uniqueIndex:idx_stock_lots_location_lot" json:"item_id"`
+	Item             *Item      `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	WarehouseID      string     `gorm:"type:uuid;not null;uniqueIndex:idx_stock_lots_location_lot" json:"warehouse_id"`
+	BinID            string     `gorm:"not null;default:'';uniqueIndex:idx_stock_lots_location_lot" json:"bin_id"`
+	LotNumber        string     `gorm:"not null;uniqueIndex:idx_stock_lots_location_lot" json:"lot_number"`
+	ExpiresAt        time.Time  `gorm:"type:date;not null;index" json:"expires_at"`
+	Quantity         int        `gorm:"not null;default:0" json:"quantity"`
+	Status           LotStatus  `gorm:"not null;default:'available';index" json:"status"`
+	QuarantinedAt    *time.Time `json:"quarantined_at,omitempty"`
+	QuarantineReason string     `json:"quarantine_reason,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func (l *StockLot) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New().String()
+	return nil
+}
+
+type QuarantineLotRequest struct {
+	Reason string `json:"reason"`
+}
\ No newline at end of file
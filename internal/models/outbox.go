@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OutboxEvent is a domain event written in the same transaction as the
+// change it describes. The dispatcher publishes it to every configured
+// sink at least once; PublishedSinks records which sinks already have it so
+// a retry only goes to the ones that failed.
+type OutboxEvent struct {
+	ID             string     `gorm:"type:uuid;primaryKey" json:"id"`
+	DedupKey       string     `gorm:"uniqueIndex;not null" json:"dedup_key"`
+	EventType      string     `gorm:"not null;index" json:"event_type"`
+	AggregateType  string     `gorm:"not null" json:"aggregate_type"`
+	AggregateID    string     `gorm:"not null;index" json:"aggregate_id"`
+	Payload        string     `gorm:"type:text;not null" json:"payload"`
+	PublishedSinks []string   `gorm:"type:text;serializer:json" json:"published_sinks"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index:idx_outbox_events_due" json:"next_attempt_at"`
+	LastError      string     `json:"last_error,omitempty"`
+	PublishedAt    *time.Time `gorm:"index:idx_outbox_events_due" json:"published_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	e.ID = uuid.New().String()
+	return nil
+}
+
+// HasPublished reports whether sink already received the event.
+func (e *OutboxEvent) HasPublished(sink string) bool {
+	for _, name := range e.PublishedSinks {
+		if name == sink {
+			return true
+		}
+	}
+	return false
+}
+
+const AggregateTypeItem = "item"
+
+// ActivityEvent is the payload of an activity outbox event and the JSON
+// body posted to webhook subscribers.
+type ActivityEvent struct {
+	ID         string       `json:"id"`
+	DedupKey   string       `json:"dedup_key"`
+	Type       ActivityType `json:"type"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Category   string       `json:"category,omitempty"`
+	Data       *ActivityLog `json:"data"`
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusApproved          PurchaseOrderStatus = "approved"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusClosed            PurchaseOrderStatus = "closed"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+const ReferenceTypePurchaseOrder = "PURCHASE_ORDER"
+
+type Supplier struct {
+	ID          string    `gorm:"type:uuid;primaryKey" json:"id"`
+	Code        string    `gorm:"uniqueIndex;not null" json:"code"`
+	Name        string    `gorm:"not null" json:"name"`
+	ContactName string    `json:"contact_name"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	Address     string    `json:"address"`
+	IsActive    bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (s *Supplier) BeforeCreate(tx *gorm.DB) error {
+	s.ID = uuid.New().String()
+	return nil
+}
+
+type PurchaseOrder struct {
+	ID          string              `gorm:"type:uuid;primaryKey" json:"id"`
+	Number      string              `gorm:"uniqueIndex;not null" json:"number"`
+	SupplierID  string              `gorm:"type:uuid;not null;index" json:"supplier_id"`
+	Supplier    *Supplier           `gorm:"foreignKey:SupplierID" json:"supplier,omitempty"`
+	Status      PurchaseOrderStatus `gorm:"not null;index" json:"status"`
+	WarehouseID string              `json:"warehouse_id"`
+	ExpectedAt  *time.Time          `json:"expected_at,omitempty"`
+	Notes       string              `json:"notes"`
+	CreatedBy   string              `gorm:"not null" json:"created_by"`
+	ApprovedBy  string              `json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time          `json:"approved_at,omitempty"`
+	ClosedAt    *time.Time          `json:"closed_at,omitempty"`
+	Lines       []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID" json:"lines"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func (po *PurchaseOrder) BeforeCreate(tx *gorm.DB) error {
+	po.ID = uuid.New().String()
+	
+	if po.Number == "" {
+		po.Number = fmt.Sprintf("PO-%s-%s",
+			time.Now().Format("20060102"),
+			randomString(3),
+		)
+	}
+	return nil
+}
+
+type PurchaseOrderLine struct {
+	ID               string  `gorm:"type:uuid;primaryKey" json:"id"`
+	PurchaseOrderID  string  `gorm:"type:uuid;not null;index" json:"purchase_order_id"`
+	ItemID           string  `gorm:"type:uuid;not null;index" json:"item_id"`
+	Item             *Item   `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	QuantityOrdered  int     `gorm:"not null" json:"quantity_ordered"`
+	QuantityReceived int     `gorm:"not null;default:0" json:"quantity_received"`
+	UnitCost         float64 `gorm:"type:decimal(12,2);not null;default:0" json:"unit_cost"`
+}
+
+func (l *PurchaseOrderLine) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New().String()
+	return nil
+}
+
+func (l *PurchaseOrderLine) Outstanding() int {
+	return l.QuantityOrdered - l.QuantityReceived
+}
+
+type CreateSupplierRequest struct {
+	Code        string `json:"code" validate:"required"`
+	Name        string `json:"name" validate:"required"`
+	ContactName string `json:"contact_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	Address     string `json:"address"`
+}
+
+type UpdateSupplierRequest struct {
+	Name        string `json:"name"`
+	ContactName string `json:"contact_name"`
+	Email       string `json:"email"`
+	Phone       string `json:"phone"`
+	Address     string `json:"address"`
+	IsActive    *bool  `json:"is_active"`
+}
+
+type PurchaseOrderLineRequest struct {
+	ItemID   string  `json:"item_id" validate:"required"`
+	Quantity int     `json:"quantity" validate:"required,min=1"`
+	UnitCost float64 `json:"unit_cost" validate:"min=0"`
+}
+
+type CreatePurchaseOrderRequest struct {
+	SupplierID  string                     `json:"supplier_id" validate:"required"`
+	WarehouseID string                     `json:"warehouse_id"`
+	ExpectedAt  *time.Time                 `json:"expected_at"`
+	Notes       string                     `json:"notes"`
+	Lines       []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1"`
+}
+
+type ReceiveLineRequest struct {
+	LineID        string   `json:"line_id" validate:"required"`
+	Quantity      int      `json:"quantity" validate:"required,min=1"`
+	LotNumber     string   `json:"lot_number"`
+	ExpiresAt     string   `json:"expires_at"`
+	SerialNumbers []string `json:"serial_numbers"`
+}
+
+type ReceivePurchaseOrderRequest struct {
+	WarehouseID string               `json:"warehouse_id"`
+	BinID       string               `json:"bin_id"`
+	Notes       string               `json:"notes"`
+	Lines       []ReceiveLineRequest `json:"lines" validate:"required,min=1"`
+}
+
+type OnOrderQuantity struct {
+	ItemID   string `json:"item_id"`
+	ItemName string `json:"item_name"`
+	SKU      string `json:"sku"`
+	OnOrder  int    `json:"on_order"`
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	PermissionItemRead         = "item:read"
+	PermissionItemCreate       = "item:create"
+	PermissionItemUpdate       = "item:update"
+	PermissionItemDelete       = "item:delete"
+	PermissionStockAdjust      = "stock:adjust"
+	PermissionActivityRead     = "activity:read"
+	PermissionUserManage       = "user:manage"
+	PermissionRoleManage       = "role:manage"
+	PermissionWarehouseManage  = "warehouse:manage"
+	PermissionPurchaseRead     = "purchase:read"
+	PermissionPurchaseManage   = "purchase:manage"
+	PermissionPurchaseApprove  = "purchase:approve"
+	PermissionPurchaseReceive  = "purchase:receive"
+	PermissionSalesRead        = "sales:read"
+	PermissionSalesManage      = "sales:manage"
+	PermissionSalesFulfil      = "sales:fulfil"
+	PermissionWebhookManage    = "webhook:manage"
+	PermissionReportRead       = "report:read"
+	PermissionStocktakeCount   = "stocktake:count"
+	PermissionStocktakeManage  = "stocktake:manage"
+	PermissionStocktakeApprove = "stocktake:approve"
+	PermissionUnitManage       = "unit:manage"
+	PermissionAttributeManage  = "attribute:manage"
+	PermissionCategoryManage   = "category:manage"
+)
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+var DefaultPermissions = map[string]string{
+	PermissionItemRead:         "View items",
+	PermissionItemCreate:       "Create items",
+	PermissionItemUpdate:       "Edit item details",
+	PermissionItemDelete:       "Delete items",
+	PermissionStockAdjust:      "Increment and decrement stock",
+	PermissionActivityRead:     "View the activity log",
+	PermissionUserManage:       "Manage user accounts",
+	PermissionRoleManage:       "Manage roles and their permissions",
+	PermissionWarehouseManage:  "Manage warehouses and bins",
+	PermissionPurchaseRead:     "View suppliers and purchase orders",
+	PermissionPurchaseManage:   "Manage suppliers and draft purchase orders",
+	PermissionPurchaseApprove:  "Approve, cancel and close purchase orders",
+	PermissionPurchaseReceive:  "Receive goods against purchase orders",
+	PermissionSalesRead:        "View sales orders and reservations",
+	PermissionSalesManage:      "Create, confirm and cancel sales orders",
+	PermissionSalesFulfil:      "Pick, pack and ship sales orders",
+	PermissionWebhookManage:    "Manage webhook subscriptions and deliveries",
+	PermissionReportRead:       "View inventory valuation reports",
+	PermissionStocktakeCount:   "View stocktakes and submit counts",
+	PermissionStocktakeManage:  "Create and cancel stocktakes",
+	PermissionStocktakeApprove: "Approve stocktakes and post their adjustments",
+	PermissionUnitManage:       "Manage the unit-of-measure catalog",
+	PermissionAttributeManage:  "Manage custom item attributes per category",
+	PermissionCategoryManage:   "Manage the category tree",
+}
+
+// DefaultRolePermissions is applied when a built-in role is first created.
+// The admin role is always granted every permission.
+var DefaultRolePermissions = map[string][]string{
+	RoleUser: {
+		PermissionItemRead,
+		PermissionItemCreate,
+		PermissionItemUpdate,
+		PermissionStockAdjust,
+		PermissionActivityRead,
+	},
+}
+
+type Permission struct {
+	ID          string    `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string    `gorm:"uniqueIndex;not null" json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (p *Permission) BeforeCreate(tx *gorm.DB) error {
+	p.ID = uuid.New().String()
+	return nil
+}
+
+type Role struct {
+	ID          string       `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string       `gorm:"uniqueIndex;not null" json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+func (r *Role) BeforeCreate(tx *gorm.DB) error {
+	r.ID = uuid.New().String()
+	return nil
+}
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type UpdateRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SalesOrderStatus string
+
+const (
+	SalesOrderStatusDraft     SalesOrderStatus = "draft"
+	SalesOrderStatusConfirmed SalesOrderStatus = "confirmed"
+	SalesOrderStatusPicked    SalesOrderStatus = "picked"
+	SalesOrderStatusPacked    SalesOrderStatus = "packed"
+	SalesOrderStatusShipped   SalesOrderStatus = "shipped"
+	SalesOrderStatusCancelled SalesOrderStatus = "cancelled"
+	SalesOrderStatusExpired   SalesOrderStatus = "expired"
+)
+
+type ReservationStatus string
+
+const (
+	ReservationStatusActive    ReservationStatus = "active"
+	ReservationStatusFulfilled ReservationStatus = "fulfilled"
+	ReservationStatusReleased  ReservationStatus = "released"
+	ReservationStatusExpired   ReservationStatus = "expired"
+)
+
+const ReferenceTypeSalesOrder = "SALES_ORDER"
+
+type SalesOrder struct {
+	ID                   string           `gorm:"type:uuid;primaryKey" json:"id"`
+	Number               string           `gorm:"uniqueIndex;not null" json:"number"`
+	CustomerName         string           `gorm:"not null" json:"customer_name"`
+	CustomerReference    string           `json:"customer_reference"`
+	Status               SalesOrderStatus `gorm:"not null;index" json:"status"`
+	WarehouseID          string           `json:"warehouse_id"`
+	Notes                string           `json:"notes"`
+	CreatedBy            string           `gorm:"not null" json:"created_by"`
+	ReservationExpiresAt *time.Time       `json:"reservation_expires_at,omitempty"`
+	ConfirmedAt          *time.Time       `json:"confirmed_at,omitempty"`
+	ShippedAt            *time.Time       `json:"shipped_at,omitempty"`
+	Lines                []SalesOrderLine `gorm:"foreignKey:SalesOrderID" json:"lines"`
+	CreatedAt            time.Time        `json:"created_at"`
+	UpdatedAt            time.Time        `json:"updated_at"`
+}
+
+func (so *SalesOrder) BeforeCreate(tx *gorm.DB) error {
+	so.ID = uuid.New().String()
+	
+	if so.Number == "" {
+		so.Number = fmt.Sprintf("SO-%s-%s",
+			time.Now().Format("20060102"),
+			randomString(3),
+		)
+	}
+	return nil
+}
+
+type SalesOrderLine struct {
+	ID              string  `gorm:"type:uuid;primaryKey" json:"id"`
+	SalesOrderID    string  `gorm:"type:uuid;not null;index" json:"sales_order_id"`
+	ItemID          string  `gorm:"type:uuid;not null;index" json:"item_id"`
+	Item            *Item   `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Quantity        int     `gorm:"not null" json:"quantity"`
+	QuantityShipped int     `gorm:"not null;default:0" json:"quantity_shipped"`
+	UnitPrice       float64 `gorm:"type:decimal(12,2);not null;default:0" json:"unit_price"`
+}
+
+func (l *SalesOrderLine) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New().String()
+	return nil
+}
+
+// StockReservation holds quantity of an item for a sales order line in the
+// warehouse the order ships from. Active reservations are summed into
+// Item.ReservedStock.
+type StockReservation struct {
+	ID               string            `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID           string            `gorm:"type:uuid;not null;index" json:"item_id"`
+	WarehouseID      string            `gorm:"not null;default:'';index" json:"warehouse_id"`
+	SalesOrderID     string            `gorm:"type:uuid;not null;index" json:"sales_order_id"`
+	SalesOrderLineID string            `gorm:"type:uuid;not null;index" json:"sales_order_line_id"`
+	Quantity         int               `gorm:"not null" json:"quantity"`
+	Status           ReservationStatus `gorm:"not null;index:idx_stock_reservations_status_expiry" json:"status"`
+	ExpiresAt        time.Time         `gorm:"not null;index:idx_stock_reservations_status_expiry" json:"expires_at"`
+	ClosedAt         *time.Time        `json:"closed_at,omitempty"`
+	CreatedAt        time.Time         `json:"created_at"`
+}
+
+func (r *StockReservation) BeforeCreate(tx *gorm.DB) error {
+	r.ID = uuid.New().String()
+	return nil
+}
+
+type SalesOrderLineRequest struct {
+	ItemID    string  `json:"item_id" validate:"required"`
+	Quantity  int     `json:"quantity" validate:"required,min=1"`
+	UnitPrice float64 `json:"unit_price" validate:"min=0"`
+}
+
+type CreateSalesOrderRequest struct {
+	CustomerName      string                  `json:"customer_name" validate:"required"`
+	CustomerReference string                  `json:"customer_reference"`
+	WarehouseID       string                  `json:"warehouse_id"`
+	Notes             string                  `json:"notes"`
+	Lines             []SalesOrderLineRequest `json:"lines" validate:"required,min=1"`
+}
+
+// ShipLineRequest ships a line from one bin of the order's warehouse. Without
+// BinID the line is taken from the bins holding its serials, or else from
+// whichever bins hold stock.
+type ShipLineRequest struct {
+	LineID        string   `json:"line_id" validate:"required"`
+	BinID         string   `json:"bin_id"`
+	SerialNumbers []string `json:"serial_numbers"`
+}
+
+// ShipSalesOrderRequest names the bin and the serial numbers shipped on
+// each line. Orders without serialized items need no body.
+type ShipSalesOrderRequest struct {
+	Lines []ShipLineRequest `json:"lines"`
+}
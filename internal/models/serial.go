@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SerialStatus string
+
+const (
+	SerialStatusInStock SerialStatus = "in_stock"
+	SerialStatusOut     SerialStatus = "out"
+)
+
+// SerialNumber is one individually identified unit of a serialized item.
+// While in stock it sits at one location; the item's stock is the number of
+// its serials in stock.
+type SerialNumber struct {
+	ID          string        `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID      string        `gorm:"type:uuid;not null;uniqueIndex:idx_serial_numbers_item_serial" json:"item_id"`
+	Item        *Item         `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Serial      string        `gorm:"not null;uniqueIndex:idx_serial_numbers_item_serial;index" json:"serial"`
+	Status      SerialStatus  `gorm:"not null;index" json:"status"`
+	WarehouseID string        `gorm:"type:uuid" json:"warehouse_id,omitempty"`
+	BinID       string        `gorm:"not null;default:''" json:"bin_id,omitempty"`
+	Events      []SerialEvent `gorm:"foreignKey:SerialID" json:"events,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	UpdatedAt   time.Time     `json:"updated_at"`
+}
+
+func (s *SerialNumber) BeforeCreate(tx *gorm.DB) error {
+	s.ID = uuid.New().String()
+	return nil
+}
+
+// SerialEvent is one entry in a serial's history, pointing at the activity
+// that moved it. Status and location are as they stood after the event.
+type SerialEvent struct {
+	ID            string       `gorm:"type:uuid;primaryKey" json:"id"`
+	SerialID      string       `gorm:"type:uuid;not null;index" json:"serial_id"`
+	ActivityID    string       `gorm:"type:uuid;index" json:"activity_id,omitempty"`
+	Action        ActivityType `gorm:"not null" json:"action"`
+	Status        SerialStatus `gorm:"not null" json:"status"`
+	WarehouseID   string       `gorm:"type:uuid" json:"warehouse_id,omitempty"`
+	BinID         string       `json:"bin_id,omitempty"`
+	UserID        string       `gorm:"type:uuid" json:"user_id,omitempty"`
+	UserName      string       `json:"user_name,omitempty"`
+	Description   string       `json:"description,omitempty"`
+	ReferenceType string       `json:"reference_type,omitempty"`
+	ReferenceID   string       `json:"reference_id,omitempty"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+func (e *SerialEvent) BeforeCreate(tx *gorm.DB) error {
+	e.ID = uuid.New().String()
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StockAlertType string
+
+const (
+	StockAlertTypeLowStock  StockAlertType = "LOW_STOCK"
+	StockAlertTypeOverstock StockAlertType = "OVERSTOCK"
+)
+
+type StockAlertStatus string
+
+const (
+	StockAlertStatusOpen     StockAlertStatus = "open"
+	StockAlertStatusResolved StockAlertStatus = "resolved"
+)
+
+// StockAlert is raised when an item's stock leaves its MinStock..MaxStock
+// band and resolved once it is back inside. At most one alert of each type
+// is open per item; Stock and SuggestedQuantity track the latest change.
+type StockAlert struct {
+	ID                string           `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID            string           `gorm:"type:uuid;not null;index" json:"item_id"`
+	Item              *Item            `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	Type              StockAlertType   `gorm:"not null;index" json:"type"`
+	Status            StockAlertStatus `gorm:"not null;index" json:"status"`
+	Threshold         int              `gorm:"not null" json:"threshold"`
+	Stock             int              `gorm:"not null" json:"stock"`
+	SuggestedQuantity int              `gorm:"not null;default:0" json:"suggested_quantity"`
+	ResolvedAt        *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
+}
+
+func (a *StockAlert) BeforeCreate(tx *gorm.DB) error {
+	a.ID = uuid.New().String()
+	return nil
+}
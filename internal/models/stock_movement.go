@@ -0,0 +1,125 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ReasonCodeInitialStock   = "INITIAL_STOCK"
+	ReasonCodeOpeningBalance = "OPENING_BALANCE"
+	ReasonCodeReceipt        = "RECEIPT"
+	ReasonCodeIssue          = "ISSUE"
+	ReasonCodeShipment       = "SHIPMENT"
+	ReasonCodeTransferOut    = "TRANSFER_OUT"
+	ReasonCodeTransferIn     = "TRANSFER_IN"
+	ReasonCodeReturn         = "RETURN"
+	ReasonCodeDamage         = "DAMAGE"
+	ReasonCodeCorrection     = "CORRECTION"
+	ReasonCodeRevaluation    = "REVALUATION"
+	ReasonCodeAssembly       = "ASSEMBLY"
+	ReasonCodeDisassembly    = "DISASSEMBLY"
+)
+
+// ReasonCodes lists the codes clients may send on a stock update.
+var ReasonCodes = map[string]bool{
+	ReasonCodeReceipt:    true,
+	ReasonCodeIssue:      true,
+	ReasonCodeReturn:     true,
+	ReasonCodeDamage:     true,
+	ReasonCodeCorrection: true,
+}
+
+// SystemReasonCodes are written only by the paths they describe: item
+// creation, the opening-balance seeder, transfers, sales-order shipments,
+// kit builds and revaluations, which change value without quantity.
+var SystemReasonCodes = map[string]bool{
+	ReasonCodeInitialStock:   true,
+	ReasonCodeOpeningBalance: true,
+	ReasonCodeTransferOut:    true,
+	ReasonCodeTransferIn:     true,
+	ReasonCodeShipment:       true,
+	ReasonCodeAssembly:       true,
+	ReasonCodeDisassembly:    true,
+	ReasonCodeRevaluation:    true,
+}
+
+// COGSReasonCodes are the movements that make up cost of goods sold: stock
+// issued or shipped to customers, net of customer returns. Damage,
+// transfers and kit builds are not sales and stay out.
+var COGSReasonCodes = []string{
+	ReasonCodeIssue,
+	ReasonCodeShipment,
+	ReasonCodeReturn,
+}
+
+var ErrLedgerImmutable = errors.New("stock movements are append-only")
+
+// StockMovement is one entry of the append-only stock ledger. The sum of an
+// item's Quantity values is its on-hand stock; Item.Stock caches that sum.
+// TotalCost is the signed change in inventory value, so its sum is the
+// on-hand value; for decrements it is the negated COGS.
+type StockMovement struct {
+	ID            string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID        string    `gorm:"type:uuid;not null;index:idx_stock_movements_item_time" json:"item_id"`
+	WarehouseID   string    `gorm:"type:uuid;not null" json:"warehouse_id"`
+	BinID         string    `gorm:"not null;default:''" json:"bin_id"`
+	Quantity      int       `gorm:"not null" json:"quantity"`
+	UnitCost      float64   `gorm:"type:decimal(12,2);not null;default:0" json:"unit_cost"`
+	TotalCost     float64   `gorm:"type:decimal(14,2);not null;default:0" json:"total_cost"`
+	ReasonCode    string    `gorm:"not null" json:"reason_code"`
+	ReferenceType string    `gorm:"index:idx_stock_movements_reference" json:"reference_type,omitempty"`
+	ReferenceID   string    `gorm:"index:idx_stock_movements_reference" json:"reference_id,omitempty"`
+	LotNumber     string    `json:"lot_number,omitempty"`
+	ActivityID    string    `json:"activity_id,omitempty"`
+	UserID        string    `gorm:"not null" json:"user_id"`
+	CreatedAt     time.Time `gorm:"not null;index:idx_stock_movements_item_time" json:"created_at"`
+}
+
+func (m *StockMovement) BeforeCreate(tx *gorm.DB) error {
+	m.ID = uuid.New().String()
+	return nil
+}
+
+func (m *StockMovement) BeforeUpdate(tx *gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+func (m *StockMovement) BeforeDelete(tx *gorm.DB) error {
+	return ErrLedgerImmutable
+}
+
+type LocationStock struct {
+	WarehouseID string `json:"warehouse_id"`
+	BinID       string `json:"bin_id"`
+	Quantity    int    `json:"quantity"`
+}
+
+type StockAsOf struct {
+	ItemID    string          `json:"item_id"`
+	AsOf      time.Time       `json:"as_of"`
+	Stock     int             `json:"stock"`
+	Locations []LocationStock `json:"locations"`
+}
+
+type LocationDrift struct {
+	WarehouseID string `json:"warehouse_id"`
+	BinID       string `json:"bin_id"`
+	CachedStock int    `json:"cached_stock"`
+	LedgerStock int    `json:"ledger_stock"`
+	Drift       int    `json:"drift"`
+}
+
+type StockReconciliation struct {
+	ItemID      string          `json:"item_id"`
+	ItemName    string          `json:"item_name"`
+	SKU         string          `json:"sku"`
+	CachedStock int             `json:"cached_stock"`
+	LedgerStock int             `json:"ledger_stock"`
+	Drift       int             `json:"drift"`
+	InSync      bool            `json:"in_sync"`
+	Locations   []LocationDrift `json:"locations,omitempty"`
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StocktakeStatus string
+
+const (
+	StocktakeStatusCounting  StocktakeStatus = "counting"
+	StocktakeStatusApproved  StocktakeStatus = "approved"
+	StocktakeStatusCancelled StocktakeStatus = "cancelled"
+)
+
+const ReferenceTypeStocktake = "STOCKTAKE"
+
+// Stocktake is a counting session. Expected quantities are frozen on its
+// lines when it is created; approving it posts the variances as adjustments.
+type Stocktake struct {
+	ID          string          `gorm:"type:uuid;primaryKey" json:"id"`
+	Number      string          `gorm:"uniqueIndex;not null" json:"number"`
+	Status      StocktakeStatus `gorm:"not null;index" json:"status"`
+	WarehouseID string          `json:"warehouse_id,omitempty"`
+	BinID       string          `json:"bin_id,omitempty"`
+	Notes       string          `json:"notes"`
+	CreatedBy   string          `gorm:"not null" json:"created_by"`
+	ApprovedBy  string          `json:"approved_by,omitempty"`
+	ApprovedAt  *time.Time      `json:"approved_at,omitempty"`
+	ClosedAt    *time.Time      `json:"closed_at,omitempty"`
+	Lines       []StocktakeLine `gorm:"foreignKey:StocktakeID" json:"lines,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+func (st *Stocktake) BeforeCreate(tx *gorm.DB) error {
+	st.ID = uuid.New().String()
+	
+	if st.Number == "" {
+		st.Number = fmt.Sprintf("ST-%s-%s",
+			time.Now().Format("20060102"),
+			randomString(3),
+		)
+	}
+	return nil
+}
+
+// StocktakeLine is one item at one location, and for lot-tracked items one
+// lot. CountedQuantity is the sum of the latest count from each device, and
+// stays nil until something is counted.
+type StocktakeLine struct {
+	ID               string     `gorm:"type:uuid;primaryKey" json:"id"`
+	StocktakeID      string     `gorm:"type:uuid;not null;index" json:"stocktake_id"`
+	ItemID           string     `gorm:"type:uuid;not null;index" json:"item_id"`
+	Item             *Item      `gorm:"foreignKey:ItemID" json:"item,omitempty"`
+	WarehouseID      string     `gorm:"type:uuid;not null" json:"warehouse_id"`
+	BinID            string     `gorm:"not null;default:''" json:"bin_id"`
+	LotNumber        string     `gorm:"not null;default:''" json:"lot_number,omitempty"`
+	ExpiresAt        *time.Time `gorm:"type:date" json:"expires_at,omitempty"`
+	ExpectedQuantity int        `gorm:"not null" json:"expected_quantity"`
+	CountedQuantity  *int       `json:"counted_quantity"`
+	CountedAt        *time.Time `json:"counted_at,omitempty"`
+	Adjustment       int        `gorm:"not null;default:0" json:"adjustment"`
+}
+
+func (l *StocktakeLine) BeforeCreate(tx *gorm.DB) error {
+	l.ID = uuid.New().String()
+	return nil
+}
+
+func (l *StocktakeLine) Variance() int {
+	if l.CountedQuantity == nil {
+		return 0
+	}
+	return *l.CountedQuantity - l.ExpectedQuantity
+}
+
+// StocktakeCount is what one device counted for a line. A device that
+// counts the same line again replaces its earlier count. Serialized items
+// keep the serial numbers that were counted.
+type StocktakeCount struct {
+	ID            string    `gorm:"type:uuid;primaryKey" json:"id"`
+	StocktakeID   string    `gorm:"type:uuid;not null;index" json:"stocktake_id"`
+	LineID        string    `gorm:"type:uuid;not null;uniqueIndex:idx_stocktake_counts_line_device" json:"line_id"`
+	DeviceID      string    `gorm:"not null;uniqueIndex:idx_stocktake_counts_line_device" json:"device_id"`
+	Quantity      int       `gorm:"not null" json:"quantity"`
+	SerialNumbers []string  `gorm:"type:text;serializer:json" json:"serial_numbers,omitempty"`
+	CountedBy     string    `gorm:"not null" json:"counted_by"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (c *StocktakeCount) BeforeCreate(tx *gorm.DB) error {
+	c.ID = uuid.New().String()
+	return nil
+}
+
+// CreateStocktakeRequest scopes the session. With nothing set it covers
+// every stocked location; ItemIDs limits it to those items, and items with
+// no stock in the given warehouse still get a line expecting zero.
+type CreateStocktakeRequest struct {
+	WarehouseID string   `json:"warehouse_id"`
+	BinID       string   `json:"bin_id"`
+	ItemIDs     []string `json:"item_ids"`
+	Notes       string   `json:"notes"`
+}
+
+// StocktakeCountEntry names a line directly or by item, with warehouse and
+// bin when the item appears at more than one location. Lot-tracked items are
+// counted per lot; a lot the session does not list yet is added with an
+// expected quantity of zero, and needs ExpiresAt unless the lot is known.
+// Serialized items are counted by serial number, one per unit.
+type StocktakeCountEntry struct {
+	LineID        string   `json:"line_id"`
+	ItemID        string   `json:"item_id"`
+	WarehouseID   string   `json:"warehouse_id"`
+	BinID         string   `json:"bin_id"`
+	LotNumber     string   `json:"lot_number"`
+	ExpiresAt     string   `json:"expires_at"`
+	SerialNumbers []string `json:"serial_numbers"`
+	Quantity      int      `json:"quantity" validate:"min=0"`
+}
+
+type SubmitStocktakeCountsRequest struct {
+	DeviceID string                `json:"device_id"`
+	Counts   []StocktakeCountEntry `json:"counts" validate:"required,min=1"`
+}
+
+type StocktakeVarianceLine struct {
+	LineID        string  `json:"line_id"`
+	ItemID        string  `json:"item_id"`
+	ItemName      string  `json:"item_name"`
+	SKU           string  `json:"sku"`
+	WarehouseID   string  `json:"warehouse_id"`
+	BinID         string  `json:"bin_id"`
+	LotNumber     string  `json:"lot_number,omitempty"`
+	Expected      int     `json:"expected"`
+	Counted       *int    `json:"counted"`
+	Variance      int     `json:"variance"`
+	VarianceValue float64 `json:"variance_value"`
+	Devices       int     `json:"devices"`
+}
+
+type StocktakeVariance struct {
+	StocktakeID       string                  `json:"stocktake_id"`
+	Number            string                  `json:"number"`
+	Status            StocktakeStatus         `json:"status"`
+	TotalLines        int                     `json:"total_lines"`
+	CountedLines      int                     `json:"counted_lines"`
+	UncountedLines    int                     `json:"uncounted_lines"`
+	LinesWithVariance int                     `json:"lines_with_variance"`
+	NetVariance       int                     `json:"net_variance"`
+	NetVarianceValue  float64                 `json:"net_variance_value"`
+	Lines             []StocktakeVarianceLine `json:"lines"`
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultBaseUnit is the base unit of items that do not name one.
+const DefaultBaseUnit = "EA"
+
+// Unit is an entry in the unit-of-measure catalog, such as EA, BOX or REAM.
+type Unit struct {
+	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
+	Code      string    `gorm:"uniqueIndex;not null" json:"code"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (u *Unit) BeforeCreate(tx *gorm.DB) error {
+	u.ID = uuid.New().String()
+	return nil
+}
+
+// ItemUnit converts one of an item's alternative units into its base unit:
+// one UnitCode holds Factor base units.
+type ItemUnit struct {
+	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID    string    `gorm:"type:uuid;not null;uniqueIndex:idx_item_units_item_unit" json:"item_id"`
+	UnitCode  string    `gorm:"not null;uniqueIndex:idx_item_units_item_unit;index" json:"unit"`
+	Factor    int       `gorm:"not null" json:"factor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (u *ItemUnit) BeforeCreate(tx *gorm.DB) error {
+	u.ID = uuid.New().String()
+	return nil
+}
+
+type CreateUnitRequest struct {
+	Code string `json:"code" validate:"required"`
+	Name string `json:"name" validate:"required"`
+}
+
+type ItemUnitRequest struct {
+	Unit   string `json:"unit" validate:"required"`
+	Factor int    `json:"factor" validate:"required,min=1"`
+}
+
+// SetItemUnitsRequest replaces an item's units. Purchase and issue units
+// default to the base unit and must be the base unit or a converted unit.
+type SetItemUnitsRequest struct {
+	BaseUnit     string            `json:"base_unit"`
+	PurchaseUnit string            `json:"purchase_unit"`
+	IssueUnit    string            `json:"issue_unit"`
+	Conversions  []ItemUnitRequest `json:"conversions"`
+}
+
+type ItemUnits struct {
+	ItemID       string     `json:"item_id"`
+	BaseUnit     string     `json:"base_unit"`
+	PurchaseUnit string     `json:"purchase_unit"`
+	IssueUnit    string     `json:"issue_unit"`
+	Conversions  []ItemUnit `json:"conversions"`
+}
\ No newline at end of file
@@ -14,6 +14,10 @@ type User struct {
 	Email     string    `gorm:"uniqueIndex;not null" json:"email"`
 	Password  string    `gorm:"not null" json:"-"`
 	Role      string    `gorm:"default:user" json:"role"`
+	IsActive  bool      `gorm:"not null;default:true" json:"is_active"`
+	
+	MustChangePassword bool `gorm:"not null;default:false" json:"must_change_password"`
+	
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -42,4 +46,18 @@ type RegisterRequest struct {
 	Name     string `json:"name" validate:"required"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
-}
\ No newline at end of file
+}
+
+type UpdateUserRequest struct {
+	Name string `json:"name"`
+	Role string `json:"role"`
+}
+
+type ResetPasswordRequest struct {
+	Password string `json:"password" validate:"omitempty,min=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=6"`
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Warehouse struct {
+	ID        string    `gorm:"type:uuid;primaryKey" json:"id"`
+	Code      string    `gorm:"uniqueIndex;not null" json:"code"`
+	Name      string    `gorm:"not null" json:"name"`
+	Address   string    `json:"address"`
+	IsDefault bool      `gorm:"not null;default:false" json:"is_default"`
+	Bins      []Bin     `gorm:"foreignKey:WarehouseID" json:"bins,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (w *Warehouse) BeforeCreate(tx *gorm.DB) error {
+	w.ID = uuid.New().String()
+	return nil
+}
+
+type Bin struct {
+	ID          string    `gorm:"type:uuid;primaryKey" json:"id"`
+	WarehouseID string    `gorm:"type:uuid;not null;uniqueIndex:idx_bins_warehouse_code" json:"warehouse_id"`
+	Code        string    `gorm:"not null;uniqueIndex:idx_bins_warehouse_code" json:"code"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (b *Bin) BeforeCreate(tx *gorm.DB) error {
+	b.ID = uuid.New().String()
+	return nil
+}
+
+// ItemStock is the quantity of one item held at one warehouse, optionally in
+// a specific bin. BinID is empty for stock that is not assigned to a bin.
+// The sum of an item's rows is cached in Item.Stock.
+type ItemStock struct {
+	ID          string     `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID      string     `gorm:"type:uuid;not null;uniqueIndex:idx_item_stocks_location" json:"item_id"`
+	WarehouseID string     `gorm:"type:uuid;not null;uniqueIndex:idx_item_stocks_location" json:"warehouse_id"`
+	BinID       string     `gorm:"not null;default:'';uniqueIndex:idx_item_stocks_location" json:"bin_id"`
+	Quantity    int        `gorm:"not null;default:0" json:"quantity"`
+	BinCode     string     `gorm:"->;-:migration" json:"bin_code,omitempty"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID" json:"warehouse,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func (s *ItemStock) BeforeCreate(tx *gorm.DB) error {
+	s.ID = uuid.New().String()
+	return nil
+}
+
+type CreateWarehouseRequest struct {
+	Code      string `json:"code" validate:"required"`
+	Name      string `json:"name" validate:"required"`
+	Address   string `json:"address"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type UpdateWarehouseRequest struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	IsDefault bool   `json:"is_default"`
+}
+
+type CreateBinRequest struct {
+	Code        string `json:"code" validate:"required"`
+	Description string `json:"description"`
+}
+
+type TransferStockRequest struct {
+	FromWarehouseID string `json:"from_warehouse_id" validate:"required"`
+	FromBinID       string `json:"from_bin_id"`
+	ToWarehouseID   string `json:"to_warehouse_id" validate:"required"`
+	ToBinID         string `json:"to_bin_id"`
+	Quantity        int    `json:"quantity" validate:"required,min=1"`
+	Reason          string `json:"reason"`
+	LotNumber       string `json:"lot_number"`
+	
+	SerialNumbers []string `json:"serial_numbers"`
+}
@@ -0,0 +1,122 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryStatusPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryStatusSucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryStatusFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookSubscription receives every activity whose type is listed in
+// EventTypes (all types when empty), optionally narrowed to one item
+// category or to a single item.
+type WebhookSubscription struct {
+	ID         string         `gorm:"type:uuid;primaryKey" json:"id"`
+	Name       string         `gorm:"not null" json:"name"`
+	URL        string         `gorm:"not null" json:"url"`
+	Secret     string         `gorm:"not null" json:"-"`
+	EventTypes []ActivityType `gorm:"type:text;serializer:json" json:"event_types"`
+	Category   string         `json:"category,omitempty"`
+	ItemID     string         `json:"item_id,omitempty"`
+	IsActive   bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedBy  string         `gorm:"not null" json:"created_by"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+}
+
+func (w *WebhookSubscription) BeforeCreate(tx *gorm.DB) error {
+	w.ID = uuid.New().String()
+	return nil
+}
+
+// Matches reports whether an activity on an item in category should be
+// delivered to this subscription.
+func (w *WebhookSubscription) Matches(activity *ActivityLog, category string) bool {
+	if !w.IsActive {
+		return false
+	}
+	if w.ItemID != "" && w.ItemID != activity.ItemID {
+		return false
+	}
+	if w.Category != "" && w.Category != category {
+		return false
+	}
+	if len(w.EventTypes) == 0 {
+		return true
+	}
+	for _, eventType := range w.EventTypes {
+		if eventType == activity.Action {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one event queued for one subscription. Replays create
+// a new delivery with the same EventID so receivers can deduplicate; only
+// the first delivery of an event is unique per subscription.
+type WebhookDelivery struct {
+	ID             string                `gorm:"type:uuid;primaryKey" json:"id"`
+	SubscriptionID string                `gorm:"type:uuid;not null;uniqueIndex:idx_webhook_deliveries_first,where:replay_of = ''" json:"subscription_id"`
+	EventID        string                `gorm:"not null;uniqueIndex:idx_webhook_deliveries_first" json:"event_id"`
+	EventType      ActivityType          `gorm:"not null" json:"event_type"`
+	Payload        string                `gorm:"type:text;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"not null;index:idx_webhook_deliveries_due" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastError      string                `json:"last_error,omitempty"`
+	ReplayOf       string                `gorm:"not null;default:'';index" json:"replay_of,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	AttemptLog     []WebhookAttempt      `gorm:"foreignKey:DeliveryID" json:"attempt_log,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	d.ID = uuid.New().String()
+	return nil
+}
+
+type WebhookAttempt struct {
+	ID           string    `gorm:"type:uuid;primaryKey" json:"id"`
+	DeliveryID   string    `gorm:"type:uuid;not null;index" json:"delivery_id"`
+	Attempt      int       `gorm:"not null" json:"attempt"`
+	StatusCode   int       `json:"status_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	ResponseBody string    `gorm:"type:text" json:"response_body,omitempty"`
+	DurationMs   int64     `json:"duration_ms"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (a *WebhookAttempt) BeforeCreate(tx *gorm.DB) error {
+	a.ID = uuid.New().String()
+	return nil
+}
+
+type CreateWebhookRequest struct {
+	Name       string         `json:"name"`
+	URL        string         `json:"url"`
+	Secret     string         `json:"secret"`
+	EventTypes []ActivityType `json:"event_types"`
+	Category   string         `json:"category"`
+	ItemID     string         `json:"item_id"`
+}
+
+type UpdateWebhookRequest struct {
+	Name       string         `json:"name"`
+	URL        string         `json:"url"`
+	Secret     string         `json:"secret"`
+	EventTypes []ActivityType `json:"event_types"`
+	Category   *string        `json:"category"`
+	ItemID     *string        `json:"item_id"`
+	IsActive   *bool          `json:"is_active"`
+}
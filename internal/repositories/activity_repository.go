@@ -15,13 +15,17 @@ func NewActivityRepository() *ActivityRepository {
 	return &ActivityRepository{db: database.DB}
 }
 
+func (r *ActivityRepository) WithTx(tx *gorm.DB) *ActivityRepository {
+	return &ActivityRepository{db: tx}
+}
+
 func (r *ActivityRepository) Create(activity *models.ActivityLog) error {
-	return database.DB.Create(activity).Error
+	return r.db.Create(activity).Error
 }
 
 func (r *ActivityRepository) FindByItemID(itemID string) ([]models.ActivityLog, error) {
 	var activities []models.ActivityLog
-	err := database.DB.Where("item_id = ?", itemID).
+	err := r.db.Where("item_id = ?", itemID).
 		Order("created_at DESC").
 		Find(&activities).Error
 	return activities, err
@@ -29,6 +33,6 @@ func (r *ActivityRepository) FindByItemID(itemID string) ([]models.ActivityLog,
 
 func (r *ActivityRepository) FindAll() ([]models.ActivityLog, error) {
 	var activities []models.ActivityLog
-	err := database.DB.Order("created_at DESC").Find(&activities).Error
+	err := r.db.Order("created_at DESC").Find(&activities).Error
 	return activities, err
 }
\ No newline at end of file
@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AttributeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository() *AttributeRepository {
+	return &AttributeRepository{db: database.DB}
+}
+
+func (r *AttributeRepository) WithTx(tx *gorm.DB) *AttributeRepository {
+	return &AttributeRepository{db: tx}
+}
+
+func (r *AttributeRepository) Create(definition *models.AttributeDefinition) error {
+	return r.db.Create(definition).Error
+}
+
+func (r *AttributeRepository) FindAll(category string) ([]models.AttributeDefinition, error) {
+	var definitions []models.AttributeDefinition
+	query := r.db.Model(&models.AttributeDefinition{})
+	if category != "" {
+		query = query.Where("LOWER(category) = LOWER(?)", category)
+	}
+	err := query.Order("category ASC, key ASC").Find(&definitions).Error
+	return definitions, err
+}
+
+func (r *AttributeRepository) FindByID(id string) (*models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+	err := r.db.Where("id = ?", id).First(&definition).Error
+	return &definition, err
+}
+
+// FindByCategory returns a category's definitions keyed by attribute key.
+func (r *AttributeRepository) FindByCategory(category string) (map[string]*models.AttributeDefinition, error) {
+	definitions, err := r.FindAll(category)
+	if err != nil {
+		return nil, err
+	}
+	
+	byKey := make(map[string]*models.AttributeDefinition, len(definitions))
+	for i := range definitions {
+		byKey[definitions[i].Key] = &definitions[i]
+	}
+	return byKey, nil
+}
+
+func (r *AttributeRepository) ExistsInCategory(category, key string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AttributeDefinition{}).
+		Where("LOWER(category) = LOWER(?) AND key = ?", category, key).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Delete removes a definition and the values items of its category hold
+// for it.
+func (r *AttributeRepository) Delete(definition *models.AttributeDefinition) error {
+	err := r.db.Model(&models.Item{}).
+		Where("LOWER(category) = LOWER(?)", definition.Category).
+		Where("jsonb_exists(attributes, ?)", definition.Key).
+		Updates(map[string]interface{}{
+			"attributes": gorm.Expr("attributes - ?::text", definition.Key),
+			"version":    gorm.Expr("version + 1"),
+		}).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Where("id = ?", definition.ID).Delete(&models.AttributeDefinition{}).Error
+}
\ No newline at end of file
@@ -0,0 +1,117 @@
+package repositories
+
+import (
+	"errors"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// categorySubtree selects the IDs of the categories matching where and of
+// every category below them. UNION drops rows already seen, so the query
+// ends even if the tree somehow holds a cycle.
+func categorySubtree(where string) string {
+	return `WITH RECURSIVE subtree AS (
+		SELECT id FROM categories WHERE ` + where + `
+		UNION
+		SELECT categories.id FROM categories JOIN subtree ON categories.parent_id = subtree.id
+	) SELECT id FROM subtree`
+}
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository() *CategoryRepository {
+	return &CategoryRepository{db: database.DB}
+}
+
+func (r *CategoryRepository) WithTx(tx *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: tx}
+}
+
+func (r *CategoryRepository) FindAll() ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// LockMoves takes a transaction-scoped lock that every category move holds,
+// so two moves cannot each check the tree before the other has changed it.
+func (r *CategoryRepository) LockMoves() error {
+	return r.db.Exec("SELECT pg_advisory_xact_lock(hashtext('categories.move'))").Error
+}
+
+func (r *CategoryRepository) FindByID(id string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("id = ?", id).First(&category).Error
+	return &category, err
+}
+
+// FindByName matches the name regardless of case and returns nil, nil when
+// there is no such category.
+func (r *CategoryRepository) FindByName(name string) (*models.Category, error) {
+	var category models.Category
+	err := r.db.Where("LOWER(name) = LOWER(?)", name).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepository) Create(category *models.Category) error {
+	return r.db.Create(category).Error
+}
+
+func (r *CategoryRepository) Update(category *models.Category) error {
+	return r.db.Omit("created_at").Save(category).Error
+}
+
+// Rename carries a new category name over to the places that still refer to
+// categories by name: items, attribute definitions and webhook filters.
+func (r *CategoryRepository) Rename(category *models.Category, oldName string) error {
+	err := r.db.Model(&models.Item{}).
+		Where("category_id = ?", category.ID).
+		Updates(map[string]interface{}{"category": category.Name, "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		return err
+	}
+	if err := r.db.Model(&models.AttributeDefinition{}).Where("LOWER(category) = LOWER(?)", oldName).Update("category", category.Name).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.WebhookSubscription{}).Where("LOWER(category) = LOWER(?)", oldName).Update("category", category.Name).Error
+}
+
+func (r *CategoryRepository) CountChildren(id string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *CategoryRepository) CountItems(id string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Item{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *CategoryRepository) CountAttributes(name string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.AttributeDefinition{}).Where("LOWER(category) = LOWER(?)", name).Count(&count).Error
+	return count, err
+}
+
+// MoveItems puts every item of one category into another.
+func (r *CategoryRepository) MoveItems(fromID string, to *models.Category) error {
+	return r.db.Model(&models.Item{}).
+		Where("category_id = ?", fromID).
+		Updates(map[string]interface{}{"category_id": to.ID, "category": to.Name, "version": gorm.Expr("version + 1")}).Error
+}
+
+func (r *CategoryRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&models.Category{}).Error
+}
\ No newline at end of file
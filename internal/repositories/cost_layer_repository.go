@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CostLayerRepository struct {
+	db *gorm.DB
+}
+
+func NewCostLayerRepository() *CostLayerRepository {
+	return &CostLayerRepository{db: database.DB}
+}
+
+func (r *CostLayerRepository) WithTx(tx *gorm.DB) *CostLayerRepository {
+	return &CostLayerRepository{db: tx}
+}
+
+func (r *CostLayerRepository) Create(layer *models.CostLayer) error {
+	return r.db.Create(layer).Error
+}
+
+// FindOpenForUpdate locks the layers of one item in one warehouse that still
+// hold stock, oldest first, or newest first when newestFirst is set.
+func (r *CostLayerRepository) FindOpenForUpdate(itemID, warehouseID string, newestFirst bool) ([]models.CostLayer, error) {
+	order := "created_at ASC, id ASC"
+	if newestFirst {
+		order = "created_at DESC, id DESC"
+	}
+	
+	var layers []models.CostLayer
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ? AND remaining > 0", itemID, warehouseID).
+		Order(order).
+		Find(&layers).Error
+	return layers, err
+}
+
+func (r *CostLayerRepository) UpdateRemaining(id string, remaining int) error {
+	return r.db.Model(&models.CostLayer{}).
+		Where("id = ?", id).
+		Update("remaining", remaining).Error
+}
+
+// Reprice sets one unit cost on every open layer of an item in a warehouse,
+// which is how the moving average is carried forward.
+func (r *CostLayerRepository) Reprice(itemID, warehouseID string, unitCost float64) error {
+	return r.db.Model(&models.CostLayer{}).
+		Where("item_id = ? AND warehouse_id = ? AND remaining > 0", itemID, warehouseID).
+		Update("unit_cost", unitCost).Error
+}
+
+func (r *CostLayerRepository) FindOpenByItemID(itemID string) ([]models.CostLayer, error) {
+	var layers []models.CostLayer
+	err := r.db.Where("item_id = ? AND remaining > 0", itemID).
+		Order("warehouse_id, created_at ASC").
+		Find(&layers).Error
+	return layers, err
+}
+
+func (r *CostLayerRepository) DeleteByItemID(itemID string) error {
+	return r.db.Where("item_id = ?", itemID).Delete(&models.CostLayer{}).Error
+}
\ No newline at end of file
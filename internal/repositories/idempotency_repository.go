@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository() *IdempotencyRepository {
+	return &IdempotencyRepository{db: database.DB}
+}
+
+func (r *IdempotencyRepository) WithTx(tx *gorm.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: tx}
+}
+
+// Claim inserts the key unless the user already holds it, reporting whether
+// this call created it. Of two concurrent requests with the same key only
+// one claims it.
+func (r *IdempotencyRepository) Claim(key *models.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *IdempotencyRepository) FindByKey(userID, key string) (*models.IdempotencyKey, error) {
+	var record models.IdempotencyKey
+	err := r.db.Where("user_id = ? AND key = ?", userID, key).First(&record).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *IdempotencyRepository) Complete(key *models.IdempotencyKey) error {
+	return r.db.Model(key).Updates(map[string]interface{}{
+		"status_code":   key.StatusCode,
+		"content_type":  key.ContentType,
+		"etag":          key.ETag,
+		"response_body": key.ResponseBody,
+	}).Error
+}
+
+func (r *IdempotencyRepository) Delete(id string) error {
+	return r.db.Where("id = ?", id).Delete(&models.IdempotencyKey{}).Error
+}
+
+func (r *IdempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
\ No newline at end of file
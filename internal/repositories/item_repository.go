@@ -1,10 +1,14 @@
 package repositories
 
 import (
+	"sort"
+	"strings"
+
 	"inventory-api/internal/database"
 	"inventory-api/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ItemRepository struct {
@@ -15,8 +19,12 @@ func NewItemRepository() *ItemRepository {
 	return &ItemRepository{db: database.DB}
 }
 
+func (r *ItemRepository) WithTx(tx *gorm.DB) *ItemRepository {
+	return &ItemRepository{db: tx}
+}
+
 func (r *ItemRepository) Create(item *models.Item) error {
-	return database.DB.Create(item).Error
+	return r.db.Create(item).Error
 }
 
 func (r *ItemRepository) FindAll() ([]models.Item, error) {
@@ -27,6 +35,106 @@ func (r *ItemRepository) FindAll() ([]models.Item, error) {
 	return items, err
 }
 
+func (r *ItemRepository) FindAllWithFilter(filter *models.ItemFilter, page, limit int) ([]models.Item, int64, error) {
+	var items []models.Item
+	var total int64
+	
+	query := r.filtered(filter)
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	query = sortedItems(query, filter)
+	
+	offset := (page - 1) * limit
+	err := query.Preload("Creator", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name")
+	}).Limit(limit).Offset(offset).Find(&items).Error
+	
+	return items, total, err
+}
+
+// EachWithFilter calls fn for every item matching filter, in filter order,
+// reading from a cursor so the full result never has to fit in memory.
+func (r *ItemRepository) EachWithFilter(filter *models.ItemFilter, fn func(*models.Item) error) error {
+	rows, err := sortedItems(r.filtered(filter), filter).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var item models.Item
+		if err := r.db.ScanRows(rows, &item); err != nil {
+			return err
+		}
+		item.AfterFind(r.db)
+		
+		if err := fn(&item); err != nil {
+			return err
+		}
+	}
+	
+	return rows.Err()
+}
+
+func (r *ItemRepository) filtered(filter *models.ItemFilter) *gorm.DB {
+	query := r.db.Model(&models.Item{})
+	
+	if filter.Category != "" {
+		query = query.Where("category_id IN ("+categorySubtree("LOWER(name) = LOWER(?)")+")", filter.Category)
+	}
+	if filter.CategoryID != "" {
+		query = query.Where("category_id IN ("+categorySubtree("id = ?")+")", filter.CategoryID)
+	}
+	if filter.Location != "" {
+		query = query.Where("LOWER(location) = LOWER(?)", filter.Location)
+	}
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		query = query.Where("(name ILIKE ? OR sku ILIKE ? OR description ILIKE ?)", pattern, pattern, pattern)
+	}
+	if filter.PriceMin != nil {
+		query = query.Where("price >= ?", *filter.PriceMin)
+	}
+	if filter.PriceMax != nil {
+		query = query.Where("price <= ?", *filter.PriceMax)
+	}
+	if filter.StockMin != nil {
+		query = query.Where("stock >= ?", *filter.StockMin)
+	}
+	if filter.StockMax != nil {
+		query = query.Where("stock <= ?", *filter.StockMax)
+	}
+	if filter.LowStock {
+		query = query.Where("stock <= min_stock")
+	}
+	if filter.ParentID != "" {
+		query = query.Where("parent_id = ?", filter.ParentID)
+	}
+	
+	keys := make([]string, 0, len(filter.Attributes))
+	for key := range filter.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		query = query.Where("attributes ->> ? = ?", key, filter.Attributes[key])
+	}
+	
+	return query
+}
+
+func sortedItems(query *gorm.DB, filter *models.ItemFilter) *gorm.DB {
+	for _, order := range filter.Sort {
+		query = query.Order(order)
+	}
+	if len(filter.Sort) == 0 {
+		query = query.Order("created_at DESC")
+	}
+	return query
+}
 
 func (r *ItemRepository) FindByID(id string) (*models.Item, error) {
 	var item models.Item
@@ -36,18 +144,87 @@ func (r *ItemRepository) FindByID(id string) (*models.Item, error) {
 	return &item, err
 }
 
+// FindByIDForUpdate locks the item row until the surrounding transaction ends.
+func (r *ItemRepository) FindByIDForUpdate(id string) (*models.Item, error) {
+	var item models.Item
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&item).Error
+	return &item, err
+}
+
+// FindBySKUs returns the items whose SKU is in skus, keyed by SKU.
+func (r *ItemRepository) FindBySKUs(skus []string) (map[string]*models.Item, error) {
+	items := make(map[string]*models.Item, len(skus))
+	if len(skus) == 0 {
+		return items, nil
+	}
+	
+	var found []models.Item
+	if err := r.db.Where("sku IN ?", skus).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	for i := range found {
+		items[found[i].SKU] = &found[i]
+	}
+	return items, nil
+}
+
+// FindVariants returns the items that are variants of parentID.
+func (r *ItemRepository) FindVariants(parentID string) ([]models.Item, error) {
+	var items []models.Item
+	err := r.db.Where("parent_id = ?", parentID).Order("name ASC").Find(&items).Error
+	return items, err
+}
+
+func (r *ItemRepository) CountVariants(parentID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Item{}).Where("parent_id = ?", parentID).Count(&count).Error
+	return count, err
+}
+
+// Update writes the item's details and bumps its version. It fails with
+// models.ErrVersionConflict when the row is no longer at item.Version.
 func (r *ItemRepository) Update(item *models.Item) error {
-	result := r.db.Omit("created_by", "created_at", "sku").Save(item)
+	version := item.Version
+	item.Version++
+	
+	result := r.db.Model(item).
+		Where("version = ?", version).
+		Select("*").
+		Omit("created_by", "created_at", "sku", "stock", "reserved_stock").
+		Updates(item)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = models.ErrVersionConflict
+	}
+	if result.Error != nil {
+		item.Version = version
+	}
 	return result.Error
 }
 
-
 func (r *ItemRepository) Delete(id string) error {
-	return database.DB.Where("id = ?", id).Delete(&models.Item{}).Error
+	return r.db.Where("id = ?", id).Delete(&models.Item{}).Error
 }
 
 func (r *ItemRepository) UpdateStock(itemID string, quantity int) error {
-	return database.DB.Model(&models.Item{}).
+	return r.db.Model(&models.Item{}).
+		Where("id = ?", itemID).
+		Updates(map[string]interface{}{
+			"stock":   gorm.Expr("stock + ?", quantity),
+			"version": gorm.Expr("version + 1"),
+		}).Error
+}
+
+func (r *ItemRepository) UpdateReservedStock(itemID string, quantity int) error {
+	return r.db.Model(&models.Item{}).
 		Where("id = ?", itemID).
-		Update("stock", gorm.Expr("stock + ?", quantity)).Error
-}
\ No newline at end of file
+		Updates(map[string]interface{}{
+			"reserved_stock": gorm.Expr("reserved_stock + ?", quantity),
+			"version":        gorm.Expr("version + 1"),
+		}).Error
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ItemStockRepository struct {
+	db *gorm.DB
+}
+
+func NewItemStockRepository() *ItemStockRepository {
+	return &ItemStockRepository{db: database.DB}
+}
+
+func (r *ItemStockRepository) WithTx(tx *gorm.DB) *ItemStockRepository {
+	return &ItemStockRepository{db: tx}
+}
+
+// FindOrCreateForUpdate returns the stock row for a location, creating an
+// empty one first if needed, and locks it for the rest of the transaction.
+func (r *ItemStockRepository) FindOrCreateForUpdate(itemID, warehouseID, binID string) (*models.ItemStock, error) {
+	row := models.ItemStock{ItemID: itemID, WarehouseID: warehouseID, BinID: binID}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		return nil, err
+	}
+	
+	var stock models.ItemStock
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ? AND bin_id = ?", itemID, warehouseID, binID).
+		First(&stock).Error
+	return &stock, err
+}
+
+func (r *ItemStockRepository) AddQuantity(id string, delta int) error {
+	return r.db.Model(&models.ItemStock{}).
+		Where("id = ?", id).
+		Update("quantity", gorm.Expr("quantity + ?", delta)).Error
+}
+
+func (r *ItemStockRepository) FindByItemID(itemID string) ([]models.ItemStock, error) {
+	var stocks []models.ItemStock
+	err := r.db.Model(&models.ItemStock{}).
+		Select("item_stocks.*, bins.code AS bin_code").
+		Joins("LEFT JOIN bins ON bins.id::text = item_stocks.bin_id").
+		Preload("Warehouse").
+		Where("item_stocks.item_id = ? AND item_stocks.quantity <> 0", itemID).
+		Order("item_stocks.warehouse_id, item_stocks.bin_id").
+		Find(&stocks).Error
+	return stocks, err
+}
+
+// FindForStocktake returns the stock rows a stocktake should freeze. Empty
+// arguments do not narrow the result.
+func (r *ItemStockRepository) FindForStocktake(warehouseID, binID string, itemIDs []string) ([]models.ItemStock, error) {
+	var stocks []models.ItemStock
+	
+	query := r.db.Model(&models.ItemStock{})
+	if warehouseID != "" {
+		query = query.Where("warehouse_id = ?", warehouseID)
+	}
+	if binID != "" {
+		query = query.Where("bin_id = ?", binID)
+	}
+	if len(itemIDs) > 0 {
+		query = query.Where("item_id IN ?", itemIDs)
+	} else {
+		query = query.Where("quantity <> 0")
+	}
+	
+	err := query.Order("warehouse_id, bin_id, item_id").Find(&stocks).Error
+	return stocks, err
+}
+
+// QuantitiesAt returns the quantities of the given items held at exactly one
+// location, keyed by item ID. Items with no row there are left out.
+func (r *ItemStockRepository) QuantitiesAt(warehouseID, binID string, itemIDs []string) (map[string]int, error) {
+	var stocks []models.ItemStock
+	err := r.db.Where("warehouse_id = ? AND bin_id = ? AND item_id IN ?", warehouseID, binID, itemIDs).
+		Find(&stocks).Error
+	if err != nil {
+		return nil, err
+	}
+	
+	quantities := make(map[string]int, len(stocks))
+	for _, stock := range stocks {
+		quantities[stock.ItemID] = stock.Quantity
+	}
+	return quantities, nil
+}
+
+// WarehouseQuantity returns an item's stock in a warehouse across its bins.
+func (r *ItemStockRepository) WarehouseQuantity(itemID, warehouseID string) (int, error) {
+	var total int
+	err := r.db.Model(&models.ItemStock{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("item_id = ? AND warehouse_id = ?", itemID, warehouseID).
+		Scan(&total).Error
+	return total, err
+}
+
+// FindInWarehouse returns the stocked rows of an item in one warehouse, the
+// unbinned row first and then by bin.
+func (r *ItemStockRepository) FindInWarehouse(itemID, warehouseID string) ([]models.ItemStock, error) {
+	var stocks []models.ItemStock
+	err := r.db.Where("item_id = ? AND warehouse_id = ? AND quantity > 0", itemID, warehouseID).
+		Order("bin_id").
+		Find(&stocks).Error
+	return stocks, err
+}
+
+func (r *ItemStockRepository) CountByWarehouse(warehouseID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ItemStock{}).
+		Where("warehouse_id = ? AND quantity <> 0", warehouseID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ItemStockRepository) CountByBin(binID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ItemStock{}).
+		Where("bin_id = ? AND quantity <> 0", binID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *ItemStockRepository) DeleteByItemID(itemID string) error {
+	return r.db.Where("item_id = ?", itemID).Delete(&models.ItemStock{}).Error
+}
+
+// DeleteEmptyByWarehouse removes zero-quantity rows so the warehouse can be
+// deleted without tripping the foreign key.
+func (r *ItemStockRepository) DeleteEmptyByWarehouse(warehouseID string) error {
+	return r.db.Where("warehouse_id = ? AND quantity = 0", warehouseID).Delete(&models.ItemStock{}).Error
+}
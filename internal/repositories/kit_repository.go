@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type KitRepository struct {
+	db *gorm.DB
+}
+
+func NewKitRepository() *KitRepository {
+	return &KitRepository{db: database.DB}
+}
+
+func (r *KitRepository) WithTx(tx *gorm.DB) *KitRepository {
+	return &KitRepository{db: tx}
+}
+
+func (r *KitRepository) FindComponents(kitID string) ([]models.KitComponent, error) {
+	var components []models.KitComponent
+	err := r.db.Preload("Component").
+		Joins("JOIN items ON items.id = kit_components.component_id").
+		Where("kit_components.kit_id = ?", kitID).
+		Order("items.name ASC").
+		Find(&components).Error
+	return components, err
+}
+
+// ReplaceComponents swaps a kit's bill of materials for the given lines.
+func (r *KitRepository) ReplaceComponents(kitID string, components []models.KitComponent) error {
+	if err := r.DeleteByKitID(kitID); err != nil {
+		return err
+	}
+	if len(components) == 0 {
+		return nil
+	}
+	return r.db.Create(&components).Error
+}
+
+func (r *KitRepository) CountComponents(kitID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.KitComponent{}).Where("kit_id = ?", kitID).Count(&count).Error
+	return count, err
+}
+
+// CountUsage returns the number of kits that use the item as a component.
+func (r *KitRepository) CountUsage(itemID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.KitComponent{}).Where("component_id = ?", itemID).Count(&count).Error
+	return count, err
+}
+
+func (r *KitRepository) DeleteByKitID(kitID string) error {
+	return r.db.Where("kit_id = ?", kitID).Delete(&models.KitComponent{}).Error
+}
+
+func (r *KitRepository) CreateBuild(build *models.KitBuild) error {
+	return r.db.Create(build).Error
+}
\ No newline at end of file
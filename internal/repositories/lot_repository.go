@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dateOnly formats a time as a date literal, so comparisons against the
+// date column do not depend on the session time zone.
+func dateOnly(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+type LotRepository struct {
+	db *gorm.DB
+}
+
+func NewLotRepository() *LotRepository {
+	return &LotRepository{db: database.DB}
+}
+
+func (r *LotRepository) WithTx(tx *gorm.DB) *LotRepository {
+	return &LotRepository{db: tx}
+}
+
+// FindOrCreateForUpdate returns the lot row at a location, creating an empty
+// one with the given expiry first if needed, and locks it.
+func (r *LotRepository) FindOrCreateForUpdate(itemID, warehouseID, binID, lotNumber string, expiresAt time.Time) (*models.StockLot, error) {
+	row := models.StockLot{
+		ItemID:      itemID,
+		WarehouseID: warehouseID,
+		BinID:       binID,
+		LotNumber:   lotNumber,
+		ExpiresAt:   expiresAt,
+		Status:      models.LotStatusAvailable,
+	}
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&row).Error; err != nil {
+		return nil, err
+	}
+	
+	return r.FindForUpdate(itemID, warehouseID, binID, lotNumber)
+}
+
+func (r *LotRepository) FindForUpdate(itemID, warehouseID, binID, lotNumber string) (*models.StockLot, error) {
+	var lot models.StockLot
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ? AND bin_id = ? AND lot_number = ?", itemID, warehouseID, binID, lotNumber).
+		First(&lot).Error
+	return &lot, err
+}
+
+// FindPickableForUpdate locks the lots at a location that may be picked,
+// first-expired first: available, in stock and not past their expiry date.
+func (r *LotRepository) FindPickableForUpdate(itemID, warehouseID, binID string, today time.Time) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND warehouse_id = ? AND bin_id = ?", itemID, warehouseID, binID).
+		Where("status = ? AND quantity > 0 AND expires_at >= ?", models.LotStatusAvailable, dateOnly(today)).
+		Order("expires_at ASC, created_at ASC").
+		Find(&lots).Error
+	return lots, err
+}
+
+// FindForStocktake returns the stocked lots of one item at a location, for
+// a stocktake to freeze.
+func (r *LotRepository) FindForStocktake(itemID, warehouseID, binID string) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	err := r.db.Where("item_id = ? AND warehouse_id = ? AND bin_id = ? AND quantity <> 0", itemID, warehouseID, binID).
+		Order("expires_at ASC, lot_number ASC").
+		Find(&lots).Error
+	return lots, err
+}
+
+// FindAt returns a lot row at a location without locking it.
+func (r *LotRepository) FindAt(itemID, warehouseID, binID, lotNumber string) (*models.StockLot, error) {
+	var lot models.StockLot
+	err := r.db.Where("item_id = ? AND warehouse_id = ? AND bin_id = ? AND lot_number = ?", itemID, warehouseID, binID, lotNumber).
+		First(&lot).Error
+	return &lot, err
+}
+
+func (r *LotRepository) AddQuantity(id string, delta int) error {
+	return r.db.Model(&models.StockLot{}).
+		Where("id = ?", id).
+		Update("quantity", gorm.Expr("quantity + ?", delta)).Error
+}
+
+func (r *LotRepository) FindByID(id string) (*models.StockLot, error) {
+	var lot models.StockLot
+	err := r.db.Where("id = ?", id).First(&lot).Error
+	return &lot, err
+}
+
+func (r *LotRepository) FindByIDForUpdate(id string) (*models.StockLot, error) {
+	var lot models.StockLot
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&lot).Error
+	return &lot, err
+}
+
+func (r *LotRepository) Update(lot *models.StockLot) error {
+	return r.db.Omit("Item", "created_at").Save(lot).Error
+}
+
+func (r *LotRepository) FindByItemID(itemID string, includeEmpty bool) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	query := r.db.Where("item_id = ?", itemID)
+	if !includeEmpty {
+		query = query.Where("quantity <> 0")
+	}
+	err := query.Order("expires_at ASC, lot_number ASC").Find(&lots).Error
+	return lots, err
+}
+
+// FindExpiring returns in-stock lots that expire on or before the given day,
+// soonest first, with their item.
+func (r *LotRepository) FindExpiring(before time.Time, warehouseID string, includeQuarantined bool) ([]models.StockLot, error) {
+	var lots []models.StockLot
+	query := r.db.Preload("Item", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "sku", "category")
+	}).
+		Where("quantity > 0 AND expires_at <= ?", dateOnly(before))
+	if warehouseID != "" {
+		query = query.Where("warehouse_id = ?", warehouseID)
+	}
+	if !includeQuarantined {
+		query = query.Where("status = ?", models.LotStatusAvailable)
+	}
+	err := query.Order("expires_at ASC, item_id").Find(&lots).Error
+	return lots, err
+}
+
+// FindExpiredIDs returns available in-stock lots past their expiry date.
+func (r *LotRepository) FindExpiredIDs(today time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.StockLot{}).
+		Where("status = ? AND quantity > 0 AND expires_at < ?", models.LotStatusAvailable, dateOnly(today)).
+		Order("expires_at ASC").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *LotRepository) DeleteByItemID(itemID string) error {
+	return r.db.Where("item_id = ?", itemID).Delete(&models.StockLot{}).Error
+}
\ No newline at end of file
@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository() *OutboxRepository {
+	return &OutboxRepository{db: database.DB}
+}
+
+func (r *OutboxRepository) WithTx(tx *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: tx}
+}
+
+// Create ignores an event whose dedup key is already in the outbox.
+func (r *OutboxRepository) Create(event *models.OutboxEvent) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "dedup_key"}},
+		DoNothing: true,
+	}).Create(event).Error
+}
+
+func (r *OutboxRepository) FindDueIDs(now time.Time, limit int) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.OutboxEvent{}).
+		Where("published_at IS NULL AND next_attempt_at <= ?", now).
+		Order("created_at ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// LockUnpublished returns nil, nil when the event is already published or
+// another dispatcher holds it.
+func (r *OutboxRepository) LockUnpublished(id string) (*models.OutboxEvent, error) {
+	var event models.OutboxEvent
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ? AND published_at IS NULL", id).
+		First(&event).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *OutboxRepository) Save(event *models.OutboxEvent) error {
+	return r.db.Save(event).Error
+}
+
+// FindPublishedAfter returns published events that follow the event with
+// the given ID, oldest first. It returns nothing when that ID is unknown.
+func (r *OutboxRepository) FindPublishedAfter(id string, limit int) ([]models.OutboxEvent, error) {
+	var after models.OutboxEvent
+	if err := r.db.Select("id", "created_at").Where("id = ?", id).First(&after).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	
+	var events []models.OutboxEvent
+	err := r.db.Where("published_at IS NOT NULL").
+		Where("(created_at > ? OR (created_at = ? AND id > ?))", after.CreatedAt, after.CreatedAt, after.ID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PurchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepository() *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: database.DB}
+}
+
+func (r *PurchaseOrderRepository) WithTx(tx *gorm.DB) *PurchaseOrderRepository {
+	return &PurchaseOrderRepository{db: tx}
+}
+
+func (r *PurchaseOrderRepository) Create(order *models.PurchaseOrder) error {
+	return r.db.Create(order).Error
+}
+
+func (r *PurchaseOrderRepository) FindAll(page, limit int, status, supplierID string) ([]models.PurchaseOrder, int64, error) {
+	var orders []models.PurchaseOrder
+	var total int64
+	
+	query := r.db.Model(&models.PurchaseOrder{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if supplierID != "" {
+		query = query.Where("supplier_id = ?", supplierID)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Preload("Supplier").
+		Preload("Lines").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	
+	return orders, total, err
+}
+
+func (r *PurchaseOrderRepository) FindByID(id string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := r.db.Preload("Supplier").
+		Preload("Lines.Item", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "sku", "stock")
+		}).
+		Where("id = ?", id).
+		First(&order).Error
+	return &order, err
+}
+
+// FindByIDForUpdate locks the order row so receipts against the same order
+// are applied one at a time.
+func (r *PurchaseOrderRepository) FindByIDForUpdate(id string) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&order).Error
+	if err != nil {
+		return &order, err
+	}
+	
+	err = r.db.Where("purchase_order_id = ?", id).Order("id").Find(&order.Lines).Error
+	return &order, err
+}
+
+func (r *PurchaseOrderRepository) Update(order *models.PurchaseOrder) error {
+	return r.db.Omit("Lines", "Supplier", "created_at", "number", "created_by").Save(order).Error
+}
+
+func (r *PurchaseOrderRepository) ReplaceLines(orderID string, lines []models.PurchaseOrderLine) error {
+	if err := r.db.Where("purchase_order_id = ?", orderID).Delete(&models.PurchaseOrderLine{}).Error; err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return r.db.Create(&lines).Error
+}
+
+func (r *PurchaseOrderRepository) AddReceivedQuantity(lineID string, quantity int) error {
+	return r.db.Model(&models.PurchaseOrderLine{}).
+		Where("id = ?", lineID).
+		Update("quantity_received", gorm.Expr("quantity_received + ?", quantity)).Error
+}
+
+// FindOnOrder sums outstanding quantities per item across open orders.
+func (r *PurchaseOrderRepository) FindOnOrder(itemID string) ([]models.OnOrderQuantity, error) {
+	var rows []models.OnOrderQuantity
+	
+	query := r.db.Table("purchase_order_lines").
+		Select("items.id AS item_id, items.name AS item_name, items.sku, SUM(purchase_order_lines.quantity_ordered - purchase_order_lines.quantity_received) AS on_order").
+		Joins("JOIN purchase_orders ON purchase_orders.id = purchase_order_lines.purchase_order_id").
+		Joins("JOIN items ON items.id = purchase_order_lines.item_id").
+		Where("purchase_orders.status IN ?", []models.PurchaseOrderStatus{
+			models.PurchaseOrderStatusApproved,
+			models.PurchaseOrderStatusPartiallyReceived,
+		})
+	if itemID != "" {
+		query = query.Where("purchase_order_lines.item_id = ?", itemID)
+	}
+	
+	err := query.Group("items.id, items.name, items.sku").
+		Having("SUM(purchase_order_lines.quantity_ordered - purchase_order_lines.quantity_received) > 0").
+		Order("items.name ASC").
+		Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"errors"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository() *RoleRepository {
+	return &RoleRepository{db: database.DB}
+}
+
+func (r *RoleRepository) WithTx(tx *gorm.DB) *RoleRepository {
+	return &RoleRepository{db: tx}
+}
+
+func (r *RoleRepository) Create(role *models.Role) error {
+	return r.db.Create(role).Error
+}
+
+func (r *RoleRepository) FindAll() ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.Preload("Permissions").Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *RoleRepository) FindByID(id string) (*models.Role, error) {
+	var role models.Role
+	err := r.db.Preload("Permissions").Where("id = ?", id).First(&role).Error
+	return &role, err
+}
+
+func (r *RoleRepository) FindByName(name string) (*models.Role, error) {
+	var role models.Role
+	err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *RoleRepository) Update(role *models.Role) error {
+	return r.db.Omit("Permissions", "created_at").Save(role).Error
+}
+
+func (r *RoleRepository) ReplacePermissions(role *models.Role, permissions []models.Permission) error {
+	return r.db.Model(role).Association("Permissions").Replace(permissions)
+}
+
+func (r *RoleRepository) Delete(role *models.Role) error {
+	if err := r.db.Model(role).Association("Permissions").Clear(); err != nil {
+		return err
+	}
+	return r.db.Delete(role).Error
+}
+
+func (r *RoleRepository) HasPermissions(roleName string, permissions []string) (bool, error) {
+	var count int64
+	err := r.db.Table("role_permissions").
+		Joins("JOIN roles ON roles.id = role_permissions.role_id").
+		Joins("JOIN permissions ON permissions.id = role_permissions.permission_id").
+		Where("roles.name = ? AND permissions.name IN ?", roleName, permissions).
+		Distinct("permissions.name").
+		Count(&count).Error
+	return count == int64(len(permissions)), err
+}
+
+func (r *RoleRepository) CountUsers(roleName string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.User{}).Where("role = ?", roleName).Count(&count).Error
+	return count, err
+}
+
+func (r *RoleRepository) FindAllPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	err := r.db.Order("name ASC").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *RoleRepository) FindPermissionsByName(names []string) ([]models.Permission, error) {
+	var permissions []models.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.db.Where("name IN ?", names).Find(&permissions).Error
+	return permissions, err
+}
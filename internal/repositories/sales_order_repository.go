@@ -0,0 +1,168 @@
+package repositories
+
+import (
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SalesOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewSalesOrderRepository() *SalesOrderRepository {
+	return &SalesOrderRepository{db: database.DB}
+}
+
+func (r *SalesOrderRepository) WithTx(tx *gorm.DB) *SalesOrderRepository {
+	return &SalesOrderRepository{db: tx}
+}
+
+func (r *SalesOrderRepository) Create(order *models.SalesOrder) error {
+	return r.db.Create(order).Error
+}
+
+func (r *SalesOrderRepository) FindAll(page, limit int, status, search string) ([]models.SalesOrder, int64, error) {
+	var orders []models.SalesOrder
+	var total int64
+	
+	query := r.db.Model(&models.SalesOrder{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("(number ILIKE ? OR customer_name ILIKE ? OR customer_reference ILIKE ?)", pattern, pattern, pattern)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Preload("Lines").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	
+	return orders, total, err
+}
+
+func (r *SalesOrderRepository) FindByID(id string) (*models.SalesOrder, error) {
+	var order models.SalesOrder
+	err := r.db.Preload("Lines.Item", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "sku", "stock", "reserved_stock")
+	}).Where("id = ?", id).First(&order).Error
+	return &order, err
+}
+
+func (r *SalesOrderRepository) FindByIDForUpdate(id string) (*models.SalesOrder, error) {
+	var order models.SalesOrder
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&order).Error
+	if err != nil {
+		return &order, err
+	}
+	
+	err = r.db.Where("sales_order_id = ?", id).Order("id").Find(&order.Lines).Error
+	return &order, err
+}
+
+func (r *SalesOrderRepository) Update(order *models.SalesOrder) error {
+	return r.db.Omit("Lines", "created_at", "number", "created_by").Save(order).Error
+}
+
+func (r *SalesOrderRepository) SetLineShipped(lineID string, quantity int) error {
+	return r.db.Model(&models.SalesOrderLine{}).
+		Where("id = ?", lineID).
+		Update("quantity_shipped", quantity).Error
+}
+
+// CountOpenLines counts the lines of an item on orders that have not yet
+// shipped or closed.
+func (r *SalesOrderRepository) CountOpenLines(itemID string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.SalesOrderLine{}).
+		Joins("JOIN sales_orders ON sales_orders.id = sales_order_lines.sales_order_id").
+		Where("sales_order_lines.item_id = ?", itemID).
+		Where("sales_orders.status IN ?", []models.SalesOrderStatus{
+			models.SalesOrderStatusDraft,
+			models.SalesOrderStatusConfirmed,
+			models.SalesOrderStatusPicked,
+			models.SalesOrderStatusPacked,
+		}).
+		Count(&count).Error
+	return count, err
+}
+
+type ReservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository() *ReservationRepository {
+	return &ReservationRepository{db: database.DB}
+}
+
+func (r *ReservationRepository) WithTx(tx *gorm.DB) *ReservationRepository {
+	return &ReservationRepository{db: tx}
+}
+
+func (r *ReservationRepository) Create(reservation *models.StockReservation) error {
+	return r.db.Create(reservation).Error
+}
+
+func (r *ReservationRepository) FindActiveByOrder(orderID string) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sales_order_id = ? AND status = ?", orderID, models.ReservationStatusActive).
+		Order("id").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+func (r *ReservationRepository) FindActiveByItem(itemID string) ([]models.StockReservation, error) {
+	var reservations []models.StockReservation
+	err := r.db.Where("item_id = ? AND status = ?", itemID, models.ReservationStatusActive).
+		Order("expires_at ASC").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// SumActiveAt returns the quantity of an item held by active reservations in
+// one warehouse.
+func (r *ReservationRepository) SumActiveAt(itemID, warehouseID string) (int, error) {
+	var total int
+	err := r.db.Model(&models.StockReservation{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("item_id = ? AND warehouse_id = ? AND status = ?", itemID, warehouseID, models.ReservationStatusActive).
+		Scan(&total).Error
+	return total, err
+}
+
+// FindExpiredOrderIDs returns confirmed orders holding expired reservations.
+// Orders already being picked or packed keep their reservations.
+func (r *ReservationRepository) FindExpiredOrderIDs(now time.Time) ([]string, error) {
+	var ids []string
+	err := r.db.Model(&models.StockReservation{}).
+		Joins("JOIN sales_orders ON sales_orders.id = stock_reservations.sales_order_id").
+		Where("stock_reservations.status = ? AND stock_reservations.expires_at < ?", models.ReservationStatusActive, now).
+		Where("sales_orders.status = ?", models.SalesOrderStatusConfirmed).
+		Distinct("stock_reservations.sales_order_id").
+		Pluck("stock_reservations.sales_order_id", &ids).Error
+	return ids, err
+}
+
+func (r *ReservationRepository) Close(id string, status models.ReservationStatus) error {
+	return r.db.Model(&models.StockReservation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":    status,
+			"closed_at": time.Now(),
+		}).Error
+}
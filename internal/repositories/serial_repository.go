@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SerialRepository struct {
+	db *gorm.DB
+}
+
+func NewSerialRepository() *SerialRepository {
+	return &SerialRepository{db: database.DB}
+}
+
+func (r *SerialRepository) WithTx(tx *gorm.DB) *SerialRepository {
+	return &SerialRepository{db: tx}
+}
+
+func (r *SerialRepository) Create(serial *models.SerialNumber) error {
+	return r.db.Omit("Item", "Events").Create(serial).Error
+}
+
+func (r *SerialRepository) Update(serial *models.SerialNumber) error {
+	return r.db.Omit("Item", "Events", "created_at").Save(serial).Error
+}
+
+func (r *SerialRepository) CreateEvent(event *models.SerialEvent) error {
+	return r.db.Create(event).Error
+}
+
+// FindForUpdate locks the item's serials with the given numbers, keyed by
+// serial. Numbers the item has never had are absent from the map.
+func (r *SerialRepository) FindForUpdate(itemID string, serials []string) (map[string]*models.SerialNumber, error) {
+	var rows []models.SerialNumber
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("item_id = ? AND serial IN ?", itemID, serials).
+		Order("serial").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	
+	found := make(map[string]*models.SerialNumber, len(rows))
+	for i := range rows {
+		found[rows[i].Serial] = &rows[i]
+	}
+	return found, nil
+}
+
+func (r *SerialRepository) FindByItemID(itemID, status string) ([]models.SerialNumber, error) {
+	var serials []models.SerialNumber
+	query := r.db.Where("item_id = ?", itemID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("serial ASC").Find(&serials).Error
+	return serials, err
+}
+
+// FindInStockAt returns the serial numbers of an item in stock at exactly
+// one location.
+func (r *SerialRepository) FindInStockAt(itemID, warehouseID, binID string) ([]string, error) {
+	var serials []string
+	err := r.db.Model(&models.SerialNumber{}).
+		Where("item_id = ? AND status = ? AND warehouse_id = ? AND bin_id = ?", itemID, models.SerialStatusInStock, warehouseID, binID).
+		Order("serial ASC").
+		Pluck("serial", &serials).Error
+	return serials, err
+}
+
+// FindBySerial returns every unit carrying the serial number, with its item
+// and its history oldest first. Different items may reuse a number.
+func (r *SerialRepository) FindBySerial(serial string) ([]models.SerialNumber, error) {
+	var serials []models.SerialNumber
+	err := r.db.Preload("Item", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "sku", "category")
+	}).
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Where("serial = ?", serial).
+		Find(&serials).Error
+	return serials, err
+}
+
+func (r *SerialRepository) DeleteByItemID(itemID string) error {
+	if err := r.db.Where("serial_id IN (?)", r.db.Model(&models.SerialNumber{}).Select("id").Where("item_id = ?", itemID)).
+		Delete(&models.SerialEvent{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("item_id = ?", itemID).Delete(&models.SerialNumber{}).Error
+}
\ No newline at end of file
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{db: database.DB}
+}
+
+func (r *SessionRepository) WithTx(tx *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: tx}
+}
+
+func (r *SessionRepository) CreateSession(session *models.UserSession) error {
+	return r.db.Create(session).Error
+}
+
+func (r *SessionRepository) FindSessionByID(id string) (*models.UserSession, error) {
+	var session models.UserSession
+	err := r.db.Where("id = ?", id).First(&session).Error
+	return &session, err
+}
+
+func (r *SessionRepository) TouchSession(id string) error {
+	return r.db.Model(&models.UserSession{}).
+		Where("id = ?", id).
+		Update("last_used_at", time.Now()).Error
+}
+
+func (r *SessionRepository) RevokeSession(id string) error {
+	now := time.Now()
+	if err := r.db.Model(&models.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.RefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now).Error
+}
+
+func (r *SessionRepository) RevokeAllForUser(userID string) error {
+	now := time.Now()
+	if err := r.db.Model(&models.UserSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error; err != nil {
+		return err
+	}
+	return r.db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now).Error
+}
+
+func (r *SessionRepository) CreateRefreshToken(token *models.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindRefreshTokenForUpdate locks the token row so that two concurrent
+// refreshes cannot both rotate the same token.
+func (r *SessionRepository) FindRefreshTokenForUpdate(tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ?", tokenHash).
+		First(&token).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *SessionRepository) MarkRefreshTokenUsed(id string) error {
+	return r.db.Model(&models.RefreshToken{}).
+		Where("id = ?", id).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *SessionRepository) RevokeToken(token *models.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(token).Error
+}
+
+func (r *SessionRepository) IsTokenRevoked(jti string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *SessionRepository) PurgeExpiredRevokedTokens() error {
+	return r.db.Where("expires_at < ?", time.Now()).Delete(&models.RevokedToken{}).Error
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type StockAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewStockAlertRepository() *StockAlertRepository {
+	return &StockAlertRepository{db: database.DB}
+}
+
+func (r *StockAlertRepository) WithTx(tx *gorm.DB) *StockAlertRepository {
+	return &StockAlertRepository{db: tx}
+}
+
+func (r *StockAlertRepository) Create(alert *models.StockAlert) error {
+	return r.db.Create(alert).Error
+}
+
+func (r *StockAlertRepository) FindAll(page, limit int, status, alertType, itemID string) ([]models.StockAlert, int64, error) {
+	var alerts []models.StockAlert
+	var total int64
+	
+	query := r.db.Model(&models.StockAlert{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if alertType != "" {
+		query = query.Where("type = ?", alertType)
+	}
+	if itemID != "" {
+		query = query.Where("item_id = ?", itemID)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Preload("Item").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&alerts).Error
+	
+	return alerts, total, err
+}
+
+// FindOpen returns nil, nil when the item has no open alert of that type.
+func (r *StockAlertRepository) FindOpen(itemID string, alertType models.StockAlertType) (*models.StockAlert, error) {
+	var alert models.StockAlert
+	err := r.db.Where("item_id = ? AND type = ? AND status = ?", itemID, alertType, models.StockAlertStatusOpen).
+		First(&alert).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *StockAlertRepository) Refresh(id string, stock, suggestedQuantity int) error {
+	return r.db.Model(&models.StockAlert{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"stock":              stock,
+			"suggested_quantity": suggestedQuantity,
+		}).Error
+}
+
+func (r *StockAlertRepository) Resolve(id string, stock int) error {
+	return r.db.Model(&models.StockAlert{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":             models.StockAlertStatusResolved,
+			"stock":              stock,
+			"suggested_quantity": 0,
+			"resolved_at":        time.Now(),
+		}).Error
+}
+
+func (r *StockAlertRepository) DeleteByItemID(itemID string) error {
+	return r.db.Where("item_id = ?", itemID).Delete(&models.StockAlert{}).Error
+}
@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type StockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository() *StockMovementRepository {
+	return &StockMovementRepository{db: database.DB}
+}
+
+func (r *StockMovementRepository) WithTx(tx *gorm.DB) *StockMovementRepository {
+	return &StockMovementRepository{db: tx}
+}
+
+func (r *StockMovementRepository) Create(movement *models.StockMovement) error {
+	return r.db.Create(movement).Error
+}
+
+func (r *StockMovementRepository) FindByItemID(itemID string, page, limit int, from, to *time.Time) ([]models.StockMovement, int64, error) {
+	var movements []models.StockMovement
+	var total int64
+	
+	query := r.db.Model(&models.StockMovement{}).Where("item_id = ?", itemID)
+	if from != nil {
+		query = query.Where("created_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("created_at <= ?", *to)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&movements).Error
+	
+	return movements, total, err
+}
+
+// SumByLocationAsOf returns the ledger balance of an item per location,
+// counting only movements recorded at or before the given time.
+func (r *StockMovementRepository) SumByLocationAsOf(itemID string, asOf time.Time) ([]models.LocationStock, error) {
+	var rows []models.LocationStock
+	err := r.db.Model(&models.StockMovement{}).
+		Select("warehouse_id, bin_id, SUM(quantity) AS quantity").
+		Where("item_id = ? AND created_at <= ?", itemID, asOf).
+		Group("warehouse_id, bin_id").
+		Having("SUM(quantity) <> 0").
+		Order("warehouse_id, bin_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *StockMovementRepository) SumByLocation(itemID string) ([]models.LocationStock, error) {
+	var rows []models.LocationStock
+	err := r.db.Model(&models.StockMovement{}).
+		Select("warehouse_id, bin_id, SUM(quantity) AS quantity").
+		Where("item_id = ?", itemID).
+		Group("warehouse_id, bin_id").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// FindDrift compares every item's cached stock with its ledger sum. With
+// onlyDrifting set, items whose totals agree are left out.
+func (r *StockMovementRepository) FindDrift(onlyDrifting bool) ([]models.StockReconciliation, error) {
+	var rows []models.StockReconciliation
+	
+	query := r.db.Table("items").
+		Select(`items.id AS item_id, items.name AS item_name, items.sku,
+			items.stock AS cached_stock,
+			COALESCE(ledger.total, 0) AS ledger_stock,
+			items.stock - COALESCE(ledger.total, 0) AS drift`).
+		Joins(`LEFT JOIN (
+			SELECT item_id, SUM(quantity) AS total FROM stock_movements GROUP BY item_id
+		) AS ledger ON ledger.item_id = items.id`)
+	
+	if onlyDrifting {
+		query = query.Where("items.stock <> COALESCE(ledger.total, 0)")
+	}
+	
+	err := query.Order("items.name ASC").Scan(&rows).Error
+	for i := range rows {
+		rows[i].InSync = rows[i].Drift == 0
+	}
+	return rows, err
+}
+
+// SumCostByReference totals the value of the movements written for one
+// reference, such as the components consumed by a kit build.
+func (r *StockMovementRepository) SumCostByReference(referenceType, referenceID string) (float64, error) {
+	var total float64
+	err := r.db.Model(&models.StockMovement{}).
+		Select("COALESCE(SUM(total_cost), 0)").
+		Where("reference_type = ? AND reference_id = ?", referenceType, referenceID).
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *StockMovementRepository) HasMovements(itemID string) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.StockMovement{}).Where("item_id = ?", itemID).Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// Valuation sums ledger quantity and value per category or warehouse up to
+// filter.AsOf. COGS is the value of the COGS reason codes since filter.From.
+// Category lines cover only the items directly in the category.
+func (r *StockMovementRepository) Valuation(filter *models.ValuationFilter) ([]models.ValuationLine, error) {
+	var rows []models.ValuationLine
+	
+	from := time.Time{}
+	if filter.From != nil {
+		from = *filter.From
+	}
+	
+	query := r.db.Table("stock_movements").
+		Joins("JOIN items ON items.id = stock_movements.item_id")
+	
+	keyColumns := "COALESCE(items.category_id::text, '') AS key, COALESCE(categories.name, '') AS name, COALESCE(categories.parent_id::text, '') AS parent_id"
+	group := "items.category_id, categories.name, categories.parent_id"
+	join := "LEFT JOIN categories ON categories.id = items.category_id"
+	if filter.GroupBy == models.ValuationGroupWarehouse {
+		keyColumns = "stock_movements.warehouse_id AS key, COALESCE(warehouses.name, '') AS name"
+		group = "stock_movements.warehouse_id, warehouses.name"
+		join = "LEFT JOIN warehouses ON warehouses.id = stock_movements.warehouse_id"
+	}
+	query = query.Joins(join)
+	
+	query = query.
+		Select(keyColumns+`,
+			SUM(stock_movements.quantity) AS quantity,
+			SUM(stock_movements.total_cost) AS value,
+			COALESCE(-SUM(stock_movements.total_cost) FILTER (
+				WHERE stock_movements.reason_code IN ? AND stock_movements.created_at >= ?
+			), 0) AS cogs`, models.COGSReasonCodes, from).
+		Where("stock_movements.created_at <= ?", filter.AsOf).
+		Group(group)
+	if filter.WarehouseID != "" {
+		query = query.Where("stock_movements.warehouse_id = ?", filter.WarehouseID)
+	}
+	if filter.Category != "" {
+		query = query.Where("items.category_id IN ("+categorySubtree("LOWER(name) = LOWER(?)")+")", filter.Category)
+	}
+	
+	err := query.Order("name ASC").Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,153 @@
+package repositories
+
+import (
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StocktakeRepository struct {
+	db *gorm.DB
+}
+
+func NewStocktakeRepository() *StocktakeRepository {
+	return &StocktakeRepository{db: database.DB}
+}
+
+func (r *StocktakeRepository) WithTx(tx *gorm.DB) *StocktakeRepository {
+	return &StocktakeRepository{db: tx}
+}
+
+func (r *StocktakeRepository) Create(stocktake *models.Stocktake) error {
+	return r.db.Create(stocktake).Error
+}
+
+func (r *StocktakeRepository) FindAll(page, limit int, status string) ([]models.Stocktake, int64, error) {
+	var stocktakes []models.Stocktake
+	var total int64
+	
+	query := r.db.Model(&models.Stocktake{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&stocktakes).Error
+	
+	return stocktakes, total, err
+}
+
+func (r *StocktakeRepository) FindByID(id string) (*models.Stocktake, error) {
+	var stocktake models.Stocktake
+	err := r.db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("warehouse_id, bin_id, item_id, lot_number")
+	}).
+		Preload("Lines.Item", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "name", "sku", "stock", "price", "lot_tracked", "serialized")
+		}).
+		Where("id = ?", id).
+		First(&stocktake).Error
+	return &stocktake, err
+}
+
+// FindByIDForUpdate locks the session row so counts and approval are applied
+// one at a time.
+func (r *StocktakeRepository) FindByIDForUpdate(id string) (*models.Stocktake, error) {
+	var stocktake models.Stocktake
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&stocktake).Error
+	if err != nil {
+		return &stocktake, err
+	}
+	
+	err = r.db.Preload("Item", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "name", "lot_tracked", "serialized")
+	}).
+		Where("stocktake_id = ?", id).
+		Order("warehouse_id, bin_id, item_id, lot_number").
+		Find(&stocktake.Lines).Error
+	return &stocktake, err
+}
+
+// AddLine adds a line to a session that is already counting, for a lot
+// found on the shelf that the session did not expect.
+func (r *StocktakeRepository) AddLine(line *models.StocktakeLine) error {
+	return r.db.Omit("Item").Create(line).Error
+}
+
+func (r *StocktakeRepository) Update(stocktake *models.Stocktake) error {
+	return r.db.Omit("Lines", "created_at", "number", "created_by").Save(stocktake).Error
+}
+
+// SaveCount stores a device's count for a line, replacing that device's
+// earlier count, and refreshes the line total from all devices.
+func (r *StocktakeRepository) SaveCount(count *models.StocktakeCount) error {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "line_id"}, {Name: "device_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"quantity", "serial_numbers", "counted_by", "updated_at"}),
+	}).Create(count).Error
+	if err != nil {
+		return err
+	}
+	
+	return r.db.Model(&models.StocktakeLine{}).
+		Where("id = ?", count.LineID).
+		Updates(map[string]interface{}{
+			"counted_quantity": gorm.Expr("(SELECT SUM(quantity) FROM stocktake_counts WHERE line_id = ?)", count.LineID),
+			"counted_at":       time.Now(),
+		}).Error
+}
+
+func (r *StocktakeRepository) SetAdjustment(lineID string, adjustment int) error {
+	return r.db.Model(&models.StocktakeLine{}).
+		Where("id = ?", lineID).
+		Update("adjustment", adjustment).Error
+}
+
+// CountDevices returns how many devices have counted each line of a session.
+func (r *StocktakeRepository) CountDevices(stocktakeID string) (map[string]int, error) {
+	var rows []struct {
+		LineID  string
+		Devices int
+	}
+	err := r.db.Model(&models.StocktakeCount{}).
+		Select("line_id, COUNT(*) AS devices").
+		Where("stocktake_id = ?", stocktakeID).
+		Group("line_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	
+	devices := make(map[string]int, len(rows))
+	for _, row := range rows {
+		devices[row.LineID] = row.Devices
+	}
+	return devices, nil
+}
+
+func (r *StocktakeRepository) FindLineCounts(lineID string) ([]models.StocktakeCount, error) {
+	var counts []models.StocktakeCount
+	err := r.db.Where("line_id = ?", lineID).Order("device_id").Find(&counts).Error
+	return counts, err
+}
+
+func (r *StocktakeRepository) FindCounts(stocktakeID string) ([]models.StocktakeCount, error) {
+	var counts []models.StocktakeCount
+	err := r.db.Where("stocktake_id = ?", stocktakeID).
+		Order("line_id, device_id").
+		Find(&counts).Error
+	return counts, err
+}
\ No newline at end of file
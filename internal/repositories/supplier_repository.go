@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"errors"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SupplierRepository struct {
+	db *gorm.DB
+}
+
+func NewSupplierRepository() *SupplierRepository {
+	return &SupplierRepository{db: database.DB}
+}
+
+func (r *SupplierRepository) Create(supplier *models.Supplier) error {
+	return r.db.Create(supplier).Error
+}
+
+func (r *SupplierRepository) FindAll(search string, active *bool) ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	
+	query := r.db.Model(&models.Supplier{})
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("(name ILIKE ? OR code ILIKE ?)", pattern, pattern)
+	}
+	if active != nil {
+		query = query.Where("is_active = ?", *active)
+	}
+	
+	err := query.Order("name ASC").Find(&suppliers).Error
+	return suppliers, err
+}
+
+func (r *SupplierRepository) FindByID(id string) (*models.Supplier, error) {
+	var supplier models.Supplier
+	err := r.db.Where("id = ?", id).First(&supplier).Error
+	return &supplier, err
+}
+
+func (r *SupplierRepository) FindByCode(code string) (*models.Supplier, error) {
+	var supplier models.Supplier
+	err := r.db.Where("code = ?", code).First(&supplier).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *SupplierRepository) Update(supplier *models.Supplier) error {
+	return r.db.Omit("created_at", "code").Save(supplier).Error
+}
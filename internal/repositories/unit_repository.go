@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"errors"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type UnitRepository struct {
+	db *gorm.DB
+}
+
+func NewUnitRepository() *UnitRepository {
+	return &UnitRepository{db: database.DB}
+}
+
+func (r *UnitRepository) WithTx(tx *gorm.DB) *UnitRepository {
+	return &UnitRepository{db: tx}
+}
+
+func (r *UnitRepository) Create(unit *models.Unit) error {
+	return r.db.Create(unit).Error
+}
+
+func (r *UnitRepository) FindAll() ([]models.Unit, error) {
+	var units []models.Unit
+	err := r.db.Order("code ASC").Find(&units).Error
+	return units, err
+}
+
+func (r *UnitRepository) FindByCode(code string) (*models.Unit, error) {
+	var unit models.Unit
+	err := r.db.Where("code = ?", code).First(&unit).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &unit, nil
+}
+
+func (r *UnitRepository) Delete(code string) error {
+	return r.db.Where("code = ?", code).Delete(&models.Unit{}).Error
+}
+
+// CountUsage counts the items that use a unit as a base, purchase or issue
+// unit or convert it.
+func (r *UnitRepository) CountUsage(code string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Item{}).
+		Where("base_unit = ? OR purchase_unit = ? OR issue_unit = ?", code, code, code).
+		Or("id IN (?)", r.db.Model(&models.ItemUnit{}).Select("item_id").Where("unit_code = ?", code)).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *UnitRepository) FindItemUnits(itemID string) ([]models.ItemUnit, error) {
+	var units []models.ItemUnit
+	err := r.db.Where("item_id = ?", itemID).Order("factor ASC, unit_code ASC").Find(&units).Error
+	return units, err
+}
+
+func (r *UnitRepository) FindItemUnit(itemID, code string) (*models.ItemUnit, error) {
+	var unit models.ItemUnit
+	err := r.db.Where("item_id = ? AND unit_code = ?", itemID, code).First(&unit).Error
+	return &unit, err
+}
+
+// ReplaceItemUnits swaps an item's conversions for the given ones.
+func (r *UnitRepository) ReplaceItemUnits(itemID string, units []models.ItemUnit) error {
+	if err := r.DeleteItemUnitsByItemID(itemID); err != nil {
+		return err
+	}
+	if len(units) == 0 {
+		return nil
+	}
+	return r.db.Create(&units).Error
+}
+
+func (r *UnitRepository) DeleteItemUnitsByItemID(itemID string) error {
+	return r.db.Where("item_id = ?", itemID).Delete(&models.ItemUnit{}).Error
+}
\ No newline at end of file
@@ -16,13 +16,17 @@ func NewUserRepository() *UserRepository {
 	return &UserRepository{db: database.DB}
 }
 
+func (r *UserRepository) WithTx(tx *gorm.DB) *UserRepository {
+	return &UserRepository{db: tx}
+}
+
 func (r *UserRepository) Create(user *models.User) error {
-	return database.DB.Create(user).Error
+	return r.db.Create(user).Error
 }
 
 func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
     var user models.User
-    err := database.DB.Where("email = ?", email).First(&user).Error
+    err := r.db.Where("email = ?", email).First(&user).Error
     if err != nil {
         if errors.Is(err, gorm.ErrRecordNotFound) {
             return nil, nil
@@ -34,6 +38,57 @@ func (r *UserRepository) FindByEmail(email string) (*models.User, error) {
 
 func (r *UserRepository) FindByID(id string) (*models.User, error) {
 	var user models.User
-	err := database.DB.Where("id = ?", id).First(&user).Error
+	err := r.db.Where("id = ?", id).First(&user).Error
 	return &user, err
-}
\ No newline at end of file
+}
+
+func (r *UserRepository) FindAll(page, limit int, search, role string, active *bool) ([]models.User, int64, error) {
+	var users []models.User
+	var total int64
+	
+	query := r.db.Model(&models.User{})
+	
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("(name ILIKE ? OR email ILIKE ?)", pattern, pattern)
+	}
+	if role != "" {
+		query = query.Where("role = ?", role)
+	}
+	if active != nil {
+		query = query.Where("is_active = ?", *active)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&users).Error
+	
+	return users, total, err
+}
+
+func (r *UserRepository) UpdateProfile(id, name, role string) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"name": name, "role": role}).Error
+}
+
+func (r *UserRepository) SetActive(id string, active bool) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", id).
+		Update("is_active", active).Error
+}
+
+func (r *UserRepository) UpdatePassword(id, hashedPassword string, mustChange bool) error {
+	return r.db.Model(&models.User{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"password":             hashedPassword,
+			"must_change_password": mustChange,
+		}).Error
+}
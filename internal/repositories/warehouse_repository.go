@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"errors"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type WarehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository() *WarehouseRepository {
+	return &WarehouseRepository{db: database.DB}
+}
+
+func (r *WarehouseRepository) WithTx(tx *gorm.DB) *WarehouseRepository {
+	return &WarehouseRepository{db: tx}
+}
+
+func (r *WarehouseRepository) Create(warehouse *models.Warehouse) error {
+	return r.db.Create(warehouse).Error
+}
+
+func (r *WarehouseRepository) FindAll() ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	err := r.db.Preload("Bins", func(db *gorm.DB) *gorm.DB {
+		return db.Order("code ASC")
+	}).Order("code ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (r *WarehouseRepository) FindByID(id string) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := r.db.Preload("Bins", func(db *gorm.DB) *gorm.DB {
+		return db.Order("code ASC")
+	}).Where("id = ?", id).First(&warehouse).Error
+	return &warehouse, err
+}
+
+func (r *WarehouseRepository) FindByCode(code string) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := r.db.Where("code = ?", code).First(&warehouse).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *WarehouseRepository) FindDefault() (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := r.db.Where("is_default = ?", true).Order("created_at ASC").First(&warehouse).Error
+	return &warehouse, err
+}
+
+func (r *WarehouseRepository) Update(warehouse *models.Warehouse) error {
+	return r.db.Omit("Bins", "created_at", "code").Save(warehouse).Error
+}
+
+func (r *WarehouseRepository) ClearDefault(exceptID string) error {
+	return r.db.Model(&models.Warehouse{}).
+		Where("id <> ? AND is_default = ?", exceptID, true).
+		Update("is_default", false).Error
+}
+
+func (r *WarehouseRepository) Delete(id string) error {
+	if err := r.db.Where("warehouse_id = ?", id).Delete(&models.Bin{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("id = ?", id).Delete(&models.Warehouse{}).Error
+}
+
+func (r *WarehouseRepository) CreateBin(bin *models.Bin) error {
+	return r.db.Create(bin).Error
+}
+
+func (r *WarehouseRepository) FindBin(warehouseID, binID string) (*models.Bin, error) {
+	var bin models.Bin
+	err := r.db.Where("id = ? AND warehouse_id = ?", binID, warehouseID).First(&bin).Error
+	return &bin, err
+}
+
+func (r *WarehouseRepository) DeleteBin(warehouseID, binID string) error {
+	return r.db.Where("id = ? AND warehouse_id = ?", binID, warehouseID).Delete(&models.Bin{}).Error
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"time"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository() *WebhookRepository {
+	return &WebhookRepository{db: database.DB}
+}
+
+func (r *WebhookRepository) WithTx(tx *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: tx}
+}
+
+func (r *WebhookRepository) Create(subscription *models.WebhookSubscription) error {
+	return r.db.Create(subscription).Error
+}
+
+func (r *WebhookRepository) FindAll() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.Order("created_at ASC").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *WebhookRepository) FindActive() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	err := r.db.Where("is_active = ?", true).Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *WebhookRepository) FindByID(id string) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	err := r.db.Where("id = ?", id).First(&subscription).Error
+	return &subscription, err
+}
+
+func (r *WebhookRepository) Update(subscription *models.WebhookSubscription) error {
+	return r.db.Omit("created_at", "created_by").Save(subscription).Error
+}
+
+func (r *WebhookRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deliveries := tx.Model(&models.WebhookDelivery{}).Select("id").Where("subscription_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&models.WebhookAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("subscription_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.WebhookSubscription{}).Error
+	})
+}
+
+// CreateDelivery ignores a delivery that was already queued for the same
+// subscription and event, so republishing an event does not send it twice.
+func (r *WebhookRepository) CreateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(delivery).Error
+}
+
+// CreateReplay queues a replay of an earlier delivery. Unlike CreateDelivery
+// it never skips: every replay is a delivery of its own.
+func (r *WebhookRepository) CreateReplay(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *WebhookRepository) FindDeliveries(subscriptionID string, page, limit int, status string) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	var total int64
+	
+	query := r.db.Model(&models.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error
+	
+	return deliveries, total, err
+}
+
+func (r *WebhookRepository) FindDeliveryByID(id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+		return db.Order("attempt ASC")
+	}).Where("id = ?", id).First(&delivery).Error
+	return &delivery, err
+}
+
+// ClaimDue picks up to limit pending deliveries that are due and pushes
+// their next attempt past lease, so a second dispatcher will not pick them
+// up while this one is still sending.
+func (r *WebhookRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		
+		ids := make([]string, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	
+	return deliveries, err
+}
+
+func (r *WebhookRepository) CreateAttempt(attempt *models.WebhookAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+func (r *WebhookRepository) UpdateDeliveryResult(id string, updates map[string]interface{}) error {
+	return r.db.Model(&models.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error
+}
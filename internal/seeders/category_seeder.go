@@ -0,0 +1,30 @@
+package seeders
+
+import (
+	"log"
+
+	"inventory-api/internal/database"
+
+	"gorm.io/gorm"
+)
+
+type CategorySeeder struct {
+	DB *gorm.DB
+}
+
+func NewCategorySeeder(db *gorm.DB) *CategorySeeder {
+	return &CategorySeeder{DB: db}
+}
+
+// Run files items that only carry a category name, such as the sample
+// items, under category rows.
+func (s *CategorySeeder) Run() error {
+	log.Println("=== Starting category seeder ===")
+	
+	if err := database.MigrateCategories(s.DB); err != nil {
+		return err
+	}
+	
+	log.Println("=== Category seeding completed! ===")
+	return nil
+}
\ No newline at end of file
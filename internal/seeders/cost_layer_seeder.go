@@ -0,0 +1,85 @@
+package seeders
+
+import (
+	"log"
+	"math"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type CostLayerSeeder struct {
+	DB *gorm.DB
+}
+
+func NewCostLayerSeeder(db *gorm.DB) *CostLayerSeeder {
+	return &CostLayerSeeder{DB: db}
+}
+
+// Run opens a cost layer at the item price for stock that predates costing,
+// and writes a zero-quantity REVALUATION movement where needed so the ledger
+// value of that warehouse matches the new layer.
+func (s *CostLayerSeeder) Run() error {
+	log.Println("=== Starting cost layer seeder ===")
+	
+	var rows []struct {
+		ItemID      string
+		WarehouseID string
+		Quantity    int
+		Price       float64
+		CreatedBy   string
+		LedgerValue float64
+	}
+	err := s.DB.Table("item_stocks").
+		Select(`item_stocks.item_id, item_stocks.warehouse_id, SUM(item_stocks.quantity) AS quantity,
+			items.price, items.created_by,
+			COALESCE((SELECT SUM(total_cost) FROM stock_movements
+				WHERE stock_movements.item_id = item_stocks.item_id
+				AND stock_movements.warehouse_id = item_stocks.warehouse_id), 0) AS ledger_value`).
+		Joins("JOIN items ON items.id = item_stocks.item_id").
+		Where("NOT EXISTS (SELECT 1 FROM cost_layers WHERE cost_layers.item_id = item_stocks.item_id)").
+		Group("item_stocks.item_id, item_stocks.warehouse_id, items.price, items.created_by").
+		Having("SUM(item_stocks.quantity) > 0").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	
+	for _, row := range rows {
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			layer := models.CostLayer{
+				ItemID:      row.ItemID,
+				WarehouseID: row.WarehouseID,
+				Quantity:    row.Quantity,
+				Remaining:   row.Quantity,
+				UnitCost:    row.Price,
+			}
+			
+			value := math.Round(float64(row.Quantity)*row.Price*100) / 100
+			if adjustment := math.Round((value-row.LedgerValue)*100) / 100; adjustment != 0 {
+				movement := models.StockMovement{
+					ItemID:      row.ItemID,
+					WarehouseID: row.WarehouseID,
+					TotalCost:   adjustment,
+					ReasonCode:  models.ReasonCodeRevaluation,
+					UserID:      row.CreatedBy,
+				}
+				if err := tx.Create(&movement).Error; err != nil {
+					return err
+				}
+				layer.MovementID = movement.ID
+			}
+			
+			return tx.Create(&layer).Error
+		})
+		if err != nil {
+			log.Printf("Failed to open cost layer for item %s: %v\n", row.ItemID, err)
+			continue
+		}
+		log.Printf("Cost layer opened for item %s (%d @ %.2f)\n", row.ItemID, row.Quantity, row.Price)
+	}
+	
+	log.Println("=== Cost layer seeding completed! ===")
+	return nil
+}
\ No newline at end of file
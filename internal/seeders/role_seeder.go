@@ -0,0 +1,65 @@
+package seeders
+
+import (
+	"log"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type RoleSeeder struct {
+	DB *gorm.DB
+}
+
+func NewRoleSeeder(db *gorm.DB) *RoleSeeder {
+	return &RoleSeeder{DB: db}
+}
+
+// Run makes sure every known permission and the built-in roles exist. Roles
+// that already exist keep whatever permissions an admin has given them,
+// except the admin role, which is always granted everything.
+func (s *RoleSeeder) Run() error {
+	log.Println("=== Starting role seeder ===")
+	
+	var allPermissions []models.Permission
+	for name, description := range models.DefaultPermissions {
+		permission := models.Permission{Name: name, Description: description}
+		if err := s.DB.Where(models.Permission{Name: name}).FirstOrCreate(&permission).Error; err != nil {
+			return err
+		}
+		allPermissions = append(allPermissions, permission)
+	}
+	
+	adminRole := models.Role{Name: models.RoleAdmin, Description: "Full access"}
+	if err := s.DB.Where(models.Role{Name: models.RoleAdmin}).FirstOrCreate(&adminRole).Error; err != nil {
+		return err
+	}
+	if err := s.DB.Model(&adminRole).Association("Permissions").Replace(allPermissions); err != nil {
+		return err
+	}
+	
+	for roleName, permissionNames := range models.DefaultRolePermissions {
+		role := models.Role{Name: roleName}
+		result := s.DB.Where(models.Role{Name: roleName}).FirstOrCreate(&role)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			log.Printf("Role already exists: %s, skipping...\n", roleName)
+			continue
+		}
+		
+		var permissions []models.Permission
+		if err := s.DB.Where("name IN ?", permissionNames).Find(&permissions).Error; err != nil {
+			return err
+		}
+		if err := s.DB.Model(&role).Association("Permissions").Replace(permissions); err != nil {
+			return err
+		}
+		log.Printf("Role created: %s\n", roleName)
+	}
+	
+	log.Println("=== Role seeding completed! ===")
+	return nil
+}
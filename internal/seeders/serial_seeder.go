@@ -0,0 +1,79 @@
+package seeders
+
+import (
+	"fmt"
+	"log"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// serializedSampleItems are the sample items tracked unit by unit.
+var serializedSampleItems = []string{"Laptop Dell XPS 15"}
+
+type SerialSeeder struct {
+	DB *gorm.DB
+}
+
+func NewSerialSeeder(db *gorm.DB) *SerialSeeder {
+	return &SerialSeeder{DB: db}
+}
+
+// Run marks the serialized sample items and registers a generated serial
+// number for every unit they already hold, at the unit's location. It
+// rewrites item data, so it belongs to a fresh database only and is run by
+// migrate_fresh, never on server start.
+func (s *SerialSeeder) Run() error {
+	log.Println("=== Starting serial seeder ===")
+	
+	var items []models.Item
+	err := s.DB.Where("name IN ? AND serialized = ?", serializedSampleItems, false).
+		Where("NOT EXISTS (SELECT 1 FROM serial_numbers WHERE serial_numbers.item_id = items.id)").
+		Find(&items).Error
+	if err != nil {
+		return err
+	}
+	
+	for _, item := range items {
+		err := s.DB.Transaction(func(tx *gorm.DB) error {
+			var stocks []models.ItemStock
+			if err := tx.Where("item_id = ? AND quantity > 0", item.ID).Order("warehouse_id, bin_id").Find(&stocks).Error; err != nil {
+				return err
+			}
+			
+			count := 0
+			for _, stock := range stocks {
+				for i := 0; i < stock.Quantity; i++ {
+					count++
+					serial := models.SerialNumber{
+						ItemID:      item.ID,
+						Serial:      fmt.Sprintf("%s-%04d", item.SKU, count),
+						Status:      models.SerialStatusInStock,
+						WarehouseID: stock.WarehouseID,
+						BinID:       stock.BinID,
+					}
+					if err := tx.Create(&serial).Error; err != nil {
+						return err
+					}
+				}
+			}
+			if count != item.Stock {
+				return fmt.Errorf("location stock %d does not match item stock %d", count, item.Stock)
+			}
+			
+			return tx.Model(&models.Item{}).Where("id = ?", item.ID).Updates(map[string]interface{}{
+				"serialized": true,
+				"version":    gorm.Expr("version + 1"),
+			}).Error
+		})
+		if err != nil {
+			log.Printf("Failed to register serials for %s: %v\n", item.Name, err)
+			continue
+		}
+		log.Printf("%s serialized with %d serial numbers\n", item.Name, item.Stock)
+	}
+	
+	log.Println("=== Serial seeding completed! ===")
+	return nil
+}
\ No newline at end of file
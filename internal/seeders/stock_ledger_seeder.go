@@ -0,0 +1,62 @@
+package seeders
+
+import (
+	"log"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type StockLedgerSeeder struct {
+	DB *gorm.DB
+}
+
+func NewStockLedgerSeeder(db *gorm.DB) *StockLedgerSeeder {
+	return &StockLedgerSeeder{DB: db}
+}
+
+// Run writes an opening balance for every stocked location of items that
+// have no ledger entries yet, so the ledger sum matches Item.Stock.
+func (s *StockLedgerSeeder) Run() error {
+	log.Println("=== Starting stock ledger seeder ===")
+	
+	var rows []struct {
+		ItemID      string
+		WarehouseID string
+		BinID       string
+		Quantity    int
+		CreatedBy   string
+		Price       float64
+	}
+	err := s.DB.Table("item_stocks").
+		Select("item_stocks.item_id, item_stocks.warehouse_id, item_stocks.bin_id, item_stocks.quantity, items.created_by, items.price").
+		Joins("JOIN items ON items.id = item_stocks.item_id").
+		Where("item_stocks.quantity <> 0").
+		Where("NOT EXISTS (SELECT 1 FROM stock_movements WHERE stock_movements.item_id = item_stocks.item_id)").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+	
+	for _, row := range rows {
+		movement := models.StockMovement{
+			ItemID:      row.ItemID,
+			WarehouseID: row.WarehouseID,
+			BinID:       row.BinID,
+			Quantity:    row.Quantity,
+			UnitCost:    row.Price,
+			TotalCost:   float64(row.Quantity) * row.Price,
+			ReasonCode:  models.ReasonCodeOpeningBalance,
+			UserID:      row.CreatedBy,
+		}
+		if err := s.DB.Create(&movement).Error; err != nil {
+			log.Printf("Failed to write opening balance for item %s: %v\n", row.ItemID, err)
+			continue
+		}
+		log.Printf("Opening balance recorded for item %s (%d)\n", row.ItemID, row.Quantity)
+	}
+	
+	log.Println("=== Stock ledger seeding completed! ===")
+	return nil
+}
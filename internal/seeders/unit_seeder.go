@@ -0,0 +1,42 @@
+package seeders
+
+import (
+	"log"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+var defaultUnits = []models.Unit{
+	{Code: models.DefaultBaseUnit, Name: "Each"},
+	{Code: "PACK", Name: "Pack"},
+	{Code: "BOX", Name: "Box"},
+	{Code: "REAM", Name: "Ream"},
+	{Code: "KG", Name: "Kilogram"},
+	{Code: "L", Name: "Litre"},
+}
+
+type UnitSeeder struct {
+	DB *gorm.DB
+}
+
+func NewUnitSeeder(db *gorm.DB) *UnitSeeder {
+	return &UnitSeeder{DB: db}
+}
+
+// Run adds the default units of measure that are missing from the catalog.
+func (s *UnitSeeder) Run() error {
+	log.Println("=== Starting unit seeder ===")
+	
+	for _, unit := range defaultUnits {
+		unit := unit
+		if err := s.DB.Where(models.Unit{Code: unit.Code}).FirstOrCreate(&unit).Error; err != nil {
+			log.Printf("Failed to seed unit %s: %v\n", unit.Code, err)
+			continue
+		}
+	}
+	
+	log.Println("=== Unit seeding completed! ===")
+	return nil
+}
\ No newline at end of file
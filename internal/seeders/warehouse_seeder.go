@@ -0,0 +1,60 @@
+package seeders
+
+import (
+	"log"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type WarehouseSeeder struct {
+	DB *gorm.DB
+}
+
+func NewWarehouseSeeder(db *gorm.DB) *WarehouseSeeder {
+	return &WarehouseSeeder{DB: db}
+}
+
+// Run creates the default warehouse if there is none and moves the stock of
+// items that have no per-location rows yet into it.
+func (s *WarehouseSeeder) Run() error {
+	log.Println("=== Starting warehouse seeder ===")
+	
+	var defaultWarehouse models.Warehouse
+	err := s.DB.Where("is_default = ?", true).First(&defaultWarehouse).Error
+	if err != nil {
+		defaultWarehouse = models.Warehouse{
+			Code:      "MAIN",
+			Name:      "Main Warehouse",
+			IsDefault: true,
+		}
+		if err := s.DB.Create(&defaultWarehouse).Error; err != nil {
+			return err
+		}
+		log.Printf("Default warehouse created: %s\n", defaultWarehouse.Code)
+	}
+	
+	var items []models.Item
+	err = s.DB.Where("NOT EXISTS (SELECT 1 FROM item_stocks WHERE item_stocks.item_id = items.id)").
+		Find(&items).Error
+	if err != nil {
+		return err
+	}
+	
+	for _, item := range items {
+		stock := models.ItemStock{
+			ItemID:      item.ID,
+			WarehouseID: defaultWarehouse.ID,
+			Quantity:    item.Stock,
+		}
+		if err := s.DB.Create(&stock).Error; err != nil {
+			log.Printf("Failed to backfill stock for item %s: %v\n", item.Name, err)
+			continue
+		}
+		log.Printf("Stock of %s assigned to %s (%d)\n", item.Name, defaultWarehouse.Code, item.Stock)
+	}
+	
+	log.Println("=== Warehouse seeding completed! ===")
+	return nil
+}
@@ -21,21 +21,11 @@ func (s *ActivityService) LogActivity(activity *models.ActivityLog) error {
 	return s.db.Create(activity).Error
 }
 
-func (s *ActivityService) GetAllActivities(page, limit int, activityType, itemID, userID string) ([]models.ActivityLog, int64, error) {
+func (s *ActivityService) GetAllActivities(page, limit int, filter *models.ActivityFilter) ([]models.ActivityLog, int64, error) {
 	var activities []models.ActivityLog
 	var total int64
 	
-	query := s.db.Model(&models.ActivityLog{})
-	
-	if activityType != "" {
-		query = query.Where("activity_type = ?", activityType)
-	}
-	if itemID != "" {
-		query = query.Where("item_id = ?", itemID)
-	}
-	if userID != "" {
-		query = query.Where("user_id = ?", userID)
-	}
+	query := s.filtered(filter)
 	
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -50,6 +40,61 @@ func (s *ActivityService) GetAllActivities(page, limit int, activityType, itemID
 	return activities, total, err
 }
 
+// EachActivity calls fn for every activity matching the same filters as
+// GetAllActivities, oldest first, reading from a cursor.
+func (s *ActivityService) EachActivity(filter *models.ActivityFilter, fn func(*models.ActivityLog) error) error {
+	rows, err := s.filtered(filter).
+		Order("created_at ASC").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var activity models.ActivityLog
+		if err := s.db.ScanRows(rows, &activity); err != nil {
+			return err
+		}
+		if err := fn(&activity); err != nil {
+			return err
+		}
+	}
+	
+	return rows.Err()
+}
+
+func (s *ActivityService) filtered(filter *models.ActivityFilter) *gorm.DB {
+	query := s.db.Model(&models.ActivityLog{})
+	
+	if filter.Type != "" {
+		query = query.Where("action = ?", filter.Type)
+	}
+	if filter.ItemID != "" {
+		query = query.Where("item_id = ?", filter.ItemID)
+	}
+	if filter.UserID != "" {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ReferenceType != "" {
+		query = query.Where("reference_type = ?", filter.ReferenceType)
+	}
+	if filter.ReferenceID != "" {
+		query = query.Where("reference_id = ?", filter.ReferenceID)
+	}
+	if filter.LotNumber != "" {
+		query = query.Where("lot_number = ?", filter.LotNumber)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+	
+	return query
+}
+
 func (s *ActivityService) GetActivitiesByItemID(itemID string, page, limit int) ([]models.ActivityLog, int64, error) {
 	var activities []models.ActivityLog
 	var total int64
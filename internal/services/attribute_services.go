@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// attributeKeyPattern keeps keys usable as attr.<key> query parameters.
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// validateAttributes checks item attribute values against the definitions of
+// the item's category and returns them normalized: numbers as float64 and
+// dates as YYYY-MM-DD. Nil values are dropped.
+func (s *ItemService) validateAttributes(tx *gorm.DB, category string, values models.Attributes) (models.Attributes, error) {
+	definitions, err := s.attributeRepo.WithTx(tx).FindByCategory(category)
+	if err != nil {
+		return nil, err
+	}
+	
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	
+	clean := models.Attributes{}
+	for _, key := range keys {
+		definition := definitions[key]
+		if definition == nil {
+			return nil, fmt.Errorf("attribute %s is not defined for category %q", key, category)
+		}
+		if values[key] == nil {
+			continue
+		}
+		
+		value, err := checkAttributeValue(definition, values[key])
+		if err != nil {
+			return nil, err
+		}
+		clean[key] = value
+	}
+	
+	required := make([]string, 0)
+	for key, definition := range definitions {
+		if definition.Required && clean[key] == nil {
+			required = append(required, key)
+		}
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		return nil, fmt.Errorf("missing required attributes: %s", strings.Join(required, ", "))
+	}
+	
+	return clean, nil
+}
+
+func checkAttributeValue(definition *models.AttributeDefinition, value interface{}) (interface{}, error) {
+	switch definition.Type {
+	case models.AttributeTypeNumber:
+		if number, ok := value.(float64); ok {
+			return number, nil
+		}
+		return nil, fmt.Errorf("attribute %s must be a number", definition.Key)
+	
+	case models.AttributeTypeDate:
+		text, ok := value.(string)
+		if ok {
+			if date, err := time.Parse("2006-01-02", strings.TrimSpace(text)); err == nil {
+				return date.Format("2006-01-02"), nil
+			}
+		}
+		return nil, fmt.Errorf("attribute %s must be a YYYY-MM-DD date", definition.Key)
+	
+	case models.AttributeTypeEnum:
+		text, ok := value.(string)
+		if ok {
+			for _, option := range definition.Options {
+				if text == option {
+					return text, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("attribute %s must be one of: %s", definition.Key, strings.Join(definition.Options, ", "))
+	
+	default:
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+		return nil, fmt.Errorf("attribute %s must be a string", definition.Key)
+	}
+}
+
+type AttributeService struct {
+	attributeRepo *repositories.AttributeRepository
+}
+
+func NewAttributeService() *AttributeService {
+	return &AttributeService{
+		attributeRepo: repositories.NewAttributeRepository(),
+	}
+}
+
+func (s *AttributeService) GetAllAttributes(category string) ([]models.AttributeDefinition, error) {
+	return s.attributeRepo.FindAll(category)
+}
+
+func (s *AttributeService) CreateAttribute(req *models.CreateAttributeDefinitionRequest) (*models.AttributeDefinition, error) {
+	category := strings.TrimSpace(req.Category)
+	key := strings.TrimSpace(req.Key)
+	attributeType := models.AttributeType(req.Type)
+	
+	if category == "" {
+		return nil, errors.New("category is required")
+	}
+	if !attributeKeyPattern.MatchString(key) {
+		return nil, errors.New("key must start with a lowercase letter and contain only lowercase letters, digits and underscores")
+	}
+	if !models.AttributeTypes[attributeType] {
+		return nil, fmt.Errorf("invalid attribute type: %s", req.Type)
+	}
+	if attributeType == models.AttributeTypeEnum && len(req.Options) == 0 {
+		return nil, errors.New("enum attributes need at least one option")
+	}
+	if attributeType != models.AttributeTypeEnum && len(req.Options) > 0 {
+		return nil, errors.New("only enum attributes take options")
+	}
+	
+	exists, err := s.attributeRepo.ExistsInCategory(category, key)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, fmt.Errorf("attribute %s is already defined for category %q", key, category)
+	}
+	
+	definition := &models.AttributeDefinition{
+		Category: category,
+		Key:      key,
+		Label:    req.Label,
+		Type:     attributeType,
+		Options:  req.Options,
+		Required: req.Required,
+	}
+	if err := s.attributeRepo.Create(definition); err != nil {
+		return nil, err
+	}
+	
+	return definition, nil
+}
+
+// DeleteAttribute removes an attribute from its category, along with the
+// values items in that category hold for it.
+func (s *AttributeService) DeleteAttribute(id string) error {
+	definition, err := s.attributeRepo.FindByID(id)
+	if err != nil {
+		return errors.New("attribute definition not found")
+	}
+	return s.attributeRepo.Delete(definition)
+}
\ No newline at end of file
@@ -2,22 +2,30 @@ package services
 
 import (
 	"errors"
+	"time"
 
 	"inventory-api/internal/config"
+	"inventory-api/internal/database"
 	"inventory-api/internal/models"
 	"inventory-api/internal/repositories"
 	"inventory-api/internal/utils"
+
+	"gorm.io/gorm"
 )
 
 type AuthService struct {
-	userRepo *repositories.UserRepository
-	config   *config.Config
+	db          *gorm.DB
+	userRepo    *repositories.UserRepository
+	sessionRepo *repositories.SessionRepository
+	config      *config.Config
 }
 
 func NewAuthService(cfg *config.Config) *AuthService {
 	return &AuthService{
-		userRepo: repositories.NewUserRepository(),
-		config:   cfg,
+		db:          database.DB,
+		userRepo:    repositories.NewUserRepository(),
+		sessionRepo: repositories.NewSessionRepository(),
+		config:      cfg,
 	}
 }
 
@@ -42,28 +50,148 @@ func (s *AuthService) Register(req *models.RegisterRequest) (*models.User, error
 	return user, nil
 }
 
-func (s *AuthService) Login(req *models.LoginRequest) (string, error) {
+func (s *AuthService) Login(req *models.LoginRequest, userAgent, ipAddress string) (*models.AuthTokens, error) {
 	user, err := s.userRepo.FindByEmail(req.Email)
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
 	}
 	
 	if user == nil {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
 	}
 	
 	if !user.CheckPassword(req.Password) {
-		return "", errors.New("invalid credentials")
+		return nil, errors.New("invalid credentials")
+	}
+	
+	if !user.IsActive {
+		return nil, errors.New("account is deactivated")
+	}
+	
+	var tokens *models.AuthTokens
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		sessionRepo := s.sessionRepo.WithTx(tx)
+		
+		session := &models.UserSession{
+			UserID:     user.ID,
+			UserAgent:  userAgent,
+			IPAddress:  ipAddress,
+			ExpiresAt:  time.Now().Add(s.refreshTokenTTL()),
+			LastUsedAt: time.Now(),
+		}
+		if err := sessionRepo.CreateSession(session); err != nil {
+			return err
+		}
+		
+		tokens, err = s.issueTokens(sessionRepo, user, session)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 	
-	token, err := utils.GenerateJWT(user.ID, user.Email, user.Name, user.Role, s.config.JWTSecret, s.config.JWTExpireHours)
+	return tokens, nil
+}
+
+// Refresh rotates a refresh token. Presenting a token that was already
+// rotated or revoked is treated as theft and ends the whole session.
+func (s *AuthService) Refresh(refreshToken string) (*models.AuthTokens, error) {
+	var tokens *models.AuthTokens
+	var reused bool
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		sessionRepo := s.sessionRepo.WithTx(tx)
+		
+		stored, err := sessionRepo.FindRefreshTokenForUpdate(utils.HashToken(refreshToken))
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return errors.New("invalid refresh token")
+		}
+		
+		if stored.UsedAt != nil || stored.RevokedAt != nil {
+			reused = true
+			if err := sessionRepo.RevokeSession(stored.SessionID); err != nil {
+				return err
+			}
+			return nil
+		}
+		
+		if time.Now().After(stored.ExpiresAt) {
+			return errors.New("refresh token expired")
+		}
+		
+		session, err := sessionRepo.FindSessionByID(stored.SessionID)
+		if err != nil || session.RevokedAt != nil {
+			return errors.New("session has been revoked")
+		}
+		
+		user, err := s.userRepo.WithTx(tx).FindByID(stored.UserID)
+		if err != nil {
+			return errors.New("user not found")
+		}
+		if !user.IsActive {
+			return errors.New("account is deactivated")
+		}
+		
+		if err := sessionRepo.MarkRefreshTokenUsed(stored.ID); err != nil {
+			return err
+		}
+		if err := sessionRepo.TouchSession(session.ID); err != nil {
+			return err
+		}
+		
+		tokens, err = s.issueTokens(sessionRepo, user, session)
+		return err
+	})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	if reused {
+		return nil, errors.New("refresh token has already been used, session revoked")
 	}
 	
-	return token, nil
+	return tokens, nil
 }
 
+// Logout ends the current session and denylists the access token that was
+// used to call it, so it stops working immediately.
+func (s *AuthService) Logout(userID, sessionID, tokenID string, tokenExpiresAt time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		sessionRepo := s.sessionRepo.WithTx(tx)
+		
+		if err := sessionRepo.RevokeSession(sessionID); err != nil {
+			return err
+		}
+		if err := sessionRepo.RevokeToken(&models.RevokedToken{
+			JTI:       tokenID,
+			UserID:    userID,
+			ExpiresAt: tokenExpiresAt,
+		}); err != nil {
+			return err
+		}
+		return sessionRepo.PurgeExpiredRevokedTokens()
+	})
+}
+
+func (s *AuthService) LogoutAll(userID, tokenID string, tokenExpiresAt time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		sessionRepo := s.sessionRepo.WithTx(tx)
+		
+		if err := sessionRepo.RevokeAllForUser(userID); err != nil {
+			return err
+		}
+		if err := sessionRepo.RevokeToken(&models.RevokedToken{
+			JTI:       tokenID,
+			UserID:    userID,
+			ExpiresAt: tokenExpiresAt,
+		}); err != nil {
+			return err
+		}
+		return sessionRepo.PurgeExpiredRevokedTokens()
+	})
+}
 
 func (s *AuthService) GetUserProfile(userID string) (*models.User, error) {
 	user, err := s.userRepo.FindByID(userID)
@@ -73,4 +201,37 @@ func (s *AuthService) GetUserProfile(userID string) (*models.User, error) {
 	
 	user.Password = ""
 	return user, nil
-}
\ No newline at end of file
+}
+
+func (s *AuthService) issueTokens(sessionRepo *repositories.SessionRepository, user *models.User, session *models.UserSession) (*models.AuthTokens, error) {
+	refreshToken, refreshHash, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+	
+	if err := sessionRepo.CreateRefreshToken(&models.RefreshToken{
+		SessionID: session.ID,
+		UserID:    user.ID,
+		TokenHash: refreshHash,
+		ExpiresAt: session.ExpiresAt,
+	}); err != nil {
+		return nil, err
+	}
+	
+	accessToken, _, err := utils.GenerateJWT(user.ID, user.Email, user.Name, user.Role, session.ID, s.config.JWTSecret, s.config.JWTAccessExpireMinutes)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &models.AuthTokens{
+		AccessToken:        accessToken,
+		RefreshToken:       refreshToken,
+		TokenType:          "Bearer",
+		ExpiresIn:          s.config.JWTAccessExpireMinutes * 60,
+		MustChangePassword: user.MustChangePassword,
+	}, nil
+}
+
+func (s *AuthService) refreshTokenTTL() time.Duration {
+	return time.Duration(s.config.RefreshTokenExpireHours) * time.Hour
+}
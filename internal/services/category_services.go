@@ -0,0 +1,277 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// resolveCategory finds the category an item request names, by ID or else by
+// name regardless of case. An unknown name becomes a new root category, so
+// clients that send free-text categories keep working. It returns nil when
+// neither is given.
+func (s *ItemService) resolveCategory(tx *gorm.DB, categoryID, name string) (*models.Category, error) {
+	categoryRepo := s.categoryRepo.WithTx(tx)
+	
+	if categoryID != "" {
+		category, err := categoryRepo.FindByID(categoryID)
+		if err != nil {
+			return nil, errors.New("category not found")
+		}
+		return category, nil
+	}
+	
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	
+	category, err := categoryRepo.FindByName(name)
+	if err != nil || category != nil {
+		return category, err
+	}
+	
+	category = &models.Category{Name: name}
+	if err := categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// setCategory puts an item in a category, or takes it out of any with nil.
+func setCategory(item *models.Item, category *models.Category) {
+	if category == nil {
+		item.CategoryID = nil
+		item.Category = ""
+		return
+	}
+	item.CategoryID = &category.ID
+	item.Category = category.Name
+}
+
+type CategoryService struct {
+	db           *gorm.DB
+	categoryRepo *repositories.CategoryRepository
+}
+
+func NewCategoryService() *CategoryService {
+	return &CategoryService{
+		db:           database.DB,
+		categoryRepo: repositories.NewCategoryRepository(),
+	}
+}
+
+// GetCategoryTree returns the root categories with their subcategories
+// nested under them, each level sorted by name.
+func (s *CategoryService) GetCategoryTree() ([]*models.Category, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	
+	byID := make(map[string]*models.Category, len(categories))
+	for i := range categories {
+		byID[categories[i].ID] = &categories[i]
+	}
+	
+	roots := make([]*models.Category, 0)
+	for i := range categories {
+		category := &categories[i]
+		if category.ParentID == nil || byID[*category.ParentID] == nil {
+			roots = append(roots, category)
+			continue
+		}
+		parent := byID[*category.ParentID]
+		parent.Children = append(parent.Children, category)
+	}
+	return roots, nil
+}
+
+// GetCategoryByID returns a category with its subtree.
+func (s *CategoryService) GetCategoryByID(id string) (*models.Category, error) {
+	tree, err := s.GetCategoryTree()
+	if err != nil {
+		return nil, err
+	}
+	if category := findCategory(tree, id); category != nil {
+		return category, nil
+	}
+	return nil, errors.New("category not found")
+}
+
+func findCategory(categories []*models.Category, id string) *models.Category {
+	for _, category := range categories {
+		if category.ID == id {
+			return category
+		}
+		if found := findCategory(category.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (s *CategoryService) CreateCategory(req *models.CreateCategoryRequest) (*models.Category, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	
+	existing, err := s.categoryRepo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("category %s already exists", existing.Name)
+	}
+	
+	category := &models.Category{
+		Name:        name,
+		Description: req.Description,
+	}
+	if req.ParentID != "" {
+		if _, err := s.categoryRepo.FindByID(req.ParentID); err != nil {
+			return nil, errors.New("parent category not found")
+		}
+		category.ParentID = &req.ParentID
+	}
+	
+	if err := s.categoryRepo.Create(category); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// UpdateCategory renames or moves a category. A rename is carried over to
+// its items; a move may not put a category below itself.
+func (s *CategoryService) UpdateCategory(id string, req *models.UpdateCategoryRequest) (*models.Category, error) {
+	var category *models.Category
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+		
+		var err error
+		category, err = categoryRepo.FindByID(id)
+		if err != nil {
+			return errors.New("category not found")
+		}
+		oldName := category.Name
+		
+		if name := strings.TrimSpace(req.Name); name != "" && name != category.Name {
+			existing, err := categoryRepo.FindByName(name)
+			if err != nil {
+				return err
+			}
+			if existing != nil && existing.ID != category.ID {
+				return fmt.Errorf("category %s already exists", existing.Name)
+			}
+			category.Name = name
+		}
+		if req.Description != nil {
+			category.Description = *req.Description
+		}
+		if req.ParentID != nil {
+			if err := s.checkMove(categoryRepo, category, *req.ParentID); err != nil {
+				return err
+			}
+			category.ParentID = nil
+			if *req.ParentID != "" {
+				category.ParentID = req.ParentID
+			}
+		}
+		
+		if err := categoryRepo.Update(category); err != nil {
+			return err
+		}
+		if category.Name != oldName {
+			return categoryRepo.Rename(category, oldName)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// checkMove walks up from the new parent to make sure the category is not
+// among its ancestors. Moves are serialized first, so the walk sees every
+// move committed before it.
+func (s *CategoryService) checkMove(categoryRepo *repositories.CategoryRepository, category *models.Category, parentID string) error {
+	if err := categoryRepo.LockMoves(); err != nil {
+		return err
+	}
+	
+	for id := parentID; id != ""; {
+		if id == category.ID {
+			return errors.New("a category cannot be moved below itself")
+		}
+		parent, err := categoryRepo.FindByID(id)
+		if err != nil {
+			return errors.New("parent category not found")
+		}
+		if parent.ParentID == nil {
+			break
+		}
+		id = *parent.ParentID
+	}
+	return nil
+}
+
+// DeleteCategory removes a category without subcategories. Its items are
+// moved to moveTo when given, which also merges duplicate categories;
+// otherwise the category must be empty.
+func (s *CategoryService) DeleteCategory(id, moveTo string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		categoryRepo := s.categoryRepo.WithTx(tx)
+		
+		category, err := categoryRepo.FindByID(id)
+		if err != nil {
+			return errors.New("category not found")
+		}
+		
+		children, err := categoryRepo.CountChildren(category.ID)
+		if err != nil {
+			return err
+		}
+		if children > 0 {
+			return errors.New("category has subcategories and cannot be deleted")
+		}
+		
+		attributes, err := categoryRepo.CountAttributes(category.Name)
+		if err != nil {
+			return err
+		}
+		if attributes > 0 {
+			return errors.New("category has attribute definitions and cannot be deleted")
+		}
+		
+		if moveTo != "" {
+			if moveTo == category.ID {
+				return errors.New("items cannot be moved to the category being deleted")
+			}
+			target, err := categoryRepo.FindByID(moveTo)
+			if err != nil {
+				return errors.New("target category not found")
+			}
+			if err := categoryRepo.MoveItems(category.ID, target); err != nil {
+				return err
+			}
+		} else {
+			items, err := categoryRepo.CountItems(category.ID)
+			if err != nil {
+				return err
+			}
+			if items > 0 {
+				return errors.New("category has items and cannot be deleted")
+			}
+		}
+		
+		return categoryRepo.Delete(category.ID)
+	})
+}
\ No newline at end of file
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"inventory-api/internal/models"
+)
+
+// TestConcurrentCategoryMovesMakeNoCycle moves two categories below each
+// other at once. Only one move may win; the other must see it and refuse.
+func TestConcurrentCategoryMovesMakeNoCycle(t *testing.T) {
+	openTestDB(t)
+	service := NewCategoryService()
+	
+	create := func() *models.Category {
+		category, err := service.CreateCategory(&models.CreateCategoryRequest{Name: "Test " + uuid.New().String()})
+		if err != nil {
+			t.Fatalf("create category: %v", err)
+		}
+		return category
+	}
+	a, b := create(), create()
+	
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	move := func(category, parent *models.Category) {
+		defer wg.Done()
+		parentID := parent.ID
+		_, err := service.UpdateCategory(category.ID, &models.UpdateCategoryRequest{ParentID: &parentID})
+		errs <- err
+	}
+	wg.Add(2)
+	go move(a, b)
+	go move(b, a)
+	wg.Wait()
+	close(errs)
+	
+	moved := 0
+	for err := range errs {
+		if err == nil {
+			moved++
+		}
+	}
+	if moved != 1 {
+		t.Fatalf("%d moves succeeded, want exactly 1", moved)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,344 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// costSlice is a quantity at one unit cost, either taken from a layer or
+// about to become one.
+type costSlice struct {
+	Quantity int
+	UnitCost float64
+}
+
+func costValue(slices []costSlice) float64 {
+	value := 0.0
+	for _, slice := range slices {
+		value += float64(slice.Quantity) * slice.UnitCost
+	}
+	return roundMoney(value)
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// priceStockChange values a quantity change at one warehouse before its
+// movement is written. Increments are priced as received; decrements consume
+// cost layers, and the negated total is their COGS. The returned slices are
+// what openCostLayers should record for an increment.
+func (s *ItemService) priceStockChange(tx *gorm.DB, item *models.Item, warehouseID string, delta int, unitCost float64) ([]costSlice, float64, error) {
+	if delta > 0 {
+		cost, err := s.incomingUnitCost(tx, item, warehouseID, unitCost)
+		if err != nil {
+			return nil, 0, err
+		}
+		slices := []costSlice{{Quantity: delta, UnitCost: cost}}
+		return slices, costValue(slices), nil
+	}
+	if delta < 0 {
+		slices, err := s.consumeCostLayers(tx, item, warehouseID, -delta)
+		if err != nil {
+			return nil, 0, err
+		}
+		return slices, -costValue(slices), nil
+	}
+	return nil, 0, nil
+}
+
+// movementUnitCost is the average cost per unit of a priced movement.
+func movementUnitCost(totalCost float64, quantity int) float64 {
+	if quantity == 0 {
+		return 0
+	}
+	return roundMoney(math.Abs(totalCost / float64(quantity)))
+}
+
+// costingMethod is the item's own method, or the configured default.
+func (s *ItemService) costingMethod(item *models.Item) models.CostingMethod {
+	if item.CostingMethod != "" {
+		return item.CostingMethod
+	}
+	if method := models.CostingMethod(strings.ToUpper(s.config.CostingMethod)); models.CostingMethods[method] {
+		return method
+	}
+	return models.CostingMethodFIFO
+}
+
+// incomingUnitCost prices an increment that came without a cost, such as a
+// return or correction, at the warehouse's current average, else the item
+// price.
+func (s *ItemService) incomingUnitCost(tx *gorm.DB, item *models.Item, warehouseID string, unitCost float64) (float64, error) {
+	if unitCost > 0 {
+		return unitCost, nil
+	}
+	
+	layers, err := s.costLayerRepo.WithTx(tx).FindOpenForUpdate(item.ID, warehouseID, false)
+	if err != nil {
+		return 0, err
+	}
+	if average, ok := averageCost(layers); ok {
+		return average, nil
+	}
+	return item.Price, nil
+}
+
+// openCostLayers records received stock as new layers. Under the moving
+// average every open layer is then repriced to the new average.
+func (s *ItemService) openCostLayers(tx *gorm.DB, item *models.Item, warehouseID, movementID string, slices []costSlice) error {
+	layerRepo := s.costLayerRepo.WithTx(tx)
+	
+	for _, slice := range slices {
+		if slice.Quantity <= 0 {
+			continue
+		}
+		layer := &models.CostLayer{
+			ItemID:      item.ID,
+			WarehouseID: warehouseID,
+			MovementID:  movementID,
+			Quantity:    slice.Quantity,
+			Remaining:   slice.Quantity,
+			UnitCost:    slice.UnitCost,
+		}
+		if err := layerRepo.Create(layer); err != nil {
+			return err
+		}
+	}
+	
+	if s.costingMethod(item) != models.CostingMethodAverage {
+		return nil
+	}
+	
+	layers, err := layerRepo.FindOpenForUpdate(item.ID, warehouseID, false)
+	if err != nil {
+		return err
+	}
+	if average, ok := averageCost(layers); ok {
+		return layerRepo.Reprice(item.ID, warehouseID, average)
+	}
+	return nil
+}
+
+// consumeCostLayers takes quantity out of the warehouse's open layers,
+// oldest first under FIFO and AVERAGE, newest first under LIFO, and returns
+// what was consumed. Stock that predates costing has no layers; it is
+// valued at the item price.
+func (s *ItemService) consumeCostLayers(tx *gorm.DB, item *models.Item, warehouseID string, quantity int) ([]costSlice, error) {
+	if quantity <= 0 {
+		return nil, errors.New("quantity to consume must be positive")
+	}
+	
+	method := s.costingMethod(item)
+	layerRepo := s.costLayerRepo.WithTx(tx)
+	
+	layers, err := layerRepo.FindOpenForUpdate(item.ID, warehouseID, method == models.CostingMethodLIFO)
+	if err != nil {
+		return nil, err
+	}
+	
+	if method == models.CostingMethodAverage {
+		if average, ok := averageCost(layers); ok {
+			for i := range layers {
+				layers[i].UnitCost = average
+			}
+		}
+	}
+	
+	var slices []costSlice
+	for _, layer := range layers {
+		if quantity == 0 {
+			break
+		}
+		taken := layer.Remaining
+		if taken > quantity {
+			taken = quantity
+		}
+		if err := layerRepo.UpdateRemaining(layer.ID, layer.Remaining-taken); err != nil {
+			return nil, err
+		}
+		slices = append(slices, costSlice{Quantity: taken, UnitCost: layer.UnitCost})
+		quantity -= taken
+	}
+	if quantity > 0 {
+		slices = append(slices, costSlice{Quantity: quantity, UnitCost: item.Price})
+	}
+	
+	if method == models.CostingMethodAverage && len(layers) > 0 {
+		if err := layerRepo.Reprice(item.ID, warehouseID, layers[0].UnitCost); err != nil {
+			return nil, err
+		}
+	}
+	
+	return slices, nil
+}
+
+func averageCost(layers []models.CostLayer) (float64, bool) {
+	quantity := 0
+	value := 0.0
+	for _, layer := range layers {
+		quantity += layer.Remaining
+		value += float64(layer.Remaining) * layer.UnitCost
+	}
+	if quantity == 0 {
+		return 0, false
+	}
+	return math.Round(value/float64(quantity)*10000) / 10000, true
+}
+
+func (s *ItemService) GetCostLayers(id string) ([]models.CostLayer, error) {
+	if _, err := s.itemRepo.FindByID(id); err != nil {
+		return nil, errors.New("item not found")
+	}
+	return s.costLayerRepo.FindOpenByItemID(id)
+}
+
+type ValuationService struct {
+	movementRepo *repositories.StockMovementRepository
+	categoryRepo *repositories.CategoryRepository
+}
+
+func NewValuationService() *ValuationService {
+	return &ValuationService{
+		movementRepo: repositories.NewStockMovementRepository(),
+		categoryRepo: repositories.NewCategoryRepository(),
+	}
+}
+
+// GetValuation reports on-hand quantity and value from the ledger, so any
+// past date is answered the same way as today.
+func (s *ValuationService) GetValuation(filter *models.ValuationFilter) (*models.InventoryValuation, error) {
+	if filter.GroupBy == "" {
+		filter.GroupBy = models.ValuationGroupCategory
+	}
+	if filter.GroupBy != models.ValuationGroupCategory && filter.GroupBy != models.ValuationGroupWarehouse {
+		return nil, errors.New("group_by must be category or warehouse")
+	}
+	if filter.From != nil && filter.From.After(filter.AsOf) {
+		return nil, errors.New("from must not be after as_of")
+	}
+	
+	lines, err := s.movementRepo.Valuation(filter)
+	if err != nil {
+		return nil, err
+	}
+	
+	result := &models.InventoryValuation{
+		GroupBy: filter.GroupBy,
+		AsOf:    filter.AsOf,
+		From:    filter.From,
+		Lines:   lines,
+	}
+	
+	// The ledger lines do not overlap, so they make the totals before
+	// category lines are rolled up the tree.
+	for _, line := range lines {
+		result.TotalQuantity += line.Quantity
+		result.TotalValue += line.Value
+		result.TotalCOGS += line.COGS
+	}
+	if filter.GroupBy == models.ValuationGroupCategory {
+		if result.Lines, err = s.rollUpCategories(lines, filter.Category); err != nil {
+			return nil, err
+		}
+	}
+	
+	for i := range result.Lines {
+		line := &result.Lines[i]
+		line.Value = roundMoney(line.Value)
+		line.COGS = roundMoney(line.COGS)
+		if line.Name == "" && filter.GroupBy == models.ValuationGroupCategory {
+			line.Name = "Uncategorized"
+		}
+	}
+	result.TotalValue = roundMoney(result.TotalValue)
+	result.TotalCOGS = roundMoney(result.TotalCOGS)
+	if result.Lines == nil {
+		result.Lines = []models.ValuationLine{}
+	}
+	
+	return result, nil
+}
+
+// rollUpCategories adds each category's own totals to all of its ancestors
+// and lists the categories depth first. With a category filter the roll-up
+// stops at that category. Uncategorized stock comes last.
+func (s *ValuationService) rollUpCategories(lines []models.ValuationLine, rootName string) ([]models.ValuationLine, error) {
+	categories, err := s.categoryRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	
+	byID := make(map[string]*models.Category, len(categories))
+	children := make(map[string][]string)
+	var roots []string
+	for i := range categories {
+		category := &categories[i]
+		byID[category.ID] = category
+		if category.ParentID == nil {
+			roots = append(roots, category.ID)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+	
+	stop := ""
+	if rootName != "" {
+		roots = nil
+		for _, category := range categories {
+			if strings.EqualFold(category.Name, rootName) {
+				stop = category.ID
+				roots = []string{category.ID}
+			}
+		}
+	}
+	
+	totals := make(map[string]*models.ValuationLine)
+	var uncategorized []models.ValuationLine
+	for _, line := range lines {
+		if byID[line.Key] == nil {
+			uncategorized = append(uncategorized, line)
+			continue
+		}
+		
+		for id := line.Key; ; {
+			category := byID[id]
+			total := totals[id]
+			if total == nil {
+				total = &models.ValuationLine{Key: id, Name: category.Name}
+				if category.ParentID != nil && id != stop {
+					total.ParentID = *category.ParentID
+				}
+				totals[id] = total
+			}
+			total.Quantity += line.Quantity
+			total.Value += line.Value
+			total.COGS += line.COGS
+			
+			if id == stop || category.ParentID == nil {
+				break
+			}
+			id = *category.ParentID
+		}
+	}
+	
+	rolled := make([]models.ValuationLine, 0, len(totals)+len(uncategorized))
+	var walk func(ids []string)
+	walk = func(ids []string) {
+		for _, id := range ids {
+			if total := totals[id]; total != nil {
+				rolled = append(rolled, *total)
+				walk(children[id])
+			}
+		}
+	}
+	walk(roots)
+	
+	return append(rolled, uncategorized...), nil
+}
\ No newline at end of file
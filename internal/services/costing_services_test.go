@@ -0,0 +1,62 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"inventory-api/internal/models"
+)
+
+// TestDecrementsConsumeCostLayers receives two layers at different costs,
+// issues across both and checks the COGS and what is left under each
+// costing method.
+func TestDecrementsConsumeCostLayers(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	tests := []struct {
+		method    models.CostingMethod
+		cogs      float64
+		remaining float64
+	}{
+		{models.CostingMethodFIFO, 40, 20},
+		{models.CostingMethodLIFO, 50, 10},
+		{models.CostingMethodAverage, 45, 15},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.method), func(t *testing.T) {
+			item := createTestItem(t, service, user, models.CreateItemRequest{CostingMethod: string(tt.method)})
+			
+			for _, cost := range []float64{2, 4} {
+				updateTestStock(t, service, user, item.ID, models.UpdateStockRequest{
+					Quantity: 10,
+					Type:     "increment",
+					UnitCost: cost,
+				})
+			}
+			updateTestStock(t, service, user, item.ID, models.UpdateStockRequest{
+				Quantity:   15,
+				Type:       "decrement",
+				ReasonCode: models.ReasonCodeIssue,
+			})
+			
+			issues := findMovements(t, item.ID, models.ReasonCodeIssue)
+			if len(issues) != 1 || issues[0].TotalCost != -tt.cogs {
+				t.Errorf("issue movements = %+v, want one costing %.2f", issues, -tt.cogs)
+			}
+			
+			layers, err := service.GetCostLayers(item.ID)
+			if err != nil {
+				t.Fatalf("load cost layers: %v", err)
+			}
+			value := 0.0
+			for _, layer := range layers {
+				value += float64(layer.Remaining) * layer.UnitCost
+			}
+			if math.Abs(value-tt.remaining) > 0.005 {
+				t.Errorf("remaining layer value = %.4f, want %.2f", value, tt.remaining)
+			}
+		})
+	}
+}
\ No newline at end of file
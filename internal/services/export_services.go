@@ -0,0 +1,185 @@
+package services
+
+import (
+	"fmt"
+	"io"
+
+	"inventory-api/internal/export"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+type ExportService struct {
+	itemRepo        *repositories.ItemRepository
+	activityService *ActivityService
+}
+
+func NewExportService() *ExportService {
+	return &ExportService{
+		itemRepo:        repositories.NewItemRepository(),
+		activityService: NewActivityService(),
+	}
+}
+
+var itemExportColumns = []export.Column{
+	{Title: "SKU", Width: 1.4},
+	{Title: "Name", Width: 2.5},
+	{Title: "Category", Width: 1.4},
+	{Title: "Location", Width: 1.2},
+	{Title: "Stock", Width: 0.8},
+	{Title: "Reserved", Width: 0.8},
+	{Title: "Available", Width: 0.8},
+	{Title: "Min Stock", Width: 0.8},
+	{Title: "Max Stock", Width: 0.8},
+	{Title: "Price", Width: 1},
+	{Title: "Stock Value", Width: 1.2},
+}
+
+// ExportItems writes every item matching filter to w. The PDF stock sheet
+// is grouped by category with a subtotal after each category and a grand
+// total at the end, so for that format the category becomes the first sort
+// key.
+func (s *ExportService) ExportItems(w io.Writer, format string, filter *models.ItemFilter) error {
+	writer, err := export.NewWriter(format, w, "Stock Sheet")
+	if err != nil {
+		return err
+	}
+	
+	grouped := format == export.FormatPDF
+	if grouped {
+		filter.Sort = append([]string{"category ASC"}, filter.Sort...)
+	}
+	
+	if err := writer.WriteHeader(itemExportColumns); err != nil {
+		return err
+	}
+	
+	var (
+		started       bool
+		category      string
+		categoryStock int
+		categoryValue float64
+		totalStock    int
+		totalValue    float64
+	)
+	writeCategoryTotal := func() error {
+		label := category
+		if label == "" {
+			label = "Uncategorized"
+		}
+		return writer.WriteSubtotal("Total "+label, itemTotalCells(categoryStock, categoryValue))
+	}
+	
+	err = s.itemRepo.EachWithFilter(filter, func(item *models.Item) error {
+		if grouped && started && item.Category != category {
+			if err := writeCategoryTotal(); err != nil {
+				return err
+			}
+			categoryStock, categoryValue = 0, 0
+		}
+		started = true
+		category = item.Category
+		
+		value := float64(item.Stock) * item.Price
+		categoryStock += item.Stock
+		categoryValue += value
+		totalStock += item.Stock
+		totalValue += value
+		
+		return writer.WriteRow(item, []interface{}{
+			item.SKU,
+			item.Name,
+			item.Category,
+			item.Location,
+			item.Stock,
+			item.ReservedStock,
+			item.AvailableStock,
+			item.MinStock,
+			item.MaxStock,
+			item.Price,
+			value,
+		})
+	})
+	if err != nil {
+		return failExport(writer, err)
+	}
+	
+	if grouped && started {
+		if err := writeCategoryTotal(); err != nil {
+			return err
+		}
+	}
+	if err := writer.WriteSubtotal("Grand Total", itemTotalCells(totalStock, totalValue)); err != nil {
+		return err
+	}
+	
+	return writer.Close()
+}
+
+func itemTotalCells(stock int, value float64) []interface{} {
+	cells := make([]interface{}, len(itemExportColumns))
+	cells[4] = stock
+	cells[10] = value
+	return cells
+}
+
+var activityExportColumns = []export.Column{
+	{Title: "Date", Width: 1.6},
+	{Title: "Action", Width: 1.6},
+	{Title: "Item", Width: 2},
+	{Title: "Quantity", Width: 0.8},
+	{Title: "Old Stock", Width: 0.8},
+	{Title: "New Stock", Width: 0.8},
+	{Title: "User", Width: 1.2},
+	{Title: "Warehouse", Width: 1.2},
+	{Title: "Reference", Width: 1.6},
+	{Title: "Description", Width: 2.4},
+}
+
+// ExportActivities writes the movement journal for the same filters as the
+// activity list, oldest entry first.
+func (s *ExportService) ExportActivities(w io.Writer, format string, filter *models.ActivityFilter) error {
+	writer, err := export.NewWriter(format, w, "Movement Journal")
+	if err != nil {
+		return err
+	}
+	
+	if err := writer.WriteHeader(activityExportColumns); err != nil {
+		return err
+	}
+	
+	err = s.activityService.EachActivity(filter, func(activity *models.ActivityLog) error {
+		reference := activity.ReferenceType
+		if activity.ReferenceID != "" {
+			reference += " " + activity.ReferenceID
+		}
+		
+		return writer.WriteRow(activity, []interface{}{
+			activity.CreatedAt,
+			string(activity.Action),
+			activity.ItemName,
+			activity.Quantity,
+			activity.OldStock,
+			activity.NewStock,
+			activity.UserName,
+			activity.WarehouseID,
+			reference,
+			activity.Description,
+		})
+	})
+	if err != nil {
+		return failExport(writer, err)
+	}
+	
+	return writer.Close()
+}
+
+// failExport ends an export that broke off part way with a marker saying
+// so, since the client has already been sent a success status. It returns
+// err for logging.
+func failExport(writer export.Writer, err error) error {
+	if failErr := writer.Fail(err); failErr != nil {
+		return fmt.Errorf("%w (and failed to mark the export: %v)", err, failErr)
+	}
+	return err
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+var (
+	ErrIdempotencyKeyReused   = errors.New("idempotency key was already used for a different request")
+	ErrIdempotencyKeyInFlight = errors.New("a request with this idempotency key is still being processed")
+)
+
+// idempotencyClaimTimeout is how long a claimed key may go without a stored
+// response before it is taken to be abandoned, e.g. by a crashed server.
+const idempotencyClaimTimeout = 5 * time.Minute
+
+type IdempotencyService struct {
+	idempotencyRepo *repositories.IdempotencyRepository
+	config          *config.Config
+}
+
+func NewIdempotencyService(cfg *config.Config) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: repositories.NewIdempotencyRepository(),
+		config:          cfg,
+	}
+}
+
+func (s *IdempotencyService) ttl() time.Duration {
+	return time.Duration(s.config.IdempotencyTTLHours) * time.Hour
+}
+
+// Begin claims the key for a request. It returns the new claim and true when
+// the request should run, or the completed original and false when its
+// response should be replayed. A key reused with a different request hash
+// fails with ErrIdempotencyKeyReused.
+func (s *IdempotencyService) Begin(userID, key, method, path, requestHash string) (*models.IdempotencyKey, bool, error) {
+	existing, err := s.idempotencyRepo.FindByKey(userID, key)
+	if err != nil {
+		return nil, false, err
+	}
+	
+	now := time.Now()
+	if existing != nil && s.abandoned(existing, now) {
+		if err := s.idempotencyRepo.Delete(existing.ID); err != nil {
+			return nil, false, err
+		}
+		existing = nil
+	}
+	
+	if existing == nil {
+		record := &models.IdempotencyKey{
+			UserID:      userID,
+			Key:         key,
+			Method:      method,
+			Path:        path,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(s.ttl()),
+		}
+		claimed, err := s.idempotencyRepo.Claim(record)
+		if err != nil {
+			return nil, false, err
+		}
+		if claimed {
+			return record, true, nil
+		}
+		
+		// Another request claimed the key between the lookup and the insert.
+		existing, err = s.idempotencyRepo.FindByKey(userID, key)
+		if err != nil {
+			return nil, false, err
+		}
+		if existing == nil {
+			return nil, false, ErrIdempotencyKeyInFlight
+		}
+	}
+	
+	if existing.RequestHash != requestHash {
+		return nil, false, ErrIdempotencyKeyReused
+	}
+	if !existing.Completed() {
+		return nil, false, ErrIdempotencyKeyInFlight
+	}
+	return existing, false, nil
+}
+
+// abandoned reports whether a stored key no longer holds back a new request:
+// it has expired, or its claim was never completed.
+func (s *IdempotencyService) abandoned(record *models.IdempotencyKey, now time.Time) bool {
+	if !record.ExpiresAt.After(now) {
+		return true
+	}
+	return !record.Completed() && now.Sub(record.CreatedAt) > idempotencyClaimTimeout
+}
+
+// Complete stores the response to a claimed request. Server errors are not
+// stored: the claim is released so that a retry runs the request again.
+func (s *IdempotencyService) Complete(record *models.IdempotencyKey, statusCode int, contentType, etag string, body []byte) error {
+	if statusCode >= 500 {
+		return s.Release(record)
+	}
+	
+	record.StatusCode = statusCode
+	record.ContentType = contentType
+	record.ETag = etag
+	record.ResponseBody = append([]byte(nil), body...)
+	return s.idempotencyRepo.Complete(record)
+}
+
+// Release drops a claim whose request failed before producing a response,
+// or whose response could not be stored.
+func (s *IdempotencyService) Release(record *models.IdempotencyKey) error {
+	return s.idempotencyRepo.Delete(record.ID)
+}
+
+func (s *IdempotencyService) PurgeExpired() error {
+	purged, err := s.idempotencyRepo.DeleteExpired(time.Now())
+	if err != nil {
+		return err
+	}
+	if purged > 0 {
+		log.Printf("Purged %d expired idempotency keys", purged)
+	}
+	return nil
+}
\ No newline at end of file
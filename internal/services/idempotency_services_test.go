@@ -0,0 +1,57 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// TestIdempotencyKeysReplayAndRejectReuse walks a key through its claim: a
+// retry in flight conflicts, a completed one is replayed, a different
+// payload is rejected and a released claim can be taken again.
+func TestIdempotencyKeysReplayAndRejectReuse(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewIdempotencyService(cfg)
+	user := createTestUser(t)
+	
+	key := uuid.New().String()
+	begin := func(hash string) (bool, error) {
+		_, claimed, err := service.Begin(user.ID, key, "POST", "/api/v1/items", hash)
+		return claimed, err
+	}
+	
+	record, claimed, err := service.Begin(user.ID, key, "POST", "/api/v1/items", "first")
+	if err != nil || !claimed {
+		t.Fatalf("first request: claimed = %v, err = %v", claimed, err)
+	}
+	if _, err := begin("first"); !errors.Is(err, ErrIdempotencyKeyInFlight) {
+		t.Errorf("retry in flight: err = %v, want ErrIdempotencyKeyInFlight", err)
+	}
+	
+	if err := service.Complete(record, 201, "application/json", "", []byte(`{"id":"1"}`)); err != nil {
+		t.Fatalf("complete: %v", err)
+	}
+	replayed, claimed, err := service.Begin(user.ID, key, "POST", "/api/v1/items", "first")
+	if err != nil || claimed {
+		t.Fatalf("retry: claimed = %v, err = %v", claimed, err)
+	}
+	if replayed.StatusCode != 201 || string(replayed.ResponseBody) != `{"id":"1"}` {
+		t.Errorf("replayed %d %s, want 201 {\"id\":\"1\"}", replayed.StatusCode, replayed.ResponseBody)
+	}
+	if _, err := begin("second"); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("different payload: err = %v, want ErrIdempotencyKeyReused", err)
+	}
+	
+	other := uuid.New().String()
+	record, _, err = service.Begin(user.ID, other, "POST", "/api/v1/items", "first")
+	if err != nil {
+		t.Fatalf("claim second key: %v", err)
+	}
+	if err := service.Complete(record, 500, "application/json", "", nil); err != nil {
+		t.Fatalf("complete with server error: %v", err)
+	}
+	if _, claimed, err := service.Begin(user.ID, other, "POST", "/api/v1/items", "first"); err != nil || !claimed {
+		t.Errorf("retry after server error: claimed = %v, err = %v", claimed, err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,436 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"inventory-api/internal/models"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+var itemImportFields = []string{
+	"name",
+	"sku",
+	"description",
+	"category",
+	"stock",
+	"min_stock",
+	"max_stock",
+	"price",
+	"location",
+}
+
+type importRow struct {
+	line    int
+	values  map[string]string
+	request models.CreateItemRequest
+}
+
+// ImportItems validates every row of a CSV or XLSX sheet and, unless this
+// is a dry run, applies all of them in one transaction. Nothing is written
+// when any row is invalid; the per-row errors are returned in the result.
+func (s *ItemService) ImportItems(filename string, r io.Reader, opts *models.ItemImportOptions, userID string) (*models.ItemImportResult, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	table, err := readImportTable(filename, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(table) == 0 {
+		return nil, errors.New("file is empty")
+	}
+	
+	columns, err := resolveImportColumns(table[0], opts.Mapping)
+	if err != nil {
+		return nil, err
+	}
+	
+	result := &models.ItemImportResult{
+		DryRun: opts.DryRun,
+		Errors: []models.ItemImportRowError{},
+	}
+	
+	rows := parseImportRows(table[1:], columns, result)
+	result.TotalRows += len(rows)
+	
+	skus := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.request.SKU != "" {
+			skus = append(skus, row.request.SKU)
+		}
+	}
+	existing, err := s.itemRepo.FindBySKUs(skus)
+	if err != nil {
+		return nil, err
+	}
+	
+	warehouseID, _, err := s.resolveLocation(s.db, opts.WarehouseID, "")
+	if err != nil {
+		return nil, err
+	}
+	existingIDs := make([]string, 0, len(existing))
+	for _, item := range existing {
+		existingIDs = append(existingIDs, item.ID)
+	}
+	onHand, err := s.itemStockRepo.QuantitiesAt(warehouseID, "", existingIDs)
+	if err != nil {
+		return nil, err
+	}
+	
+	seen := map[string]int{}
+	for _, row := range rows {
+		sku := row.request.SKU
+		item, exists := existing[sku]
+		
+		if sku != "" {
+			if first, ok := seen[sku]; ok {
+				result.Errors = append(result.Errors, models.ItemImportRowError{
+					Row: row.line, Field: "sku", Message: fmt.Sprintf("duplicate sku, already used on row %d", first),
+				})
+				continue
+			}
+			seen[sku] = row.line
+		}
+		
+		switch {
+		case exists && !opts.Upsert:
+			result.Errors = append(result.Errors, models.ItemImportRowError{
+				Row: row.line, Field: "sku", Message: "sku already exists",
+			})
+		case exists:
+			if _, ok := row.values["stock"]; ok {
+				if message := importStockError(item, onHand[item.ID], row.request.Stock); message != "" {
+					result.Errors = append(result.Errors, models.ItemImportRowError{
+						Row: row.line, Field: "stock", Message: message,
+					})
+					continue
+				}
+			}
+			result.Updated++
+		case row.request.Name == "":
+			result.Errors = append(result.Errors, models.ItemImportRowError{
+				Row: row.line, Field: "name", Message: "name is required",
+			})
+		default:
+			result.Created++
+		}
+	}
+	
+	sort.SliceStable(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Row < result.Errors[j].Row
+	})
+	
+	if opts.DryRun || len(result.Errors) > 0 {
+		return result, nil
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		for i := range rows {
+			row := &rows[i]
+			
+			if item, ok := existing[row.request.SKU]; ok && row.request.SKU != "" {
+				if err := s.importUpdate(tx, user, item.ID, row, warehouseID); err != nil {
+					return fmt.Errorf("row %d: %w", row.line, err)
+				}
+				continue
+			}
+			
+			row.request.WarehouseID = warehouseID
+			if _, err := s.createItem(tx, user, &row.request); err != nil {
+				return fmt.Errorf("row %d: %w", row.line, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	result.Imported = true
+	return result, nil
+}
+
+// importUpdate applies the columns present in the row to an existing item.
+// The stock column is the quantity held in the import's warehouse outside
+// any bin; a change to it is booked there as a correction so the ledger
+// still explains the new on-hand quantity.
+func (s *ItemService) importUpdate(tx *gorm.DB, user *models.User, itemID string, row *importRow, warehouseID string) error {
+	itemRepo := s.itemRepo.WithTx(tx)
+	
+	item, err := itemRepo.FindByIDForUpdate(itemID)
+	if err != nil {
+		return err
+	}
+	
+	req := &row.request
+	if _, ok := row.values["name"]; ok && req.Name != "" {
+		item.Name = req.Name
+	}
+	if _, ok := row.values["description"]; ok {
+		item.Description = req.Description
+	}
+	if _, ok := row.values["category"]; ok {
+		category, err := s.resolveCategory(tx, "", req.Category)
+		if err != nil {
+			return err
+		}
+		setCategory(item, category)
+	}
+	if _, ok := row.values["min_stock"]; ok {
+		item.MinStock = req.MinStock
+	}
+	if _, ok := row.values["max_stock"]; ok {
+		item.MaxStock = req.MaxStock
+	}
+	if _, ok := row.values["price"]; ok {
+		item.Price = req.Price
+	}
+	if _, ok := row.values["location"]; ok {
+		item.Location = req.Location
+	}
+	
+	if err := itemRepo.Update(item); err != nil {
+		return err
+	}
+	
+	activity := &models.ActivityLog{
+		UserID:      user.ID,
+		UserName:    user.Name,
+		ItemID:      item.ID,
+		ItemName:    item.Name,
+		Action:      models.ActivityTypeItemUpdated,
+		OldStock:    item.Stock,
+		NewStock:    item.Stock,
+		Description: "Item updated by import",
+	}
+	if err := s.logActivity(tx, activity, item.Category); err != nil {
+		return err
+	}
+	
+	if _, ok := row.values["stock"]; !ok {
+		return nil
+	}
+	
+	current, err := s.itemStockRepo.WithTx(tx).QuantitiesAt(warehouseID, "", []string{item.ID})
+	if err != nil {
+		return err
+	}
+	delta := req.Stock - current[item.ID]
+	if delta == 0 {
+		return nil
+	}
+	if message := importStockError(item, current[item.ID], req.Stock); message != "" {
+		return errors.New(message)
+	}
+	
+	action := models.ActivityTypeStockIncrement
+	if delta < 0 {
+		action = models.ActivityTypeStockDecrement
+	}
+	_, err = s.applyStockChange(tx, user, &stockChange{
+		ItemID:      item.ID,
+		WarehouseID: warehouseID,
+		Delta:       delta,
+		ReasonCode:  models.ReasonCodeCorrection,
+		Action:      action,
+		Description: "Stock set by import",
+	})
+	return err
+}
+
+// importStockError explains why the stock column cannot move an item from
+// its current quantity at the import location to the given one, or returns
+// "" when it can.
+func importStockError(item *models.Item, current, stock int) string {
+	delta := stock - current
+	switch {
+	case delta == 0:
+		return ""
+	case item.LotTracked:
+		return "stock of a lot-tracked item cannot be set by import, adjust it per lot instead"
+	case item.Serialized:
+		return "stock of a serialized item cannot be set by import, adjust it with serial numbers instead"
+	case delta < 0 && item.Stock-item.ReservedStock+delta < 0:
+		return fmt.Sprintf("would leave less than the %d reserved", item.ReservedStock)
+	}
+	return ""
+}
+
+func readImportTable(filename string, r io.Reader) ([][]string, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		
+		table, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid csv: %w", err)
+		}
+		if len(table) > 0 && len(table[0]) > 0 {
+			table[0][0] = strings.TrimPrefix(table[0][0], "\ufeff")
+		}
+		return table, nil
+	case ".xlsx":
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid xlsx: %w", err)
+		}
+		defer f.Close()
+		
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("workbook has no sheets")
+		}
+		return f.GetRows(sheets[0])
+	default:
+		return nil, errors.New("unsupported file type, expected .csv or .xlsx")
+	}
+}
+
+// resolveImportColumns finds the column index of every field. Headers are
+// compared case-insensitively with spaces and dashes read as underscores,
+// so "Min Stock" matches min_stock without a mapping.
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	for field := range mapping {
+		if !isImportField(field) {
+			return nil, fmt.Errorf("unknown field in mapping: %s", field)
+		}
+	}
+	
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[normalizeHeader(name)] = i
+	}
+	
+	columns := map[string]int{}
+	for _, field := range itemImportFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+		
+		i, ok := index[normalizeHeader(name)]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s not found", name, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+	
+	if _, ok := columns["name"]; !ok {
+		if _, ok := columns["sku"]; !ok {
+			return nil, errors.New("sheet needs a name or sku column")
+		}
+	}
+	
+	return columns, nil
+}
+
+func parseImportRows(table [][]string, columns map[string]int, result *models.ItemImportResult) []importRow {
+	var rows []importRow
+	
+	for i, record := range table {
+		line := i + 2
+		
+		values := map[string]string{}
+		blank := true
+		for field, col := range columns {
+			if col < len(record) {
+				value := strings.TrimSpace(record[col])
+				if value != "" {
+					values[field] = value
+					blank = false
+				}
+			}
+		}
+		if blank {
+			continue
+		}
+		
+		row := importRow{line: line, values: values}
+		valid := true
+		fail := func(field, message string) {
+			result.Errors = append(result.Errors, models.ItemImportRowError{Row: line, Field: field, Message: message})
+			valid = false
+		}
+		
+		row.request.Name = values["name"]
+		row.request.SKU = values["sku"]
+		row.request.Description = values["description"]
+		row.request.Category = values["category"]
+		row.request.Location = values["location"]
+		
+		for _, target := range []struct {
+			field string
+			value *int
+		}{
+			{"stock", &row.request.Stock},
+			{"min_stock", &row.request.MinStock},
+			{"max_stock", &row.request.MaxStock},
+		} {
+			field := target.field
+			value, ok := values[field]
+			if !ok {
+				continue
+			}
+			n, err := strconv.Atoi(value)
+			switch {
+			case err != nil:
+				fail(field, "must be a whole number")
+			case n < 0:
+				fail(field, "must not be negative")
+			default:
+				*target.value = n
+			}
+		}
+		
+		if value, ok := values["price"]; ok {
+			price, err := strconv.ParseFloat(value, 64)
+			switch {
+			case err != nil:
+				fail("price", "must be a number")
+			case price < 0:
+				fail("price", "must not be negative")
+			default:
+				row.request.Price = price
+			}
+		}
+		
+		if valid {
+			rows = append(rows, row)
+		} else {
+			result.TotalRows++
+		}
+	}
+	
+	return rows
+}
+
+func isImportField(field string) bool {
+	for _, f := range itemImportFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHeader(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.ReplaceAll(name, " ", "_")
+	return strings.ReplaceAll(name, "-", "_")
+}
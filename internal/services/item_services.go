@@ -2,22 +2,58 @@ package services
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
 	"inventory-api/internal/models"
 	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
 )
 
 type ItemService struct {
-	itemRepo *repositories.ItemRepository
-	activityRepo *repositories.ActivityRepository
-	userRepo *repositories.UserRepository
+	db              *gorm.DB
+	itemRepo        *repositories.ItemRepository
+	itemStockRepo   *repositories.ItemStockRepository
+	reservationRepo *repositories.ReservationRepository
+	salesOrderRepo  *repositories.SalesOrderRepository
+	warehouseRepo   *repositories.WarehouseRepository
+	movementRepo    *repositories.StockMovementRepository
+	costLayerRepo   *repositories.CostLayerRepository
+	lotRepo         *repositories.LotRepository
+	serialRepo      *repositories.SerialRepository
+	unitRepo        *repositories.UnitRepository
+	attributeRepo   *repositories.AttributeRepository
+	kitRepo         *repositories.KitRepository
+	categoryRepo    *repositories.CategoryRepository
+	activityRepo    *repositories.ActivityRepository
+	alertRepo       *repositories.StockAlertRepository
+	userRepo        *repositories.UserRepository
+	config          *config.Config
 }
 
-func NewItemService() *ItemService {
+func NewItemService(cfg *config.Config) *ItemService {
 	return &ItemService{
-		itemRepo:     repositories.NewItemRepository(),
-		activityRepo: repositories.NewActivityRepository(),
-		userRepo:     repositories.NewUserRepository(),
+		db:              database.DB,
+		itemRepo:        repositories.NewItemRepository(),
+		itemStockRepo:   repositories.NewItemStockRepository(),
+		reservationRepo: repositories.NewReservationRepository(),
+		salesOrderRepo:  repositories.NewSalesOrderRepository(),
+		warehouseRepo:   repositories.NewWarehouseRepository(),
+		movementRepo:    repositories.NewStockMovementRepository(),
+		costLayerRepo:   repositories.NewCostLayerRepository(),
+		lotRepo:         repositories.NewLotRepository(),
+		serialRepo:      repositories.NewSerialRepository(),
+		unitRepo:        repositories.NewUnitRepository(),
+		attributeRepo:   repositories.NewAttributeRepository(),
+		kitRepo:         repositories.NewKitRepository(),
+		categoryRepo:    repositories.NewCategoryRepository(),
+		activityRepo:    repositories.NewActivityRepository(),
+		alertRepo:       repositories.NewStockAlertRepository(),
+		userRepo:        repositories.NewUserRepository(),
+		config:          cfg,
 	}
 }
 
@@ -27,168 +63,528 @@ func (s *ItemService) CreateItem(req *models.CreateItemRequest, userID string) (
 		return nil, errors.New("user not found")
 	}
 	
+	var item *models.Item
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		item, err = s.createItem(tx, user, req)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return item, nil
+}
+
+// createItem writes the item with its opening stock, activity and ledger
+// entry inside the caller's transaction.
+func (s *ItemService) createItem(tx *gorm.DB, user *models.User, req *models.CreateItemRequest) (*models.Item, error) {
+	if req.LotTracked && req.Serialized {
+		return nil, errors.New("an item cannot be both lot-tracked and serialized")
+	}
+	
+	var lot *stockChange
+	if req.LotTracked && req.Stock > 0 {
+		lotNumber, expiresAt, err := parseLot(req.LotNumber, req.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		lot = &stockChange{Delta: req.Stock, LotNumber: lotNumber, ExpiresAt: expiresAt}
+	}
+	
+	var serials []string
+	if req.Serialized {
+		var err error
+		if serials, err = normalizeSerials(req.SerialNumbers); err != nil {
+			return nil, err
+		}
+		if len(serials) != req.Stock {
+			return nil, fmt.Errorf("%d serial numbers given for an opening stock of %d", len(serials), req.Stock)
+		}
+	}
+	
+	base := normalizeUnit(req.BaseUnit)
+	if base == "" {
+		base = models.DefaultBaseUnit
+	} else if err := s.requireUnit(s.unitRepo.WithTx(tx), base); err != nil {
+		return nil, err
+	}
+	
+	category, err := s.resolveCategory(tx, req.CategoryID, req.Category)
+	if err != nil {
+		return nil, err
+	}
+	
+	var parentID *string
+	if req.ParentID != "" {
+		parent, err := s.itemRepo.WithTx(tx).FindByID(req.ParentID)
+		if err != nil {
+			return nil, errors.New("parent item not found")
+		}
+		if parent.ParentID != nil {
+			return nil, errors.New("a variant cannot have variants of its own")
+		}
+		if category == nil && parent.CategoryID != nil {
+			if category, err = s.categoryRepo.WithTx(tx).FindByID(*parent.CategoryID); err != nil {
+				return nil, err
+			}
+		}
+		parentID = &parent.ID
+	}
+	
+	categoryName := ""
+	if category != nil {
+		categoryName = category.Name
+	}
+	attributes, err := s.validateAttributes(tx, categoryName, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	
 	item := &models.Item{
 		Name:        req.Name,
 		Description: req.Description,
-		Category:    req.Category,
 		Stock:       req.Stock,
 		MinStock:    req.MinStock,
 		MaxStock:    req.MaxStock,
 		Price:       req.Price,
 		SKU:         req.SKU,
 		Location:    req.Location,
-		CreatedBy:   userID,
+		CreatedBy:   user.ID,
+		
+		CostingMethod: models.CostingMethod(req.CostingMethod),
+		LotTracked:    req.LotTracked,
+		Serialized:    req.Serialized,
+		BaseUnit:      base,
+		ParentID:      parentID,
+		Attributes:    attributes,
+	}
+	setCategory(item, category)
+	
+	if err := s.itemRepo.WithTx(tx).Create(item); err != nil {
+		return nil, err
 	}
 	
-	if err := s.itemRepo.Create(item); err != nil {
+	warehouseID, binID, err := s.resolveLocation(tx, req.WarehouseID, req.BinID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.moveLocationStock(tx, item.ID, warehouseID, binID, req.Stock); err != nil {
 		return nil, err
 	}
 	
+	lotNumber := ""
+	if lot != nil {
+		if err := s.moveLotStock(tx, item, warehouseID, binID, lot); err != nil {
+			return nil, err
+		}
+		lotNumber = lot.LotNumber
+	}
+	
 	activity := &models.ActivityLog{
-		UserID:       userID,
-		UserName:     user.Name,
-		ItemID:       item.ID,
-		ItemName:     item.Name,
-		Action: models.ActivityTypeItemCreated,
-		Quantity:     req.Stock,
-		OldStock:     0,
-		NewStock:     req.Stock,
-		Description:  "Item created",
-	}
-	s.activityRepo.Create(activity)
+		UserID:      user.ID,
+		UserName:    user.Name,
+		ItemID:      item.ID,
+		ItemName:    item.Name,
+		Action:      models.ActivityTypeItemCreated,
+		Quantity:    req.Stock,
+		OldStock:    0,
+		NewStock:    req.Stock,
+		Description: "Item created",
+		WarehouseID: warehouseID,
+		BinID:       binID,
+		LotNumber:   lotNumber,
+	}
+	if err := s.logActivity(tx, activity, item.Category); err != nil {
+		return nil, err
+	}
+	if len(serials) > 0 {
+		if err := s.moveSerials(tx, item, activity, serials, req.Stock); err != nil {
+			return nil, err
+		}
+	}
+	
+	if req.Stock != 0 {
+		slices, totalCost, err := s.priceStockChange(tx, item, warehouseID, req.Stock, req.Price)
+		if err != nil {
+			return nil, err
+		}
+		
+		movement := &models.StockMovement{
+			ItemID:      item.ID,
+			WarehouseID: warehouseID,
+			BinID:       binID,
+			Quantity:    req.Stock,
+			UnitCost:    movementUnitCost(totalCost, req.Stock),
+			TotalCost:   totalCost,
+			ReasonCode:  models.ReasonCodeInitialStock,
+			LotNumber:   lotNumber,
+			ActivityID:  activity.ID,
+			UserID:      user.ID,
+		}
+		if err := s.movementRepo.WithTx(tx).Create(movement); err != nil {
+			return nil, err
+		}
+		if err := s.openCostLayers(tx, item, warehouseID, movement.ID, slices); err != nil {
+			return nil, err
+		}
+	}
+	
+	if err := s.evaluateStockAlerts(tx, item); err != nil {
+		return nil, err
+	}
 	
 	return item, nil
 }
 
-func (s *ItemService) GetAllItems() ([]models.Item, error) {
-	return s.itemRepo.FindAll()
+func (s *ItemService) GetAllItems(filter *models.ItemFilter, page, limit int) ([]models.Item, int64, error) {
+	return s.itemRepo.FindAllWithFilter(filter, page, limit)
+}
+
+var itemSortColumns = map[string]string{
+	"name":       "name",
+	"sku":        "sku",
+	"category":   "category",
+	"location":   "location",
+	"stock":      "stock",
+	"min_stock":  "min_stock",
+	"price":      "price",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// ParseItemSort turns a sort parameter such as "-price,name" into ORDER BY
+// clauses. A leading "-" sorts that field in descending order.
+func (s *ItemService) ParseItemSort(sort string) ([]string, error) {
+	var orders []string
+	
+	for _, field := range strings.Split(sort, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			direction = "DESC"
+			field = strings.TrimPrefix(field, "-")
+		}
+		
+		column, ok := itemSortColumns[field]
+		if !ok {
+			return nil, fmt.Errorf("invalid sort field: %s", field)
+		}
+		orders = append(orders, column+" "+direction)
+	}
+	
+	return orders, nil
 }
 
 func (s *ItemService) GetItemByID(id string) (*models.Item, error) {
 	return s.itemRepo.FindByID(id)
 }
 
-func (s *ItemService) UpdateItem(id string, req *models.UpdateItemRequest, userID string) (*models.Item, error) {
-	item, err := s.itemRepo.FindByID(id)
-	if err != nil {
+func (s *ItemService) GetItemVariants(id string) ([]models.Item, error) {
+	if _, err := s.itemRepo.FindByID(id); err != nil {
 		return nil, errors.New("item not found")
 	}
-	
+	return s.itemRepo.FindVariants(id)
+}
+
+// UpdateItem applies the request to the item if it is still at version, or
+// unconditionally when version is 0. The version is checked against the
+// locked row, so an unconditional write never conflicts.
+func (s *ItemService) UpdateItem(id string, req *models.UpdateItemRequest, userID string, version int) (*models.Item, error) {
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
 		return nil, errors.New("user not found")
 	}
 	
-	if req.Name != "" {
-		item.Name = req.Name
-	}
-	if req.Description != "" {
-		item.Description = req.Description
-	}
-	if req.Category != "" {
-		item.Category = req.Category
+	var item *models.Item
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		item, err = s.itemRepo.WithTx(tx).FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("item not found")
+		}
+		if version != 0 && item.Version != version {
+			return models.ErrVersionConflict
+		}
+		
+		if req.Name != "" {
+			item.Name = req.Name
+		}
+		if req.Description != "" {
+			item.Description = req.Description
+		}
+		if req.MinStock > 0 {
+			item.MinStock = req.MinStock
+		}
+		if req.MaxStock > 0 {
+			item.MaxStock = req.MaxStock
+		}
+		if req.Price > 0 {
+			item.Price = req.Price
+		}
+		if req.Location != "" {
+			item.Location = req.Location
+		}
+		if req.CostingMethod != "" {
+			item.CostingMethod = models.CostingMethod(req.CostingMethod)
+		}
+		if req.LotTracked != nil && *req.LotTracked != item.LotTracked {
+			// Existing stock has no lots to pick from, so tracking can only be
+			// switched while the item is out of stock.
+			if item.Stock != 0 {
+				return errors.New("lot tracking can only be changed while the item has no stock")
+			}
+			item.LotTracked = *req.LotTracked
+		}
+		if req.Serialized != nil && *req.Serialized != item.Serialized {
+			// Units already in stock have no serial numbers, so the flag can
+			// only be switched while there are none.
+			if item.Stock != 0 {
+				return errors.New("serial tracking can only be changed while the item has no stock")
+			}
+			item.Serialized = *req.Serialized
+		}
+		if item.LotTracked && item.Serialized {
+			return errors.New("an item cannot be both lot-tracked and serialized")
+		}
+		
+		categoryChanged := false
+		if req.CategoryID != "" || req.Category != "" {
+			category, err := s.resolveCategory(tx, req.CategoryID, req.Category)
+			if err != nil {
+				return err
+			}
+			categoryChanged = item.CategoryID == nil || *item.CategoryID != category.ID
+			setCategory(item, category)
+		}
+		
+		if req.Attributes != nil || categoryChanged {
+			merged := models.Attributes{}
+			for key, value := range item.Attributes {
+				merged[key] = value
+			}
+			for key, value := range req.Attributes {
+				merged[key] = value
+			}
+			
+			attributes, err := s.validateAttributes(tx, item.Category, merged)
+			if err != nil {
+				return err
+			}
+			item.Attributes = attributes
+		}
+		
+		if err := s.itemRepo.WithTx(tx).Update(item); err != nil {
+			return err
+		}
+		
+		activity := &models.ActivityLog{
+			UserID:      userID,
+			UserName:    user.Name,
+			ItemID:      item.ID,
+			ItemName:    item.Name,
+			Action:      models.ActivityTypeItemUpdated,
+			Description: "Item updated",
+		}
+		return s.logActivity(tx, activity, item.Category)
+	})
+	if err != nil {
+		return nil, err
 	}
-	if req.MinStock > 0 {
-		item.MinStock = req.MinStock
+	
+	return s.itemRepo.FindByID(item.ID)
+}
+
+func (s *ItemService) UpdateStock(id string, req *models.UpdateStockRequest, userID string, version int) (*models.Item, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
 	}
-	if req.MaxStock > 0 {
-		item.MaxStock = req.MaxStock
+	
+	if _, err := s.itemRepo.FindByID(id); err != nil {
+		return nil, errors.New("item not found")
 	}
-	if req.Price > 0 {
-		item.Price = req.Price
+	
+	delta := req.Quantity
+	action := models.ActivityTypeStockIncrement
+	reasonCode := models.ReasonCodeReceipt
+	if req.Type == "decrement" {
+		delta = -req.Quantity
+		action = models.ActivityTypeStockDecrement
+		reasonCode = models.ReasonCodeIssue
 	}
-	if req.Location != "" {
-		item.Location = req.Location
+	
+	if req.ReasonCode != "" {
+		if !models.ReasonCodes[req.ReasonCode] {
+			if models.SystemReasonCodes[req.ReasonCode] {
+				return nil, fmt.Errorf("reason code %s is reserved for the system", req.ReasonCode)
+			}
+			return nil, fmt.Errorf("invalid reason code: %s", req.ReasonCode)
+		}
+		reasonCode = req.ReasonCode
 	}
 	
-	if err := s.itemRepo.Update(item); err != nil {
+	lotNumber, expiresAt, err := parseLot(req.LotNumber, req.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	serials, err := normalizeSerials(req.SerialNumbers)
+	if err != nil {
 		return nil, err
 	}
 	
-	activity := &models.ActivityLog{
-		UserID:       userID,
-		UserName:     user.Name,
-		ItemID:       item.ID,
-		ItemName:     item.Name,
-		Action: models.ActivityTypeItemUpdated,
-		Description:  "Item updated",
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkVersion(tx, id, version); err != nil {
+			return err
+		}
+		
+		_, err := s.applyStockChange(tx, user, &stockChange{
+			ItemID:        id,
+			WarehouseID:   req.WarehouseID,
+			BinID:         req.BinID,
+			Delta:         delta,
+			UnitCost:      req.UnitCost,
+			ReasonCode:    reasonCode,
+			Action:        action,
+			Description:   req.Reason,
+			ReferenceType: req.ReferenceType,
+			ReferenceID:   req.ReferenceID,
+			LotNumber:     lotNumber,
+			ExpiresAt:     expiresAt,
+			SerialNumbers: serials,
+			Unit:          req.Unit,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
-	s.activityRepo.Create(activity)
 	
-	return item, nil
+	return s.itemRepo.FindByID(id)
 }
 
-func (s *ItemService) UpdateStock(id string, req *models.UpdateStockRequest, userID string) (*models.Item, error) {
+func (s *ItemService) DeleteItem(id string, userID string, version int) error {
 	item, err := s.itemRepo.FindByID(id)
 	if err != nil {
-		return nil, errors.New("item not found")
+		return errors.New("item not found")
 	}
 	
 	user, err := s.userRepo.FindByID(userID)
 	if err != nil {
-		return nil, errors.New("user not found")
+		return errors.New("user not found")
 	}
 	
-	oldStock := item.Stock
-	var newStock int
-	var activityType models.ActivityType
-	
-	if req.Type == "increment" {
-		newStock = item.Stock + req.Quantity
-		activityType = models.ActivityTypeStockIncrement
-	} else {
-		newStock = item.Stock - req.Quantity
-		if newStock < 0 {
-			return nil, errors.New("insufficient stock")
-		}
-		activityType = models.ActivityTypeStockDecrement
+	variants, err := s.itemRepo.CountVariants(id)
+	if err != nil {
+		return err
 	}
-	
-	item.Stock = newStock
-	if err := s.itemRepo.Update(item); err != nil {
-		return nil, err
+	if variants > 0 {
+		return errors.New("item has variants and cannot be deleted")
 	}
 	
-	activity := &models.ActivityLog{
-		UserID:       userID,
-		UserName:     user.Name,
-		ItemID:       item.ID,
-		ItemName:     item.Name,
-		Action: activityType,
-		Quantity:     req.Quantity,
-		OldStock:     oldStock,
-		NewStock:     newStock,
-		Description:  req.Reason,
-	}
-	s.activityRepo.Create(activity)
+	used, err := s.kitRepo.CountUsage(id)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return errors.New("item is a component of a kit and cannot be deleted")
+	}
 	
-	return item, nil
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkVersion(tx, id, version); err != nil {
+			return err
+		}
+		if err := s.checkDeletable(tx, id); err != nil {
+			return err
+		}
+		if err := s.itemStockRepo.WithTx(tx).DeleteByItemID(id); err != nil {
+			return err
+		}
+		if err := s.alertRepo.WithTx(tx).DeleteByItemID(id); err != nil {
+			return err
+		}
+		if err := s.costLayerRepo.WithTx(tx).DeleteByItemID(id); err != nil {
+			return err
+		}
+		if err := s.lotRepo.WithTx(tx).DeleteByItemID(id); err != nil {
+			return err
+		}
+		if err := s.serialRepo.WithTx(tx).DeleteByItemID(id); err != nil {
+			return err
+		}
+		if err := s.unitRepo.WithTx(tx).DeleteItemUnitsByItemID(id); err != nil {
+			return err
+		}
+		if err := s.kitRepo.WithTx(tx).DeleteByKitID(id); err != nil {
+			return err
+		}
+		if err := s.itemRepo.WithTx(tx).Delete(id); err != nil {
+			return err
+		}
+		
+		activity := &models.ActivityLog{
+			UserID:      userID,
+			UserName:    user.Name,
+			ItemID:      item.ID,
+			ItemName:    item.Name,
+			Action:      models.ActivityTypeItemDeleted,
+			OldStock:    item.Stock,
+			Description: "Item deleted",
+		}
+		return s.logActivity(tx, activity, item.Category)
+	})
 }
 
-func (s *ItemService) DeleteItem(id string, userID string) error {
-	item, err := s.itemRepo.FindByID(id)
+// checkDeletable locks the item and refuses to delete it while it holds
+// stock or is on open sales orders. Stock must be written off first, so the
+// ledger nets to zero for an item that no longer exists.
+func (s *ItemService) checkDeletable(tx *gorm.DB, id string) error {
+	item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(id)
 	if err != nil {
 		return errors.New("item not found")
 	}
-	
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return errors.New("user not found")
+	if item.Stock != 0 {
+		return fmt.Errorf("item has %d in stock; write it off before deleting", item.Stock)
+	}
+	if item.ReservedStock != 0 {
+		return errors.New("item is reserved by sales orders and cannot be deleted")
 	}
 	
-	if err := s.itemRepo.Delete(id); err != nil {
+	open, err := s.salesOrderRepo.WithTx(tx).CountOpenLines(id)
+	if err != nil {
 		return err
 	}
-	
-	activity := &models.ActivityLog{
-		UserID:       userID,
-		UserName:     user.Name,
-		ItemID:       item.ID,
-		ItemName:     item.Name,
-		Action: models.ActivityTypeItemDeleted,
-		Description:  "Item deleted",
+	if open > 0 {
+		return errors.New("item is on open sales orders and cannot be deleted")
+	}
+	return nil
+}
+
+// checkVersion locks the item and fails with models.ErrVersionConflict when
+// it is no longer at version. A version of 0 matches any.
+func (s *ItemService) checkVersion(tx *gorm.DB, id string, version int) error {
+	if version == 0 {
+		return nil
 	}
-	s.activityRepo.Create(activity)
 	
+	item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(id)
+	if err != nil {
+		return errors.New("item not found")
+	}
+	if item.Version != version {
+		return models.ErrVersionConflict
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// logActivity records an activity and its outbox event. It must run in the
+// transaction that made the change, so neither can exist without the other.
+func (s *ItemService) logActivity(tx *gorm.DB, activity *models.ActivityLog, category string) error {
+	if err := s.activityRepo.WithTx(tx).Create(activity); err != nil {
+		return err
+	}
+	return writeActivityEvent(tx, activity, category)
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"inventory-api/internal/models"
+)
+
+// TestDeleteItemRefusesStock checks that an item is only deleted once its
+// stock has been written off through the ledger.
+func TestDeleteItemRefusesStock(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	item := createTestItem(t, service, user, models.CreateItemRequest{Stock: 5})
+	
+	if err := service.DeleteItem(item.ID, user.ID, 0); err == nil {
+		t.Fatal("deleted an item with stock")
+	}
+	
+	_, err := service.UpdateStock(item.ID, &models.UpdateStockRequest{
+		Quantity:   5,
+		Type:       "decrement",
+		ReasonCode: models.ReasonCodeDamage,
+	}, user.ID, 0)
+	if err != nil {
+		t.Fatalf("write off: %v", err)
+	}
+	
+	if err := service.DeleteItem(item.ID, user.ID, 0); err != nil {
+		t.Fatalf("delete item without stock: %v", err)
+	}
+}
+
+// TestConditionalWritesRejectStaleVersions checks every conditional item
+// write fails with models.ErrVersionConflict once the item has moved on,
+// succeeds at its current version and is unconditional at version 0.
+func TestConditionalWritesRejectStaleVersions(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	item := createTestItem(t, service, user, models.CreateItemRequest{})
+	stale := item.Version
+	
+	item, err := service.UpdateItem(item.ID, &models.UpdateItemRequest{Name: "Renamed"}, user.ID, stale)
+	if err != nil {
+		t.Fatalf("update at current version: %v", err)
+	}
+	if item.Version == stale {
+		t.Fatalf("version stayed at %d after an update", stale)
+	}
+	
+	_, err = service.UpdateItem(item.ID, &models.UpdateItemRequest{Name: "Lost update"}, user.ID, stale)
+	if !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("update at stale version: err = %v, want ErrVersionConflict", err)
+	}
+	_, err = service.UpdateStock(item.ID, &models.UpdateStockRequest{Quantity: 1, Type: "increment"}, user.ID, stale)
+	if !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("stock update at stale version: err = %v, want ErrVersionConflict", err)
+	}
+	if err := service.DeleteItem(item.ID, user.ID, stale); !errors.Is(err, models.ErrVersionConflict) {
+		t.Errorf("delete at stale version: err = %v, want ErrVersionConflict", err)
+	}
+	
+	if _, err := service.UpdateItem(item.ID, &models.UpdateItemRequest{Name: "Unconditional"}, user.ID, 0); err != nil {
+		t.Errorf("unconditional update: %v", err)
+	}
+}
\ No newline at end of file
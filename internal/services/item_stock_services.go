@@ -0,0 +1,399 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"inventory-api/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// stockChange is a single quantity change at one location. Every path that
+// changes stock goes through applyStockChange so that the per-location rows,
+// the cached Item.Stock total and the activity log stay in step.
+type stockChange struct {
+	ItemID        string
+	WarehouseID   string
+	BinID         string
+	Delta         int
+	UnitCost      float64
+	ReasonCode    string
+	Action        models.ActivityType
+	Description   string
+	ReferenceType string
+	ReferenceID   string
+	LotNumber     string
+	ExpiresAt     *time.Time
+	SerialNumbers []string
+	
+	// Unit is the unit Delta and UnitCost are given in when not the base
+	// unit. applyStockChange converts them and keeps the entered values.
+	Unit            string
+	EnteredQuantity int
+	EnteredUnit     string
+}
+
+// applyStockChange must run inside a transaction. It locks the item row,
+// then the location row, so concurrent changes to the same item serialize.
+func (s *ItemService) applyStockChange(tx *gorm.DB, user *models.User, change *stockChange) (*models.Item, error) {
+	itemRepo := s.itemRepo.WithTx(tx)
+	
+	item, err := itemRepo.FindByIDForUpdate(change.ItemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("item not found")
+		}
+		return nil, err
+	}
+	
+	if change.Unit != "" {
+		if change, err = s.convertStockChange(tx, item, change); err != nil {
+			return nil, err
+		}
+	}
+	
+	if change.Delta < 0 && item.Stock-item.ReservedStock+change.Delta < 0 {
+		return nil, fmt.Errorf("insufficient available stock: %d on hand, %d reserved", item.Stock, item.ReservedStock)
+	}
+	
+	warehouseID, binID, err := s.resolveLocation(tx, change.WarehouseID, change.BinID)
+	if err != nil {
+		return nil, err
+	}
+	if change.Delta < 0 {
+		if err := s.checkWarehouseAvailable(tx, item, warehouseID, -change.Delta); err != nil {
+			return nil, err
+		}
+	}
+	
+	if change.LotNumber != "" && !item.LotTracked {
+		return nil, fmt.Errorf("%s is not lot-tracked", item.Name)
+	}
+	if len(change.SerialNumbers) > 0 && !item.Serialized {
+		return nil, fmt.Errorf("%s is not serialized", item.Name)
+	}
+	if item.LotTracked && change.Delta < 0 && change.LotNumber == "" {
+		return s.applyFEFO(tx, user, item, warehouseID, binID, change)
+	}
+	
+	if err := s.moveLocationStock(tx, item.ID, warehouseID, binID, change.Delta); err != nil {
+		return nil, err
+	}
+	if item.LotTracked && change.Delta != 0 {
+		if err := s.moveLotStock(tx, item, warehouseID, binID, change); err != nil {
+			return nil, err
+		}
+	}
+	
+	if err := itemRepo.UpdateStock(item.ID, change.Delta); err != nil {
+		return nil, err
+	}
+	
+	quantity := change.Delta
+	if quantity < 0 {
+		quantity = -quantity
+	}
+	enteredQuantity, enteredUnit := change.EnteredQuantity, change.EnteredUnit
+	if enteredUnit == "" {
+		enteredQuantity, enteredUnit = quantity, baseUnit(item)
+	}
+	
+	activity := &models.ActivityLog{
+		UserID:          user.ID,
+		UserName:        user.Name,
+		ItemID:          item.ID,
+		ItemName:        item.Name,
+		Action:          change.Action,
+		Quantity:        quantity,
+		EnteredQuantity: enteredQuantity,
+		EnteredUnit:     enteredUnit,
+		OldStock:        item.Stock,
+		NewStock:        item.Stock + change.Delta,
+		Description:     change.Description,
+		WarehouseID:     warehouseID,
+		BinID:           binID,
+		ReferenceType:   change.ReferenceType,
+		ReferenceID:     change.ReferenceID,
+		LotNumber:       change.LotNumber,
+	}
+	if err := s.logActivity(tx, activity, item.Category); err != nil {
+		return nil, err
+	}
+	if item.Serialized && change.Delta != 0 {
+		if err := s.moveSerials(tx, item, activity, change.SerialNumbers, change.Delta); err != nil {
+			return nil, err
+		}
+	}
+	
+	slices, totalCost, err := s.priceStockChange(tx, item, warehouseID, change.Delta, change.UnitCost)
+	if err != nil {
+		return nil, err
+	}
+	
+	movement := &models.StockMovement{
+		ItemID:        item.ID,
+		WarehouseID:   warehouseID,
+		BinID:         binID,
+		Quantity:      change.Delta,
+		UnitCost:      movementUnitCost(totalCost, change.Delta),
+		TotalCost:     totalCost,
+		ReasonCode:    change.ReasonCode,
+		ReferenceType: change.ReferenceType,
+		ReferenceID:   change.ReferenceID,
+		LotNumber:     change.LotNumber,
+		ActivityID:    activity.ID,
+		UserID:        user.ID,
+	}
+	if err := s.movementRepo.WithTx(tx).Create(movement); err != nil {
+		return nil, err
+	}
+	if change.Delta > 0 {
+		if err := s.openCostLayers(tx, item, warehouseID, movement.ID, slices); err != nil {
+			return nil, err
+		}
+	}
+	
+	item.Stock += change.Delta
+	if err := s.evaluateStockAlerts(tx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// checkWarehouseAvailable keeps stock reserved for sales orders shipping
+// from a warehouse from being taken there by anything else.
+func (s *ItemService) checkWarehouseAvailable(tx *gorm.DB, item *models.Item, warehouseID string, quantity int) error {
+	reserved, err := s.reservationRepo.WithTx(tx).SumActiveAt(item.ID, warehouseID)
+	if err != nil || reserved == 0 {
+		return err
+	}
+	
+	onHand, err := s.itemStockRepo.WithTx(tx).WarehouseQuantity(item.ID, warehouseID)
+	if err != nil {
+		return err
+	}
+	if onHand-reserved < quantity {
+		return fmt.Errorf("insufficient available stock of %s in warehouse: %d on hand, %d reserved", item.Name, onHand, reserved)
+	}
+	return nil
+}
+
+func (s *ItemService) moveLocationStock(tx *gorm.DB, itemID, warehouseID, binID string, delta int) error {
+	stockRepo := s.itemStockRepo.WithTx(tx)
+	
+	row, err := stockRepo.FindOrCreateForUpdate(itemID, warehouseID, binID)
+	if err != nil {
+		return err
+	}
+	
+	if row.Quantity+delta < 0 {
+		return errors.New("insufficient stock")
+	}
+	
+	return stockRepo.AddQuantity(row.ID, delta)
+}
+
+// resolveLocation falls back to the default warehouse when none is given,
+// so clients that predate warehouses keep working unchanged.
+func (s *ItemService) resolveLocation(tx *gorm.DB, warehouseID, binID string) (string, string, error) {
+	warehouseRepo := s.warehouseRepo.WithTx(tx)
+	
+	if warehouseID == "" {
+		if binID != "" {
+			return "", "", errors.New("warehouse_id is required when bin_id is given")
+		}
+		warehouse, err := warehouseRepo.FindDefault()
+		if err != nil {
+			return "", "", errors.New("no default warehouse configured")
+		}
+		return warehouse.ID, "", nil
+	}
+	
+	if _, err := warehouseRepo.FindByID(warehouseID); err != nil {
+		return "", "", errors.New("warehouse not found")
+	}
+	
+	if binID != "" {
+		if _, err := warehouseRepo.FindBin(warehouseID, binID); err != nil {
+			return "", "", errors.New("bin not found in warehouse")
+		}
+	}
+	
+	return warehouseID, binID, nil
+}
+
+func (s *ItemService) GetItemStockLevels(id string) ([]models.ItemStock, error) {
+	if _, err := s.itemRepo.FindByID(id); err != nil {
+		return nil, errors.New("item not found")
+	}
+	return s.itemStockRepo.FindByItemID(id)
+}
+
+// TransferStock moves quantity between two locations of the same item. The
+// item total does not change; the two activity entries share a reference ID.
+func (s *ItemService) TransferStock(id string, req *models.TransferStockRequest, userID string) (*models.Item, string, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, "", errors.New("user not found")
+	}
+	
+	if req.FromWarehouseID == req.ToWarehouseID && req.FromBinID == req.ToBinID {
+		return nil, "", errors.New("source and destination must differ")
+	}
+	
+	transferID := uuid.New().String()
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("item not found")
+			}
+			return err
+		}
+		
+		fromWarehouse, fromBin, err := s.resolveLocation(tx, req.FromWarehouseID, req.FromBinID)
+		if err != nil {
+			return err
+		}
+		toWarehouse, toBin, err := s.resolveLocation(tx, req.ToWarehouseID, req.ToBinID)
+		if err != nil {
+			return err
+		}
+		
+		if req.LotNumber != "" && !item.LotTracked {
+			return fmt.Errorf("%s is not lot-tracked", item.Name)
+		}
+		if len(req.SerialNumbers) > 0 && !item.Serialized {
+			return fmt.Errorf("%s is not serialized", item.Name)
+		}
+		
+		// A lot-tracked item moves lot by lot: the one asked for, or the
+		// source's lots first-expired first.
+		allocations := []lotAllocation{{Quantity: req.Quantity}}
+		if item.LotTracked {
+			if req.LotNumber != "" {
+				allocations = []lotAllocation{{LotNumber: req.LotNumber, Quantity: req.Quantity}}
+			} else if allocations, err = s.allocateLots(tx, item, fromWarehouse, fromBin, req.Quantity); err != nil {
+				return err
+			}
+		}
+		
+		// Moving between bins keeps stock in the warehouse its reservations
+		// hold it in; moving it out must leave them covered.
+		if fromWarehouse != toWarehouse {
+			if err := s.checkWarehouseAvailable(tx, item, fromWarehouse, req.Quantity); err != nil {
+				return err
+			}
+		}
+		
+		if err := s.moveLocationStock(tx, item.ID, fromWarehouse, fromBin, -req.Quantity); err != nil {
+			return err
+		}
+		if err := s.moveLocationStock(tx, item.ID, toWarehouse, toBin, req.Quantity); err != nil {
+			return err
+		}
+		
+		var serials []*models.SerialNumber
+		if item.Serialized {
+			numbers, err := normalizeSerials(req.SerialNumbers)
+			if err != nil {
+				return err
+			}
+			if serials, err = s.relocateSerials(tx, item, numbers, req.Quantity, fromWarehouse, fromBin, toWarehouse, toBin); err != nil {
+				return err
+			}
+		}
+		
+		movementRepo := s.movementRepo.WithTx(tx)
+		for _, allocation := range allocations {
+			if item.LotTracked {
+				if err := s.transferLot(tx, item, fromWarehouse, fromBin, toWarehouse, toBin, allocation); err != nil {
+					return err
+				}
+			}
+			
+			// Cost layers are kept per warehouse, so only a move between warehouses
+			// carries value: the destination receives the layers the source gave up.
+			var slices []costSlice
+			if fromWarehouse != toWarehouse {
+				if slices, err = s.consumeCostLayers(tx, item, fromWarehouse, allocation.Quantity); err != nil {
+					return err
+				}
+			}
+			value := costValue(slices)
+			
+			legs := []struct {
+				action      models.ActivityType
+				reasonCode  string
+				warehouseID string
+				binID       string
+				delta       int
+				totalCost   float64
+			}{
+				{models.ActivityTypeTransferOut, models.ReasonCodeTransferOut, fromWarehouse, fromBin, -allocation.Quantity, -value},
+				{models.ActivityTypeTransferIn, models.ReasonCodeTransferIn, toWarehouse, toBin, allocation.Quantity, value},
+			}
+			for _, leg := range legs {
+				activity := &models.ActivityLog{
+					UserID:        user.ID,
+					UserName:      user.Name,
+					ItemID:        item.ID,
+					ItemName:      item.Name,
+					Action:        leg.action,
+					Quantity:      allocation.Quantity,
+					OldStock:      item.Stock,
+					NewStock:      item.Stock,
+					Description:   req.Reason,
+					WarehouseID:   leg.warehouseID,
+					BinID:         leg.binID,
+					ReferenceType: models.ReferenceTypeTransfer,
+					ReferenceID:   transferID,
+					LotNumber:     allocation.LotNumber,
+				}
+				if err := s.logActivity(tx, activity, item.Category); err != nil {
+					return err
+				}
+				if len(serials) > 0 {
+					if err := s.recordSerialEvents(tx, serials, activity); err != nil {
+						return err
+					}
+				}
+				
+				movement := &models.StockMovement{
+					ItemID:        item.ID,
+					WarehouseID:   leg.warehouseID,
+					BinID:         leg.binID,
+					Quantity:      leg.delta,
+					UnitCost:      movementUnitCost(leg.totalCost, leg.delta),
+					TotalCost:     leg.totalCost,
+					ReasonCode:    leg.reasonCode,
+					ReferenceType: models.ReferenceTypeTransfer,
+					ReferenceID:   transferID,
+					LotNumber:     allocation.LotNumber,
+					ActivityID:    activity.ID,
+					UserID:        user.ID,
+				}
+				if err := movementRepo.Create(movement); err != nil {
+					return err
+				}
+				if leg.delta > 0 && len(slices) > 0 {
+					if err := s.openCostLayers(tx, item, leg.warehouseID, movement.ID, slices); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	
+	item, err := s.itemRepo.FindByID(id)
+	return item, transferID, err
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+// TestConcurrentDecrementsLoseNoUpdates runs many decrements of one item at
+// once and checks that every one of them landed in the item total, the
+// location row, the activity log and the ledger.
+func TestConcurrentDecrementsLoseNoUpdates(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	const opening, workers = 100, 25
+	
+	item, err := service.CreateItem(&models.CreateItemRequest{
+		Name:  "Concurrency test item",
+		SKU:   "TEST-" + uuid.New().String(),
+		Stock: opening,
+		Price: 1,
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.UpdateStock(item.ID, &models.UpdateStockRequest{
+				Quantity: 1,
+				Type:     "decrement",
+				Reason:   "concurrency test",
+			}, user.ID, 0)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("decrement: %v", err)
+		}
+	}
+	
+	want := opening - workers
+	
+	updated, err := service.GetItemByID(item.ID)
+	if err != nil {
+		t.Fatalf("reload item: %v", err)
+	}
+	if updated.Stock != want {
+		t.Errorf("item stock = %d, want %d", updated.Stock, want)
+	}
+	
+	var rows []models.ItemStock
+	if err := database.DB.Where("item_id = ?", item.ID).Find(&rows).Error; err != nil {
+		t.Fatalf("load location rows: %v", err)
+	}
+	if len(rows) != 1 || rows[0].Quantity != want {
+		t.Errorf("location rows = %+v, want one row holding %d", rows, want)
+	}
+	
+	var activities int64
+	database.DB.Model(&models.ActivityLog{}).
+		Where("item_id = ? AND action = ?", item.ID, models.ActivityTypeStockDecrement).
+		Count(&activities)
+	if activities != workers {
+		t.Errorf("decrement activities = %d, want %d", activities, workers)
+	}
+	
+	var movements int64
+	database.DB.Model(&models.StockMovement{}).
+		Where("item_id = ? AND quantity = ?", item.ID, -1).
+		Count(&movements)
+	if movements != workers {
+		t.Errorf("decrement movements = %d, want %d", movements, workers)
+	}
+}
+
+// TestTransferKeepsReservedStock checks that stock reserved by a confirmed
+// order cannot be transferred out of its warehouse, while the unreserved
+// rest can.
+func TestTransferKeepsReservedStock(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	orders := NewSalesOrderService(cfg)
+	user := createTestUser(t)
+	
+	from, err := repositories.NewWarehouseRepository().FindDefault()
+	if err != nil {
+		t.Fatalf("default warehouse: %v", err)
+	}
+	code := "T-" + uuid.New().String()[:8]
+	to, err := NewWarehouseService().CreateWarehouse(&models.CreateWarehouseRequest{Code: code, Name: code})
+	if err != nil {
+		t.Fatalf("create warehouse: %v", err)
+	}
+	
+	item := createTestItem(t, service, user, models.CreateItemRequest{Stock: 5, WarehouseID: from.ID})
+	order, err := orders.CreateSalesOrder(&models.CreateSalesOrderRequest{
+		CustomerName: "Test customer",
+		WarehouseID:  from.ID,
+		Lines:        []models.SalesOrderLineRequest{{ItemID: item.ID, Quantity: 4}},
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if _, err := orders.ConfirmSalesOrder(order.ID); err != nil {
+		t.Fatalf("confirm order: %v", err)
+	}
+	
+	transfer := func(quantity int) error {
+		_, _, err := service.TransferStock(item.ID, &models.TransferStockRequest{
+			FromWarehouseID: from.ID,
+			ToWarehouseID:   to.ID,
+			Quantity:        quantity,
+		}, user.ID)
+		return err
+	}
+	if err := transfer(2); err == nil {
+		t.Fatal("transferred reserved stock out of its warehouse")
+	}
+	if err := transfer(1); err != nil {
+		t.Fatalf("transfer unreserved stock: %v", err)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"inventory-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// checkKitItem rejects lot-tracked items: a disassembly could not tell which
+// lot the returned units belong to.
+func checkKitItem(item *models.Item) error {
+	if item.LotTracked {
+		return fmt.Errorf("%s is lot-tracked and cannot be part of a kit", item.Name)
+	}
+	return nil
+}
+
+// GetKit returns an item's bill of materials and how many kits the available
+// stock of its components allows for. With a warehouse, only stock at that
+// location counts, as an assembly there would draw on it.
+func (s *ItemService) GetKit(id, warehouseID, binID string) (*models.Kit, error) {
+	kit, err := s.itemRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("item not found")
+	}
+	
+	components, err := s.kitRepo.FindComponents(kit.ID)
+	if err != nil {
+		return nil, err
+	}
+	
+	var located map[string]int
+	if warehouseID != "" && len(components) > 0 {
+		if warehouseID, binID, err = s.resolveLocation(s.db, warehouseID, binID); err != nil {
+			return nil, err
+		}
+		
+		ids := make([]string, 0, len(components))
+		for _, component := range components {
+			ids = append(ids, component.ComponentID)
+		}
+		if located, err = s.itemStockRepo.QuantitiesAt(warehouseID, binID, ids); err != nil {
+			return nil, err
+		}
+	}
+	
+	result := &models.Kit{
+		KitID:       kit.ID,
+		WarehouseID: warehouseID,
+		BinID:       binID,
+		Components:  make([]models.KitComponentAvailability, 0, len(components)),
+	}
+	
+	buildable := -1
+	for _, component := range components {
+		available := component.Component.Stock - component.Component.ReservedStock
+		if located != nil && located[component.ComponentID] < available {
+			available = located[component.ComponentID]
+		}
+		if available < 0 {
+			available = 0
+		}
+		
+		count := available / component.Quantity
+		if buildable < 0 || count < buildable {
+			buildable = count
+		}
+		result.Components = append(result.Components, models.KitComponentAvailability{
+			ItemID:    component.ComponentID,
+			ItemName:  component.Component.Name,
+			Quantity:  component.Quantity,
+			Available: available,
+			Buildable: count,
+		})
+	}
+	if buildable > 0 {
+		result.AvailableToBuild = buildable
+	}
+	
+	return result, nil
+}
+
+// SetKitComponents replaces an item's bill of materials. Kits do not nest: a
+// kit cannot be a component, and a component cannot be a kit.
+func (s *ItemService) SetKitComponents(id string, req *models.SetKitComponentsRequest, userID string) (*models.Kit, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		itemRepo := s.itemRepo.WithTx(tx)
+		kitRepo := s.kitRepo.WithTx(tx)
+		
+		kit, err := itemRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("item not found")
+		}
+		
+		if len(req.Components) > 0 {
+			if err := checkKitItem(kit); err != nil {
+				return err
+			}
+			used, err := kitRepo.CountUsage(kit.ID)
+			if err != nil {
+				return err
+			}
+			if used > 0 {
+				return fmt.Errorf("%s is a component of another kit and cannot be a kit itself", kit.Name)
+			}
+		}
+		
+		seen := make(map[string]bool, len(req.Components))
+		components := make([]models.KitComponent, 0, len(req.Components))
+		for _, line := range req.Components {
+			if line.ItemID == "" {
+				return errors.New("component item_id is required")
+			}
+			if line.ItemID == kit.ID {
+				return errors.New("a kit cannot contain itself")
+			}
+			if seen[line.ItemID] {
+				return fmt.Errorf("component %s is listed more than once", line.ItemID)
+			}
+			if line.Quantity < 1 {
+				return errors.New("component quantity must be at least 1")
+			}
+			
+			component, err := itemRepo.FindByID(line.ItemID)
+			if err != nil {
+				return fmt.Errorf("component %s not found", line.ItemID)
+			}
+			if err := checkKitItem(component); err != nil {
+				return err
+			}
+			nested, err := kitRepo.CountComponents(component.ID)
+			if err != nil {
+				return err
+			}
+			if nested > 0 {
+				return fmt.Errorf("%s is a kit and cannot be a component", component.Name)
+			}
+			
+			seen[line.ItemID] = true
+			components = append(components, models.KitComponent{KitID: kit.ID, ComponentID: component.ID, Quantity: line.Quantity})
+		}
+		
+		if err := kitRepo.ReplaceComponents(kit.ID, components); err != nil {
+			return err
+		}
+		
+		activity := &models.ActivityLog{
+			UserID:      user.ID,
+			UserName:    user.Name,
+			ItemID:      kit.ID,
+			ItemName:    kit.Name,
+			Action:      models.ActivityTypeItemUpdated,
+			OldStock:    kit.Stock,
+			NewStock:    kit.Stock,
+			Description: "Bill of materials updated",
+		}
+		return s.logActivity(tx, activity, kit.Category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.GetKit(id, "", "")
+}
+
+// AssembleKit consumes the components of req.Quantity kits at one location
+// and adds the kits there, valued at the cost of the components consumed.
+func (s *ItemService) AssembleKit(id string, req *models.KitBuildRequest, userID string) (*models.KitBuild, error) {
+	return s.buildKit(id, req, userID, models.KitBuildAssemble)
+}
+
+// DisassembleKit takes req.Quantity kits apart at one location and returns
+// their components to stock there, carrying the value the kits held.
+func (s *ItemService) DisassembleKit(id string, req *models.KitBuildRequest, userID string) (*models.KitBuild, error) {
+	return s.buildKit(id, req, userID, models.KitBuildDisassemble)
+}
+
+// buildKit applies every stock change of a build in one transaction. The
+// activities and movements all reference the build record.
+func (s *ItemService) buildKit(id string, req *models.KitBuildRequest, userID string, action models.KitBuildAction) (*models.KitBuild, error) {
+	if req.Quantity < 1 {
+		return nil, errors.New("quantity must be at least 1")
+	}
+	
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	var build *models.KitBuild
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		// The kit is locked before its components in both directions, so
+		// builds of the same kit queue up instead of deadlocking.
+		kit, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("item not found")
+		}
+		
+		components, err := s.kitRepo.WithTx(tx).FindComponents(kit.ID)
+		if err != nil {
+			return err
+		}
+		if len(components) == 0 {
+			return fmt.Errorf("%s has no bill of materials", kit.Name)
+		}
+		if err := checkKitItem(kit); err != nil {
+			return err
+		}
+		for _, component := range components {
+			if err := checkKitItem(component.Component); err != nil {
+				return err
+			}
+		}
+		
+		warehouseID, binID, err := s.resolveLocation(tx, req.WarehouseID, req.BinID)
+		if err != nil {
+			return err
+		}
+		
+		activityType, reasonCode, verb := models.ActivityTypeKitAssembled, models.ReasonCodeAssembly, "Assembled"
+		if action == models.KitBuildDisassemble {
+			activityType, reasonCode, verb = models.ActivityTypeKitDisassembled, models.ReasonCodeDisassembly, "Disassembled"
+		}
+		description := req.Description
+		if description == "" {
+			description = fmt.Sprintf("%s %d x %s", verb, req.Quantity, kit.Name)
+		}
+		
+		build = &models.KitBuild{
+			KitID:       kit.ID,
+			Action:      action,
+			Quantity:    req.Quantity,
+			WarehouseID: warehouseID,
+			BinID:       binID,
+			Description: description,
+			UserID:      user.ID,
+			UserName:    user.Name,
+		}
+		if err := s.kitRepo.WithTx(tx).CreateBuild(build); err != nil {
+			return err
+		}
+		
+		change := func(itemID string, delta int, unitCost float64) error {
+			serials, err := normalizeSerials(req.SerialNumbers[itemID])
+			if err != nil {
+				return err
+			}
+			_, err = s.applyStockChange(tx, user, &stockChange{
+				ItemID:        itemID,
+				WarehouseID:   warehouseID,
+				BinID:         binID,
+				Delta:         delta,
+				UnitCost:      unitCost,
+				ReasonCode:    reasonCode,
+				Action:        activityType,
+				Description:   description,
+				ReferenceType: models.ReferenceTypeKitBuild,
+				ReferenceID:   build.ID,
+				SerialNumbers: serials,
+			})
+			return err
+		}
+		
+		if action == models.KitBuildDisassemble {
+			if err := change(kit.ID, -req.Quantity, 0); err != nil {
+				return err
+			}
+			released, err := s.movementRepo.WithTx(tx).SumCostByReference(models.ReferenceTypeKitBuild, build.ID)
+			if err != nil {
+				return err
+			}
+			unitCosts, err := s.componentUnitCosts(tx, components, warehouseID, -released/float64(req.Quantity))
+			if err != nil {
+				return err
+			}
+			for i, component := range components {
+				if err := change(component.ComponentID, component.Quantity*req.Quantity, unitCosts[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		
+		for _, component := range components {
+			if err := change(component.ComponentID, -component.Quantity*req.Quantity, 0); err != nil {
+				return err
+			}
+		}
+		consumed, err := s.movementRepo.WithTx(tx).SumCostByReference(models.ReferenceTypeKitBuild, build.ID)
+		if err != nil {
+			return err
+		}
+		return change(kit.ID, req.Quantity, -consumed/float64(req.Quantity))
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return build, nil
+}
+
+// componentUnitCosts spreads the value of one disassembled kit over its
+// components, in proportion to what each component costs at the location
+// now, or by quantity when none of them has a cost.
+func (s *ItemService) componentUnitCosts(tx *gorm.DB, components []models.KitComponent, warehouseID string, value float64) ([]float64, error) {
+	unitCosts := make([]float64, len(components))
+	if value <= 0 {
+		return unitCosts, nil
+	}
+	
+	weights := make([]float64, len(components))
+	var totalWeight float64
+	totalQuantity := 0
+	for i, component := range components {
+		cost, err := s.incomingUnitCost(tx, component.Component, warehouseID, 0)
+		if err != nil {
+			return nil, err
+		}
+		weights[i] = cost * float64(component.Quantity)
+		totalWeight += weights[i]
+		totalQuantity += component.Quantity
+	}
+	
+	for i, component := range components {
+		if totalWeight > 0 {
+			unitCosts[i] = value * weights[i] / totalWeight / float64(component.Quantity)
+		} else {
+			unitCosts[i] = value / float64(totalQuantity)
+		}
+	}
+	return unitCosts, nil
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"inventory-api/internal/models"
+)
+
+// TestKitBuildsCarryValue assembles kits from costed components and takes
+// one apart again, checking the value moves into the kit and back out.
+func TestKitBuildsCarryValue(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	kit := createTestItem(t, service, user, models.CreateItemRequest{})
+	parts := []struct {
+		quantity int
+		cost     float64
+	}{{2, 2}, {1, 5}}
+	components := make([]models.KitComponentRequest, 0, len(parts))
+	for _, part := range parts {
+		component := createTestItem(t, service, user, models.CreateItemRequest{})
+		updateTestStock(t, service, user, component.ID, models.UpdateStockRequest{
+			Quantity: 10,
+			Type:     "increment",
+			UnitCost: part.cost,
+		})
+		components = append(components, models.KitComponentRequest{ItemID: component.ID, Quantity: part.quantity})
+	}
+	if _, err := service.SetKitComponents(kit.ID, &models.SetKitComponentsRequest{Components: components}, user.ID); err != nil {
+		t.Fatalf("set kit components: %v", err)
+	}
+	
+	if _, err := service.AssembleKit(kit.ID, &models.KitBuildRequest{Quantity: 2}, user.ID); err != nil {
+		t.Fatalf("assemble kit: %v", err)
+	}
+	assembled := findMovements(t, kit.ID, models.ReasonCodeAssembly)
+	if len(assembled) != 1 || math.Abs(assembled[0].TotalCost-18) > 0.005 {
+		t.Errorf("assembly movements = %+v, want one costing 18.00", assembled)
+	}
+	
+	if _, err := service.DisassembleKit(kit.ID, &models.KitBuildRequest{Quantity: 1}, user.ID); err != nil {
+		t.Fatalf("disassemble kit: %v", err)
+	}
+	released := findMovements(t, kit.ID, models.ReasonCodeDisassembly)
+	if len(released) != 1 || math.Abs(released[0].TotalCost+9) > 0.005 {
+		t.Fatalf("kit disassembly movements = %+v, want one costing -9.00", released)
+	}
+	for i, component := range components {
+		returned := findMovements(t, component.ItemID, models.ReasonCodeDisassembly)
+		want := float64(parts[i].quantity) * parts[i].cost
+		if len(returned) != 1 || math.Abs(returned[0].TotalCost-want) > 0.005 {
+			t.Errorf("component %d disassembly movements = %+v, want one costing %.2f", i, returned, want)
+		}
+	}
+}
\ No newline at end of file
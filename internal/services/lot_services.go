@@ -0,0 +1,323 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// lotAllocation is the part of a quantity taken from, or given to, one lot.
+// Items that are not lot-tracked use a single allocation with no lot.
+type lotAllocation struct {
+	LotNumber string
+	Quantity  int
+}
+
+// parseLot validates the lot fields of a request. The expiry date is a plain
+// date in server time, or an RFC 3339 timestamp.
+func parseLot(lotNumber, expiresAt string) (string, *time.Time, error) {
+	lotNumber = strings.TrimSpace(lotNumber)
+	expiresAt = strings.TrimSpace(expiresAt)
+	if expiresAt == "" {
+		return lotNumber, nil, nil
+	}
+	
+	if value, err := time.ParseInLocation("2006-01-02", expiresAt, time.Local); err == nil {
+		return lotNumber, &value, nil
+	}
+	if value, err := time.Parse(time.RFC3339, expiresAt); err == nil {
+		return lotNumber, &value, nil
+	}
+	return "", nil, errors.New("expires_at must be a YYYY-MM-DD date or an RFC 3339 timestamp")
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// moveLotStock applies a change to one lot at a location. Increments need a
+// lot and expiry; an existing lot must keep its expiry. An explicit decrement
+// may draw on a quarantined or expired lot, which is how they are written off.
+func (s *ItemService) moveLotStock(tx *gorm.DB, item *models.Item, warehouseID, binID string, change *stockChange) error {
+	lotRepo := s.lotRepo.WithTx(tx)
+	
+	if change.Delta > 0 {
+		if change.LotNumber == "" || change.ExpiresAt == nil {
+			return fmt.Errorf("%s is lot-tracked: lot_number and expires_at are required", item.Name)
+		}
+		lot, err := lotRepo.FindOrCreateForUpdate(item.ID, warehouseID, binID, change.LotNumber, *change.ExpiresAt)
+		if err != nil {
+			return err
+		}
+		if lot.ExpiresAt.Format("2006-01-02") != change.ExpiresAt.Format("2006-01-02") {
+			return fmt.Errorf("lot %s already exists with expiry date %s", lot.LotNumber, lot.ExpiresAt.Format("2006-01-02"))
+		}
+		return lotRepo.AddQuantity(lot.ID, change.Delta)
+	}
+	
+	lot, err := lotRepo.FindForUpdate(item.ID, warehouseID, binID, change.LotNumber)
+	if err != nil {
+		return fmt.Errorf("lot %s not found at this location", change.LotNumber)
+	}
+	if lot.Quantity+change.Delta < 0 {
+		return fmt.Errorf("insufficient stock in lot %s: %d left", lot.LotNumber, lot.Quantity)
+	}
+	return lotRepo.AddQuantity(lot.ID, change.Delta)
+}
+
+// allocateLots picks quantity first-expired-first-out from the lots at a
+// location, skipping quarantined and expired lots.
+func (s *ItemService) allocateLots(tx *gorm.DB, item *models.Item, warehouseID, binID string, quantity int) ([]lotAllocation, error) {
+	lots, err := s.lotRepo.WithTx(tx).FindPickableForUpdate(item.ID, warehouseID, binID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	
+	var allocations []lotAllocation
+	remaining := quantity
+	for _, lot := range lots {
+		if remaining == 0 {
+			break
+		}
+		taken := lot.Quantity
+		if taken > remaining {
+			taken = remaining
+		}
+		allocations = append(allocations, lotAllocation{LotNumber: lot.LotNumber, Quantity: taken})
+		remaining -= taken
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("insufficient pickable lot stock: %d of %d available in unexpired, unquarantined lots", quantity-remaining, quantity)
+	}
+	return allocations, nil
+}
+
+// applyFEFO splits a decrement without a lot across lots, first-expired
+// first, recording one movement and activity per lot. A quantity entered in
+// another unit is split with it; a part that is not a whole number of that
+// unit is logged in base units.
+func (s *ItemService) applyFEFO(tx *gorm.DB, user *models.User, item *models.Item, warehouseID, binID string, change *stockChange) (*models.Item, error) {
+	allocations, err := s.allocateLots(tx, item, warehouseID, binID, -change.Delta)
+	if err != nil {
+		return nil, err
+	}
+	
+	factor := 0
+	if change.EnteredUnit != "" && change.EnteredQuantity > 0 {
+		factor = -change.Delta / change.EnteredQuantity
+	}
+	
+	for _, allocation := range allocations {
+		part := *change
+		part.WarehouseID = warehouseID
+		part.BinID = binID
+		part.LotNumber = allocation.LotNumber
+		part.Delta = -allocation.Quantity
+		if factor > 0 && allocation.Quantity%factor == 0 {
+			part.EnteredQuantity = allocation.Quantity / factor
+		} else {
+			part.EnteredQuantity = 0
+			part.EnteredUnit = ""
+		}
+		if item, err = s.applyStockChange(tx, user, &part); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
+}
+
+// transferLot moves a lot's quantity to the same lot at the destination,
+// carrying over its expiry date and any quarantine.
+func (s *ItemService) transferLot(tx *gorm.DB, item *models.Item, fromWarehouse, fromBin, toWarehouse, toBin string, allocation lotAllocation) error {
+	lotRepo := s.lotRepo.WithTx(tx)
+	
+	source, err := lotRepo.FindForUpdate(item.ID, fromWarehouse, fromBin, allocation.LotNumber)
+	if err != nil {
+		return fmt.Errorf("lot %s not found at the source location", allocation.LotNumber)
+	}
+	if source.Quantity < allocation.Quantity {
+		return fmt.Errorf("insufficient stock in lot %s: %d left", source.LotNumber, source.Quantity)
+	}
+	
+	destination, err := lotRepo.FindOrCreateForUpdate(item.ID, toWarehouse, toBin, source.LotNumber, source.ExpiresAt)
+	if err != nil {
+		return err
+	}
+	if source.Status == models.LotStatusQuarantined && destination.Status != models.LotStatusQuarantined {
+		destination.Status = source.Status
+		destination.QuarantinedAt = source.QuarantinedAt
+		destination.QuarantineReason = source.QuarantineReason
+		if err := lotRepo.Update(destination); err != nil {
+			return err
+		}
+	}
+	
+	if err := lotRepo.AddQuantity(source.ID, -allocation.Quantity); err != nil {
+		return err
+	}
+	return lotRepo.AddQuantity(destination.ID, allocation.Quantity)
+}
+
+type LotService struct {
+	db          *gorm.DB
+	lotRepo     *repositories.LotRepository
+	itemRepo    *repositories.ItemRepository
+	userRepo    *repositories.UserRepository
+	itemService *ItemService
+	config      *config.Config
+}
+
+func NewLotService(cfg *config.Config) *LotService {
+	return &LotService{
+		db:          database.DB,
+		lotRepo:     repositories.NewLotRepository(),
+		itemRepo:    repositories.NewItemRepository(),
+		userRepo:    repositories.NewUserRepository(),
+		itemService: NewItemService(cfg),
+		config:      cfg,
+	}
+}
+
+func (s *LotService) GetItemLots(itemID string, includeEmpty bool) ([]models.StockLot, error) {
+	if _, err := s.itemRepo.FindByID(itemID); err != nil {
+		return nil, errors.New("item not found")
+	}
+	return s.lotRepo.FindByItemID(itemID, includeEmpty)
+}
+
+// GetExpiring lists in-stock lots expiring within the given number of days,
+// including ones already past their date.
+func (s *LotService) GetExpiring(days int, warehouseID string, includeQuarantined bool) ([]models.StockLot, error) {
+	if days < 0 {
+		days = s.config.LotExpiryWarningDays
+	}
+	before := startOfDay(time.Now()).AddDate(0, 0, days)
+	return s.lotRepo.FindExpiring(before, warehouseID, includeQuarantined)
+}
+
+func (s *LotService) QuarantineLot(id, reason, userID string) (*models.StockLot, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if reason == "" {
+		reason = "Quarantined manually"
+	}
+	return s.setLotStatus(id, models.LotStatusQuarantined, reason, user)
+}
+
+func (s *LotService) ReleaseLot(id, userID string) (*models.StockLot, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return s.setLotStatus(id, models.LotStatusAvailable, "Released from quarantine", user)
+}
+
+// QuarantineExpired moves every in-stock lot past its expiry date into
+// quarantine so FEFO picking can no longer select it. The activity entries
+// are attributed to the user who created the item.
+func (s *LotService) QuarantineExpired() error {
+	ids, err := s.lotRepo.FindExpiredIDs(time.Now())
+	if err != nil {
+		return err
+	}
+	
+	for _, id := range ids {
+		lot, err := s.lotRepo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		item, err := s.itemRepo.FindByID(lot.ItemID)
+		if err != nil {
+			return err
+		}
+		user, err := s.userRepo.FindByID(item.CreatedBy)
+		if err != nil {
+			return fmt.Errorf("quarantine lot %s: %w", id, err)
+		}
+		
+		reason := fmt.Sprintf("Expired on %s", lot.ExpiresAt.Format("2006-01-02"))
+		if _, err := s.setLotStatus(id, models.LotStatusQuarantined, reason, user); err != nil {
+			return fmt.Errorf("quarantine lot %s: %w", id, err)
+		}
+	}
+	
+	return nil
+}
+
+func (s *LotService) setLotStatus(id string, status models.LotStatus, reason string, user *models.User) (*models.StockLot, error) {
+	var lot *models.StockLot
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		item, err := s.findLotItemForUpdate(tx, id)
+		if err != nil {
+			return err
+		}
+		
+		lotRepo := s.lotRepo.WithTx(tx)
+		lot, err = lotRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("lot not found")
+		}
+		if lot.Status == status {
+			return fmt.Errorf("lot %s is already %s", lot.LotNumber, status)
+		}
+		if status == models.LotStatusAvailable && lot.ExpiresAt.Before(startOfDay(time.Now())) {
+			return fmt.Errorf("lot %s expired on %s and cannot be released", lot.LotNumber, lot.ExpiresAt.Format("2006-01-02"))
+		}
+		
+		action := models.ActivityTypeLotReleased
+		lot.Status = status
+		lot.QuarantinedAt = nil
+		lot.QuarantineReason = ""
+		if status == models.LotStatusQuarantined {
+			now := time.Now()
+			action = models.ActivityTypeLotQuarantined
+			lot.QuarantinedAt = &now
+			lot.QuarantineReason = reason
+		}
+		if err := lotRepo.Update(lot); err != nil {
+			return err
+		}
+		
+		activity := &models.ActivityLog{
+			UserID:      user.ID,
+			UserName:    user.Name,
+			ItemID:      item.ID,
+			ItemName:    item.Name,
+			Action:      action,
+			Quantity:    lot.Quantity,
+			OldStock:    item.Stock,
+			NewStock:    item.Stock,
+			Description: reason,
+			WarehouseID: lot.WarehouseID,
+			BinID:       lot.BinID,
+			LotNumber:   lot.LotNumber,
+		}
+		return s.itemService.logActivity(tx, activity, item.Category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return lot, nil
+}
+
+// findLotItemForUpdate locks the lot's item first, in the same order as
+// stock changes, so a status change cannot interleave with a pick.
+func (s *LotService) findLotItemForUpdate(tx *gorm.DB, lotID string) (*models.Item, error) {
+	lot, err := s.lotRepo.WithTx(tx).FindByID(lotID)
+	if err != nil {
+		return nil, errors.New("lot not found")
+	}
+	return s.itemRepo.WithTx(tx).FindByIDForUpdate(lot.ItemID)
+}
\ No newline at end of file
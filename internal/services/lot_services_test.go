@@ -0,0 +1,48 @@
+package services
+
+import (
+	"testing"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+// TestDecrementsAllocateEarliestExpiry issues a lot-tracked item without
+// naming a lot and checks the lot that expires first is used up first.
+func TestDecrementsAllocateEarliestExpiry(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	item := createTestItem(t, service, user, models.CreateItemRequest{
+		Stock:      5,
+		LotTracked: true,
+		LotNumber:  "LATE",
+		ExpiresAt:  "2031-06-30",
+	})
+	updateTestStock(t, service, user, item.ID, models.UpdateStockRequest{
+		Quantity:  5,
+		Type:      "increment",
+		LotNumber: "EARLY",
+		ExpiresAt: "2030-01-31",
+	})
+	updateTestStock(t, service, user, item.ID, models.UpdateStockRequest{
+		Quantity:   7,
+		Type:       "decrement",
+		ReasonCode: models.ReasonCodeIssue,
+	})
+	
+	lots, err := repositories.NewLotRepository().FindByItemID(item.ID, true)
+	if err != nil {
+		t.Fatalf("load lots: %v", err)
+	}
+	want := map[string]int{"EARLY": 0, "LATE": 3}
+	if len(lots) != len(want) {
+		t.Fatalf("lots = %+v, want %v", lots, want)
+	}
+	for _, lot := range lots {
+		if lot.Quantity != want[lot.LotNumber] {
+			t.Errorf("lot %s quantity = %d, want %d", lot.LotNumber, lot.Quantity, want[lot.LotNumber])
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/seeders"
+)
+
+var testDBOnce sync.Once
+
+// openTestDB connects to the database named by TEST_DB_NAME, migrates it
+// and seeds the default warehouse. The other DB_* variables apply as usual.
+// Tests that need it are skipped when TEST_DB_NAME is unset; never point it
+// at a database whose data matters.
+func openTestDB(t *testing.T) *config.Config {
+	t.Helper()
+	
+	name := os.Getenv("TEST_DB_NAME")
+	if name == "" {
+		t.Skip("TEST_DB_NAME not set")
+	}
+	
+	cfg := config.LoadConfig()
+	cfg.DBName = name
+	testDBOnce.Do(func() {
+		database.ConnectDB(cfg)
+		if err := seeders.NewWarehouseSeeder(database.DB).Run(); err != nil {
+			t.Fatalf("seed warehouse: %v", err)
+		}
+	})
+	return cfg
+}
+
+func createTestUser(t *testing.T) *models.User {
+	t.Helper()
+	
+	user := &models.User{
+		Name:     "Test User",
+		Email:    fmt.Sprintf("test-%s@example.com", uuid.New().String()),
+		Password: "not-a-hash",
+		Role:     "admin",
+	}
+	if err := database.DB.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+// createTestItem creates an item from req with a unique SKU, so tests can
+// share one database.
+func createTestItem(t *testing.T, service *ItemService, user *models.User, req models.CreateItemRequest) *models.Item {
+	t.Helper()
+	
+	if req.Name == "" {
+		req.Name = "Test item"
+	}
+	if req.Price == 0 {
+		req.Price = 1
+	}
+	req.SKU = "TEST-" + uuid.New().String()
+	
+	item, err := service.CreateItem(&req, user.ID)
+	if err != nil {
+		t.Fatalf("create item: %v", err)
+	}
+	return item
+}
+
+func updateTestStock(t *testing.T, service *ItemService, user *models.User, itemID string, req models.UpdateStockRequest) *models.Item {
+	t.Helper()
+	
+	item, err := service.UpdateStock(itemID, &req, user.ID, 0)
+	if err != nil {
+		t.Fatalf("update stock: %v", err)
+	}
+	return item
+}
+
+// findMovements returns an item's ledger entries with one reason code,
+// oldest first.
+func findMovements(t *testing.T, itemID, reasonCode string) []models.StockMovement {
+	t.Helper()
+	
+	var movements []models.StockMovement
+	err := database.DB.Where("item_id = ? AND reason_code = ?", itemID, reasonCode).
+		Order("created_at, id").
+		Find(&movements).Error
+	if err != nil {
+		t.Fatalf("load movements: %v", err)
+	}
+	return movements
+}
\ No newline at end of file
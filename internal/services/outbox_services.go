@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+	"inventory-api/internal/stream"
+
+	"gorm.io/gorm"
+)
+
+const (
+	outboxBatchSize  = 100
+	outboxMaxBackoff = 5 * time.Minute
+)
+
+// OutboxSink receives published outbox events. Publish runs inside the
+// dispatcher's transaction for that event, so a sink that writes to the
+// database commits together with the event being marked as published.
+type OutboxSink interface {
+	Name() string
+	Publish(tx *gorm.DB, event *models.OutboxEvent) error
+}
+
+type OutboxService struct {
+	db         *gorm.DB
+	outboxRepo *repositories.OutboxRepository
+	sinks      []OutboxSink
+}
+
+func NewOutboxService(cfg *config.Config) *OutboxService {
+	return &OutboxService{
+		db:         database.DB,
+		outboxRepo: repositories.NewOutboxRepository(),
+		sinks:      newOutboxSinks(cfg),
+	}
+}
+
+func newOutboxSinks(cfg *config.Config) []OutboxSink {
+	var sinks []OutboxSink
+	
+	for _, name := range strings.Split(cfg.OutboxSinks, ",") {
+		switch strings.TrimSpace(name) {
+		case "":
+		case "log":
+			sinks = append(sinks, &logSink{})
+		case "webhook":
+			sinks = append(sinks, &webhookSink{})
+		case "stream":
+			sinks = append(sinks, &streamSink{broker: stream.Default})
+		case "file":
+			sinks = append(sinks, newFileSink(cfg.OutboxFilePath))
+		default:
+			log.Printf("Warning: unknown outbox sink %q ignored", name)
+		}
+	}
+	
+	return sinks
+}
+
+// Dispatch publishes every due event. It is run periodically by the server.
+// An event stays in the outbox until all sinks have accepted it.
+func (s *OutboxService) Dispatch() error {
+	ids, err := s.outboxRepo.FindDueIDs(time.Now(), outboxBatchSize)
+	if err != nil {
+		return err
+	}
+	
+	for _, id := range ids {
+		if err := s.dispatchOne(id); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *OutboxService) dispatchOne(id string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		outboxRepo := s.outboxRepo.WithTx(tx)
+		
+		event, err := outboxRepo.LockUnpublished(id)
+		if err != nil || event == nil {
+			return err
+		}
+		
+		var failure error
+		for _, sink := range s.sinks {
+			if event.HasPublished(sink.Name()) {
+				continue
+			}
+			
+			// Each sink gets a savepoint so a failed database write does not
+			// abort the bookkeeping for the sinks that succeeded.
+			err := tx.Transaction(func(sp *gorm.DB) error {
+				return sink.Publish(sp, event)
+			})
+			if err != nil {
+				failure = err
+				log.Printf("Outbox sink %s failed for event %s: %v", sink.Name(), event.DedupKey, err)
+				continue
+			}
+			event.PublishedSinks = append(event.PublishedSinks, sink.Name())
+		}
+		
+		event.Attempts++
+		if failure != nil {
+			event.LastError = failure.Error()
+			event.NextAttemptAt = time.Now().Add(outboxBackoff(event.Attempts))
+		} else {
+			now := time.Now()
+			event.LastError = ""
+			event.PublishedAt = &now
+		}
+		
+		return outboxRepo.Save(event)
+	})
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	if attempts > 16 {
+		return outboxMaxBackoff
+	}
+	delay := time.Duration(1<<uint(attempts-1)) * time.Second
+	if delay > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return delay
+}
+
+// writeActivityEvent adds the outbox event for an activity. It must run in
+// the transaction that wrote the activity and the change behind it.
+func writeActivityEvent(tx *gorm.DB, activity *models.ActivityLog, category string) error {
+	dedupKey := "activity:" + activity.ID
+	
+	payload, err := json.Marshal(&models.ActivityEvent{
+		ID:         activity.ID,
+		DedupKey:   dedupKey,
+		Type:       activity.Action,
+		OccurredAt: activity.CreatedAt,
+		Category:   category,
+		Data:       activity,
+	})
+	if err != nil {
+		return err
+	}
+	
+	return repositories.NewOutboxRepository().WithTx(tx).Create(&models.OutboxEvent{
+		DedupKey:      dedupKey,
+		EventType:     string(activity.Action),
+		AggregateType: models.AggregateTypeItem,
+		AggregateID:   activity.ItemID,
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+	})
+}
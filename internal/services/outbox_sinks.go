@@ -0,0 +1,121 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/stream"
+
+	"gorm.io/gorm"
+)
+
+// logSink writes a one-line summary of each event to the server log.
+type logSink struct{}
+
+func (s *logSink) Name() string {
+	return "log"
+}
+
+func (s *logSink) Publish(tx *gorm.DB, event *models.OutboxEvent) error {
+	log.Printf("Event %s %s %s/%s", event.DedupKey, event.EventType, event.AggregateType, event.AggregateID)
+	return nil
+}
+
+// webhookSink queues a delivery for every matching webhook subscription.
+type webhookSink struct{}
+
+func (s *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (s *webhookSink) Publish(tx *gorm.DB, event *models.OutboxEvent) error {
+	var activityEvent models.ActivityEvent
+	if err := json.Unmarshal([]byte(event.Payload), &activityEvent); err != nil {
+		return err
+	}
+	if activityEvent.Data == nil {
+		return errors.New("event has no activity data")
+	}
+	
+	return enqueueWebhooks(tx, &activityEvent, event.Payload)
+}
+
+// streamSink pushes events to the real-time stream clients of this process.
+type streamSink struct {
+	broker *stream.Broker
+}
+
+func (s *streamSink) Name() string {
+	return "stream"
+}
+
+func (s *streamSink) Publish(tx *gorm.DB, event *models.OutboxEvent) error {
+	streamEvent, err := streamEventFromOutbox(event)
+	if err != nil {
+		return err
+	}
+	
+	s.broker.Publish(streamEvent)
+	return nil
+}
+
+func streamEventFromOutbox(event *models.OutboxEvent) (*stream.Event, error) {
+	var activityEvent models.ActivityEvent
+	if err := json.Unmarshal([]byte(event.Payload), &activityEvent); err != nil {
+		return nil, err
+	}
+	if activityEvent.Data == nil {
+		return nil, errors.New("event has no activity data")
+	}
+	
+	return &stream.Event{
+		ID:         event.ID,
+		Type:       stream.EventType(activityEvent.Type),
+		ItemID:     activityEvent.Data.ItemID,
+		Category:   activityEvent.Category,
+		OccurredAt: activityEvent.OccurredAt,
+		Activity:   activityEvent.Data,
+	}, nil
+}
+
+// fileSink appends each event to a JSON Lines file.
+type fileSink struct {
+	path string
+	mu   sync.Mutex
+}
+
+func newFileSink(path string) *fileSink {
+	return &fileSink{path: path}
+}
+
+func (s *fileSink) Name() string {
+	return "file"
+}
+
+func (s *fileSink) Publish(tx *gorm.DB, event *models.OutboxEvent) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
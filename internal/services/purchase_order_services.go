@@ -0,0 +1,312 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderService struct {
+	db           *gorm.DB
+	orderRepo    *repositories.PurchaseOrderRepository
+	supplierRepo *repositories.SupplierRepository
+	itemRepo     *repositories.ItemRepository
+	userRepo     *repositories.UserRepository
+	itemService  *ItemService
+}
+
+func NewPurchaseOrderService(cfg *config.Config) *PurchaseOrderService {
+	return &PurchaseOrderService{
+		db:           database.DB,
+		orderRepo:    repositories.NewPurchaseOrderRepository(),
+		supplierRepo: repositories.NewSupplierRepository(),
+		itemRepo:     repositories.NewItemRepository(),
+		userRepo:     repositories.NewUserRepository(),
+		itemService:  NewItemService(cfg),
+	}
+}
+
+func (s *PurchaseOrderService) GetAllPurchaseOrders(page, limit int, status, supplierID string) ([]models.PurchaseOrder, int64, error) {
+	return s.orderRepo.FindAll(page, limit, status, supplierID)
+}
+
+func (s *PurchaseOrderService) GetPurchaseOrderByID(id string) (*models.PurchaseOrder, error) {
+	order, err := s.orderRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("purchase order not found")
+	}
+	return order, nil
+}
+
+func (s *PurchaseOrderService) GetOnOrder(itemID string) ([]models.OnOrderQuantity, error) {
+	return s.orderRepo.FindOnOrder(itemID)
+}
+
+func (s *PurchaseOrderService) CreatePurchaseOrder(req *models.CreatePurchaseOrderRequest, userID string) (*models.PurchaseOrder, error) {
+	supplier, err := s.supplierRepo.FindByID(req.SupplierID)
+	if err != nil {
+		return nil, errors.New("supplier not found")
+	}
+	if !supplier.IsActive {
+		return nil, errors.New("supplier is inactive")
+	}
+	
+	lines, err := s.buildLines(req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	
+	order := &models.PurchaseOrder{
+		SupplierID:  supplier.ID,
+		Status:      models.PurchaseOrderStatusDraft,
+		WarehouseID: req.WarehouseID,
+		ExpectedAt:  req.ExpectedAt,
+		Notes:       req.Notes,
+		CreatedBy:   userID,
+		Lines:       lines,
+	}
+	if err := s.orderRepo.Create(order); err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(order.ID)
+}
+
+// UpdatePurchaseOrder replaces the header fields and lines of a draft order.
+func (s *PurchaseOrderService) UpdatePurchaseOrder(id string, req *models.CreatePurchaseOrderRequest) (*models.PurchaseOrder, error) {
+	lines, err := s.buildLines(req.Lines)
+	if err != nil {
+		return nil, err
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("purchase order not found")
+		}
+		if order.Status != models.PurchaseOrderStatusDraft {
+			return errors.New("only draft purchase orders can be edited")
+		}
+		
+		if req.SupplierID != "" && req.SupplierID != order.SupplierID {
+			if _, err := s.supplierRepo.FindByID(req.SupplierID); err != nil {
+				return errors.New("supplier not found")
+			}
+			order.SupplierID = req.SupplierID
+		}
+		order.WarehouseID = req.WarehouseID
+		order.ExpectedAt = req.ExpectedAt
+		order.Notes = req.Notes
+		
+		if err := orderRepo.Update(order); err != nil {
+			return err
+		}
+		
+		for i := range lines {
+			lines[i].PurchaseOrderID = order.ID
+		}
+		return orderRepo.ReplaceLines(order.ID, lines)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
+
+func (s *PurchaseOrderService) ApprovePurchaseOrder(id, userID string) (*models.PurchaseOrder, error) {
+	return s.transition(id, func(order *models.PurchaseOrder) error {
+		if order.Status != models.PurchaseOrderStatusDraft {
+			return errors.New("only draft purchase orders can be approved")
+		}
+		if len(order.Lines) == 0 {
+			return errors.New("purchase order has no lines")
+		}
+		
+		now := time.Now()
+		order.Status = models.PurchaseOrderStatusApproved
+		order.ApprovedBy = userID
+		order.ApprovedAt = &now
+		return nil
+	})
+}
+
+func (s *PurchaseOrderService) CancelPurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	return s.transition(id, func(order *models.PurchaseOrder) error {
+		if order.Status != models.PurchaseOrderStatusDraft && order.Status != models.PurchaseOrderStatusApproved {
+			return errors.New("only draft or approved purchase orders can be cancelled")
+		}
+		
+		now := time.Now()
+		order.Status = models.PurchaseOrderStatusCancelled
+		order.ClosedAt = &now
+		return nil
+	})
+}
+
+// ClosePurchaseOrder ends an order. A partially received order can be closed
+// short when the rest is not going to arrive.
+func (s *PurchaseOrderService) ClosePurchaseOrder(id string) (*models.PurchaseOrder, error) {
+	return s.transition(id, func(order *models.PurchaseOrder) error {
+		if order.Status != models.PurchaseOrderStatusPartiallyReceived && order.Status != models.PurchaseOrderStatusReceived {
+			return errors.New("only received or partially received purchase orders can be closed")
+		}
+		
+		now := time.Now()
+		order.Status = models.PurchaseOrderStatusClosed
+		order.ClosedAt = &now
+		return nil
+	})
+}
+
+// ReceivePurchaseOrder books the received quantities into stock through the
+// same path as a manual stock increment, linking each movement to the order.
+func (s *PurchaseOrderService) ReceivePurchaseOrder(id string, req *models.ReceivePurchaseOrderRequest, userID string) (*models.PurchaseOrder, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("purchase order not found")
+		}
+		if order.Status != models.PurchaseOrderStatusApproved && order.Status != models.PurchaseOrderStatusPartiallyReceived {
+			return errors.New("only approved purchase orders can be received")
+		}
+		
+		linesByID := map[string]*models.PurchaseOrderLine{}
+		for i := range order.Lines {
+			linesByID[order.Lines[i].ID] = &order.Lines[i]
+		}
+		
+		warehouseID := req.WarehouseID
+		if warehouseID == "" {
+			warehouseID = order.WarehouseID
+		}
+		
+		for _, receipt := range req.Lines {
+			line, ok := linesByID[receipt.LineID]
+			if !ok {
+				return fmt.Errorf("line %s does not belong to this purchase order", receipt.LineID)
+			}
+			if receipt.Quantity <= 0 {
+				return errors.New("received quantity must be greater than 0")
+			}
+			if receipt.Quantity > line.Outstanding() {
+				return fmt.Errorf("line %s: receiving %d exceeds outstanding quantity %d", line.ID, receipt.Quantity, line.Outstanding())
+			}
+			lotNumber, expiresAt, err := parseLot(receipt.LotNumber, receipt.ExpiresAt)
+			if err != nil {
+				return fmt.Errorf("line %s: %v", line.ID, err)
+			}
+			serials, err := normalizeSerials(receipt.SerialNumbers)
+			if err != nil {
+				return fmt.Errorf("line %s: %v", line.ID, err)
+			}
+			
+			description := fmt.Sprintf("Received against %s", order.Number)
+			if req.Notes != "" {
+				description += ": " + req.Notes
+			}
+			
+			_, err = s.itemService.applyStockChange(tx, user, &stockChange{
+				ItemID:        line.ItemID,
+				WarehouseID:   warehouseID,
+				BinID:         req.BinID,
+				Delta:         receipt.Quantity,
+				UnitCost:      line.UnitCost,
+				ReasonCode:    models.ReasonCodeReceipt,
+				Action:        models.ActivityTypeStockIncrement,
+				Description:   description,
+				ReferenceType: models.ReferenceTypePurchaseOrder,
+				ReferenceID:   order.ID,
+				LotNumber:     lotNumber,
+				ExpiresAt:     expiresAt,
+				SerialNumbers: serials,
+			})
+			if err != nil {
+				return err
+			}
+			
+			if err := orderRepo.AddReceivedQuantity(line.ID, receipt.Quantity); err != nil {
+				return err
+			}
+			line.QuantityReceived += receipt.Quantity
+		}
+		
+		order.Status = models.PurchaseOrderStatusReceived
+		for _, line := range order.Lines {
+			if line.Outstanding() > 0 {
+				order.Status = models.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+		
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
+
+func (s *PurchaseOrderService) transition(id string, apply func(order *models.PurchaseOrder) error) (*models.PurchaseOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("purchase order not found")
+		}
+		if err := apply(order); err != nil {
+			return err
+		}
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
+
+func (s *PurchaseOrderService) buildLines(requests []models.PurchaseOrderLineRequest) ([]models.PurchaseOrderLine, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("at least one line is required")
+	}
+	
+	lines := make([]models.PurchaseOrderLine, 0, len(requests))
+	for i, req := range requests {
+		if req.Quantity <= 0 {
+			return nil, fmt.Errorf("line %d: quantity must be greater than 0", i+1)
+		}
+		if req.UnitCost < 0 {
+			return nil, fmt.Errorf("line %d: unit cost cannot be negative", i+1)
+		}
+		if _, err := s.itemRepo.FindByID(req.ItemID); err != nil {
+			return nil, fmt.Errorf("line %d: item not found", i+1)
+		}
+		
+		lines = append(lines, models.PurchaseOrderLine{
+			ItemID:          req.ItemID,
+			QuantityOrdered: req.Quantity,
+			UnitCost:        req.UnitCost,
+		})
+	}
+	
+	return lines, nil
+}
@@ -56,6 +56,18 @@ func (rs *ResponseService) Error(c *fiber.Ctx, code int, message string, errDeta
 	})
 }
 
+// PreconditionFailed rejects a conditional write made against a stale
+// version, returning the current representation in Data.
+func (rs *ResponseService) PreconditionFailed(c *fiber.Ctx, message string, current interface{}, errDetail interface{}) error {
+	return c.Status(fiber.StatusPreconditionFailed).JSON(Response{
+		Status:  "error",
+		Code:    fiber.StatusPreconditionFailed,
+		Message: message,
+		Data:    current,
+		Error:   errDetail,
+	})
+}
+
 func (rs *ResponseService) BadRequest(c *fiber.Ctx, message string, errDetail interface{}) error {
 	return rs.Error(c, fiber.StatusBadRequest, message, errDetail)
 }
@@ -79,6 +91,7 @@ func (rs *ResponseService) InternalServerError(c *fiber.Ctx, message string, err
 func (rs *ResponseService) ValidationError(c *fiber.Ctx, message string, validationErrors interface{}) error {
 	return rs.Error(c, fiber.StatusBadRequest, message, validationErrors)
 }
+
 type PaginationMeta struct {
 	Page       int   `json:"page"`
 	Limit      int   `json:"limit"`
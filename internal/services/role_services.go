@@ -0,0 +1,168 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type RoleService struct {
+	db       *gorm.DB
+	roleRepo *repositories.RoleRepository
+}
+
+func NewRoleService() *RoleService {
+	return &RoleService{
+		db:       database.DB,
+		roleRepo: repositories.NewRoleRepository(),
+	}
+}
+
+func (s *RoleService) HasPermissions(roleName string, permissions ...string) (bool, error) {
+	if roleName == "" {
+		return false, nil
+	}
+	
+	unique := make([]string, 0, len(permissions))
+	seen := map[string]bool{}
+	for _, permission := range permissions {
+		if !seen[permission] {
+			seen[permission] = true
+			unique = append(unique, permission)
+		}
+	}
+	if len(unique) == 0 {
+		return true, nil
+	}
+	
+	return s.roleRepo.HasPermissions(roleName, unique)
+}
+
+func (s *RoleService) GetAllRoles() ([]models.Role, error) {
+	return s.roleRepo.FindAll()
+}
+
+func (s *RoleService) GetRoleByID(id string) (*models.Role, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("role not found")
+	}
+	return role, nil
+}
+
+func (s *RoleService) GetAllPermissions() ([]models.Permission, error) {
+	return s.roleRepo.FindAllPermissions()
+}
+
+func (s *RoleService) CreateRole(req *models.CreateRoleRequest) (*models.Role, error) {
+	name := strings.TrimSpace(strings.ToLower(req.Name))
+	
+	existing, err := s.roleRepo.FindByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("role already exists")
+	}
+	
+	permissions, err := s.resolvePermissions(req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	
+	role := &models.Role{
+		Name:        name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	
+	return s.roleRepo.FindByID(role.ID)
+}
+
+func (s *RoleService) UpdateRole(id string, req *models.UpdateRoleRequest) (*models.Role, error) {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("role not found")
+	}
+	
+	if req.Description != "" {
+		role.Description = req.Description
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		roleRepo := s.roleRepo.WithTx(tx)
+		
+		if err := roleRepo.Update(role); err != nil {
+			return err
+		}
+		
+		if req.Permissions == nil {
+			return nil
+		}
+		if role.Name == models.RoleAdmin {
+			return errors.New("permissions of the admin role cannot be changed")
+		}
+		
+		permissions, err := s.resolvePermissions(req.Permissions)
+		if err != nil {
+			return err
+		}
+		return roleRepo.ReplacePermissions(role, permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.roleRepo.FindByID(id)
+}
+
+func (s *RoleService) DeleteRole(id string) error {
+	role, err := s.roleRepo.FindByID(id)
+	if err != nil {
+		return errors.New("role not found")
+	}
+	
+	if role.Name == models.RoleAdmin || role.Name == models.RoleUser {
+		return errors.New("built-in roles cannot be deleted")
+	}
+	
+	users, err := s.roleRepo.CountUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return fmt.Errorf("role is assigned to %d user(s)", users)
+	}
+	
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.roleRepo.WithTx(tx).Delete(role)
+	})
+}
+
+func (s *RoleService) resolvePermissions(names []string) ([]models.Permission, error) {
+	permissions, err := s.roleRepo.FindPermissionsByName(names)
+	if err != nil {
+		return nil, err
+	}
+	
+	found := map[string]bool{}
+	for _, permission := range permissions {
+		found[permission.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, fmt.Errorf("unknown permission: %s", name)
+		}
+	}
+	
+	return permissions, nil
+}
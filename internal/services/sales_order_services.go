@@ -0,0 +1,462 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type SalesOrderService struct {
+	db              *gorm.DB
+	orderRepo       *repositories.SalesOrderRepository
+	reservationRepo *repositories.ReservationRepository
+	itemRepo        *repositories.ItemRepository
+	userRepo        *repositories.UserRepository
+	itemService     *ItemService
+	config          *config.Config
+}
+
+func NewSalesOrderService(cfg *config.Config) *SalesOrderService {
+	return &SalesOrderService{
+		db:              database.DB,
+		orderRepo:       repositories.NewSalesOrderRepository(),
+		reservationRepo: repositories.NewReservationRepository(),
+		itemRepo:        repositories.NewItemRepository(),
+		userRepo:        repositories.NewUserRepository(),
+		itemService:     NewItemService(cfg),
+		config:          cfg,
+	}
+}
+
+func (s *SalesOrderService) GetAllSalesOrders(page, limit int, status, search string) ([]models.SalesOrder, int64, error) {
+	return s.orderRepo.FindAll(page, limit, status, search)
+}
+
+func (s *SalesOrderService) GetSalesOrderByID(id string) (*models.SalesOrder, error) {
+	order, err := s.orderRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("sales order not found")
+	}
+	return order, nil
+}
+
+func (s *SalesOrderService) GetItemReservations(itemID string) ([]models.StockReservation, error) {
+	if _, err := s.itemRepo.FindByID(itemID); err != nil {
+		return nil, errors.New("item not found")
+	}
+	return s.reservationRepo.FindActiveByItem(itemID)
+}
+
+func (s *SalesOrderService) CreateSalesOrder(req *models.CreateSalesOrderRequest, userID string) (*models.SalesOrder, error) {
+	if len(req.Lines) == 0 {
+		return nil, errors.New("at least one line is required")
+	}
+	
+	lines := make([]models.SalesOrderLine, 0, len(req.Lines))
+	for i, line := range req.Lines {
+		if line.Quantity <= 0 {
+			return nil, fmt.Errorf("line %d: quantity must be greater than 0", i+1)
+		}
+		if _, err := s.itemRepo.FindByID(line.ItemID); err != nil {
+			return nil, fmt.Errorf("line %d: item not found", i+1)
+		}
+		lines = append(lines, models.SalesOrderLine{
+			ItemID:    line.ItemID,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+		})
+	}
+	
+	order := &models.SalesOrder{
+		CustomerName:      req.CustomerName,
+		CustomerReference: req.CustomerReference,
+		Status:            models.SalesOrderStatusDraft,
+		WarehouseID:       req.WarehouseID,
+		Notes:             req.Notes,
+		CreatedBy:         userID,
+		Lines:             lines,
+	}
+	if err := s.orderRepo.Create(order); err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(order.ID)
+}
+
+// ConfirmSalesOrder reserves every line against the stock available in the
+// warehouse the order ships from, defaulting that warehouse if none is set.
+// Either all lines are reserved or none are.
+func (s *SalesOrderService) ConfirmSalesOrder(id string) (*models.SalesOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		itemRepo := s.itemRepo.WithTx(tx)
+		reservationRepo := s.reservationRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("sales order not found")
+		}
+		if order.Status != models.SalesOrderStatusDraft {
+			return errors.New("only draft sales orders can be confirmed")
+		}
+		
+		warehouseID, _, err := s.itemService.resolveLocation(tx, order.WarehouseID, "")
+		if err != nil {
+			return err
+		}
+		order.WarehouseID = warehouseID
+		
+		expiresAt := time.Now().Add(time.Duration(s.config.ReservationTTLHours) * time.Hour)
+		
+		for _, line := range order.Lines {
+			item, err := itemRepo.FindByIDForUpdate(line.ItemID)
+			if err != nil {
+				return fmt.Errorf("item %s not found", line.ItemID)
+			}
+			
+			available := item.Stock - item.ReservedStock
+			if available < line.Quantity {
+				return fmt.Errorf("insufficient available stock for %s: %d available, %d requested", item.Name, available, line.Quantity)
+			}
+			
+			onHand, err := s.itemService.itemStockRepo.WithTx(tx).WarehouseQuantity(item.ID, warehouseID)
+			if err != nil {
+				return err
+			}
+			reserved, err := reservationRepo.SumActiveAt(item.ID, warehouseID)
+			if err != nil {
+				return err
+			}
+			if onHand-reserved < line.Quantity {
+				return fmt.Errorf("insufficient available stock for %s in the order's warehouse: %d available, %d requested", item.Name, onHand-reserved, line.Quantity)
+			}
+			
+			if err := itemRepo.UpdateReservedStock(item.ID, line.Quantity); err != nil {
+				return err
+			}
+			if err := reservationRepo.Create(&models.StockReservation{
+				ItemID:           item.ID,
+				WarehouseID:      warehouseID,
+				SalesOrderID:     order.ID,
+				SalesOrderLineID: line.ID,
+				Quantity:         line.Quantity,
+				Status:           models.ReservationStatusActive,
+				ExpiresAt:        expiresAt,
+			}); err != nil {
+				return err
+			}
+		}
+		
+		now := time.Now()
+		order.Status = models.SalesOrderStatusConfirmed
+		order.ConfirmedAt = &now
+		order.ReservationExpiresAt = &expiresAt
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
+
+func (s *SalesOrderService) PickSalesOrder(id string) (*models.SalesOrder, error) {
+	return s.advance(id, models.SalesOrderStatusConfirmed, models.SalesOrderStatusPicked)
+}
+
+func (s *SalesOrderService) PackSalesOrder(id string) (*models.SalesOrder, error) {
+	return s.advance(id, models.SalesOrderStatusPicked, models.SalesOrderStatusPacked)
+}
+
+// ShipSalesOrder converts the order's reservations into stock decrements.
+// Each line is taken from the bins of the order's warehouse that hold it,
+// and lines of serialized items ship the serial numbers given for them.
+func (s *SalesOrderService) ShipSalesOrder(id string, req *models.ShipSalesOrderRequest, userID string) (*models.SalesOrder, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("sales order not found")
+		}
+		if order.Status != models.SalesOrderStatusPacked {
+			return errors.New("only packed sales orders can be shipped")
+		}
+		
+		shipments, err := shipLines(order, req)
+		if err != nil {
+			return err
+		}
+		
+		if err := s.closeReservations(tx, order.ID, models.ReservationStatusFulfilled); err != nil {
+			return err
+		}
+		
+		for _, line := range order.Lines {
+			parts, err := s.shipmentParts(tx, order.WarehouseID, &line, shipments[line.ID])
+			if err != nil {
+				return fmt.Errorf("line %s: %v", line.ID, err)
+			}
+			for _, part := range parts {
+				_, err := s.itemService.applyStockChange(tx, user, &stockChange{
+					ItemID:        line.ItemID,
+					WarehouseID:   order.WarehouseID,
+					BinID:         part.BinID,
+					Delta:         -part.Quantity,
+					ReasonCode:    models.ReasonCodeShipment,
+					Action:        models.ActivityTypeStockDecrement,
+					Description:   fmt.Sprintf("Shipped on %s to %s", order.Number, order.CustomerName),
+					ReferenceType: models.ReferenceTypeSalesOrder,
+					ReferenceID:   order.ID,
+					SerialNumbers: part.SerialNumbers,
+				})
+				if err != nil {
+					return fmt.Errorf("line %s: %v", line.ID, err)
+				}
+			}
+			if err := orderRepo.SetLineShipped(line.ID, line.Quantity); err != nil {
+				return err
+			}
+		}
+		
+		now := time.Now()
+		order.Status = models.SalesOrderStatusShipped
+		order.ShippedAt = &now
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
+
+// shipLines validates the lines of a shipment request and keys them by
+// order line, with their serial numbers normalized.
+func shipLines(order *models.SalesOrder, req *models.ShipSalesOrderRequest) (map[string]models.ShipLineRequest, error) {
+	shipments := map[string]models.ShipLineRequest{}
+	if req == nil {
+		return shipments, nil
+	}
+	
+	lineIDs := make(map[string]bool, len(order.Lines))
+	for _, line := range order.Lines {
+		lineIDs[line.ID] = true
+	}
+	for _, shipment := range req.Lines {
+		if !lineIDs[shipment.LineID] {
+			return nil, fmt.Errorf("line %s does not belong to this sales order", shipment.LineID)
+		}
+		if _, seen := shipments[shipment.LineID]; seen {
+			return nil, fmt.Errorf("line %s is given more than once", shipment.LineID)
+		}
+		numbers, err := normalizeSerials(shipment.SerialNumbers)
+		if err != nil {
+			return nil, fmt.Errorf("line %s: %v", shipment.LineID, err)
+		}
+		shipment.BinID = strings.TrimSpace(shipment.BinID)
+		shipment.SerialNumbers = numbers
+		shipments[shipment.LineID] = shipment
+	}
+	return shipments, nil
+}
+
+// shipmentPart is the part of a line shipped from one bin.
+type shipmentPart struct {
+	BinID         string
+	Quantity      int
+	SerialNumbers []string
+}
+
+// shipmentParts splits a line across the bins it ships from: the bin given,
+// the bins its serials sit in, or the warehouse's stocked bins in order. The
+// item is locked first, as every stock change does, so the rows read here
+// cannot change before they are decremented.
+func (s *SalesOrderService) shipmentParts(tx *gorm.DB, warehouseID string, line *models.SalesOrderLine, shipment models.ShipLineRequest) ([]shipmentPart, error) {
+	if shipment.BinID != "" {
+		return []shipmentPart{{BinID: shipment.BinID, Quantity: line.Quantity, SerialNumbers: shipment.SerialNumbers}}, nil
+	}
+	
+	item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(line.ItemID)
+	if err != nil {
+		return nil, fmt.Errorf("item %s not found", line.ItemID)
+	}
+	
+	if len(shipment.SerialNumbers) > 0 {
+		if err := checkSerialCount(item, shipment.SerialNumbers, line.Quantity); err != nil {
+			return nil, err
+		}
+		existing, err := s.itemService.serialRepo.WithTx(tx).FindForUpdate(item.ID, shipment.SerialNumbers)
+		if err != nil {
+			return nil, err
+		}
+		
+		var parts []shipmentPart
+		byBin := map[string]int{}
+		for _, number := range shipment.SerialNumbers {
+			serial := existing[number]
+			if serial == nil || serial.Status != models.SerialStatusInStock || serial.WarehouseID != warehouseID {
+				return nil, fmt.Errorf("serial %s is not in stock in the order's warehouse", number)
+			}
+			i, ok := byBin[serial.BinID]
+			if !ok {
+				i = len(parts)
+				byBin[serial.BinID] = i
+				parts = append(parts, shipmentPart{BinID: serial.BinID})
+			}
+			parts[i].Quantity++
+			parts[i].SerialNumbers = append(parts[i].SerialNumbers, number)
+		}
+		return parts, nil
+	}
+	
+	stocks, err := s.itemService.itemStockRepo.WithTx(tx).FindInWarehouse(item.ID, warehouseID)
+	if err != nil {
+		return nil, err
+	}
+	
+	var parts []shipmentPart
+	remaining := line.Quantity
+	for _, stock := range stocks {
+		if remaining == 0 {
+			break
+		}
+		taken := stock.Quantity
+		if taken > remaining {
+			taken = remaining
+		}
+		parts = append(parts, shipmentPart{BinID: stock.BinID, Quantity: taken})
+		remaining -= taken
+	}
+	if remaining > 0 {
+		return nil, fmt.Errorf("insufficient stock of %s in the order's warehouse: %d of %d on hand", item.Name, line.Quantity-remaining, line.Quantity)
+	}
+	return parts, nil
+}
+
+func (s *SalesOrderService) CancelSalesOrder(id string) (*models.SalesOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("sales order not found")
+		}
+		
+		switch order.Status {
+		case models.SalesOrderStatusShipped, models.SalesOrderStatusCancelled, models.SalesOrderStatusExpired:
+			return fmt.Errorf("a %s sales order cannot be cancelled", order.Status)
+		}
+		
+		if err := s.closeReservations(tx, order.ID, models.ReservationStatusReleased); err != nil {
+			return err
+		}
+		
+		order.Status = models.SalesOrderStatusCancelled
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
+
+// ExpireReservations releases reservations that have passed their expiry
+// and marks their orders as expired. Only confirmed orders expire: once
+// picking has started the reservation holds until shipment or cancellation.
+// It is run periodically by the server.
+func (s *SalesOrderService) ExpireReservations() error {
+	orderIDs, err := s.reservationRepo.FindExpiredOrderIDs(time.Now())
+	if err != nil {
+		return err
+	}
+	
+	for _, orderID := range orderIDs {
+		err := s.db.Transaction(func(tx *gorm.DB) error {
+			orderRepo := s.orderRepo.WithTx(tx)
+			
+			order, err := orderRepo.FindByIDForUpdate(orderID)
+			if err != nil {
+				return err
+			}
+			if order.Status != models.SalesOrderStatusConfirmed {
+				return nil
+			}
+			if order.ReservationExpiresAt == nil || order.ReservationExpiresAt.After(time.Now()) {
+				return nil
+			}
+			
+			if err := s.closeReservations(tx, order.ID, models.ReservationStatusExpired); err != nil {
+				return err
+			}
+			
+			order.Status = models.SalesOrderStatusExpired
+			return orderRepo.Update(order)
+		})
+		if err != nil {
+			return fmt.Errorf("expire reservations of order %s: %w", orderID, err)
+		}
+	}
+	
+	return nil
+}
+
+func (s *SalesOrderService) closeReservations(tx *gorm.DB, orderID string, status models.ReservationStatus) error {
+	reservationRepo := s.reservationRepo.WithTx(tx)
+	itemRepo := s.itemRepo.WithTx(tx)
+	
+	reservations, err := reservationRepo.FindActiveByOrder(orderID)
+	if err != nil {
+		return err
+	}
+	
+	for _, reservation := range reservations {
+		if _, err := itemRepo.FindByIDForUpdate(reservation.ItemID); err != nil {
+			return err
+		}
+		if err := itemRepo.UpdateReservedStock(reservation.ItemID, -reservation.Quantity); err != nil {
+			return err
+		}
+		if err := reservationRepo.Close(reservation.ID, status); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *SalesOrderService) advance(id string, from, to models.SalesOrderStatus) (*models.SalesOrder, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		orderRepo := s.orderRepo.WithTx(tx)
+		
+		order, err := orderRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("sales order not found")
+		}
+		if order.Status != from {
+			return fmt.Errorf("sales order must be %s to become %s", from, to)
+		}
+		
+		order.Status = to
+		return orderRepo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.orderRepo.FindByID(id)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+// createTestBin adds a bin with a unique code to the default warehouse.
+func createTestBin(t *testing.T) (*models.Warehouse, *models.Bin) {
+	t.Helper()
+	
+	warehouse, err := repositories.NewWarehouseRepository().FindDefault()
+	if err != nil {
+		t.Fatalf("default warehouse: %v", err)
+	}
+	bin, err := NewWarehouseService().CreateBin(warehouse.ID, &models.CreateBinRequest{
+		Code: "T-" + uuid.New().String()[:8],
+	})
+	if err != nil {
+		t.Fatalf("create bin: %v", err)
+	}
+	return warehouse, bin
+}
+
+// shipTestOrder takes a one-line order for item from draft to shipped.
+func shipTestOrder(t *testing.T, orders *SalesOrderService, user *models.User, warehouseID, itemID string, quantity int, req *models.ShipSalesOrderRequest) {
+	t.Helper()
+	
+	order, err := orders.CreateSalesOrder(&models.CreateSalesOrderRequest{
+		CustomerName: "Test customer",
+		WarehouseID:  warehouseID,
+		Lines:        []models.SalesOrderLineRequest{{ItemID: itemID, Quantity: quantity, UnitPrice: 2}},
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create order: %v", err)
+	}
+	if _, err := orders.ConfirmSalesOrder(order.ID); err != nil {
+		t.Fatalf("confirm order: %v", err)
+	}
+	if _, err := orders.PickSalesOrder(order.ID); err != nil {
+		t.Fatalf("pick order: %v", err)
+	}
+	if _, err := orders.PackSalesOrder(order.ID); err != nil {
+		t.Fatalf("pack order: %v", err)
+	}
+	if req != nil {
+		for i := range req.Lines {
+			req.Lines[i].LineID = order.Lines[0].ID
+		}
+	}
+	if _, err := orders.ShipSalesOrder(order.ID, req, user.ID); err != nil {
+		t.Fatalf("ship order: %v", err)
+	}
+}
+
+func binQuantity(t *testing.T, itemID, warehouseID, binID string) int {
+	t.Helper()
+	
+	var stock models.ItemStock
+	err := database.DB.Where("item_id = ? AND warehouse_id = ? AND bin_id = ?", itemID, warehouseID, binID).
+		First(&stock).Error
+	if err != nil {
+		t.Fatalf("load bin stock: %v", err)
+	}
+	return stock.Quantity
+}
+
+// TestShipSalesOrderFromBin ships an order whose stock sits only in a bin,
+// which confirming accepts, so shipping must take it from there.
+func TestShipSalesOrderFromBin(t *testing.T) {
+	cfg := openTestDB(t)
+	items := NewItemService(cfg)
+	orders := NewSalesOrderService(cfg)
+	user := createTestUser(t)
+	warehouse, bin := createTestBin(t)
+	
+	item := createTestItem(t, items, user, models.CreateItemRequest{
+		Stock:       10,
+		WarehouseID: warehouse.ID,
+		BinID:       bin.ID,
+	})
+	
+	shipTestOrder(t, orders, user, warehouse.ID, item.ID, 4, nil)
+	
+	if got := binQuantity(t, item.ID, warehouse.ID, bin.ID); got != 6 {
+		t.Errorf("bin stock = %d, want 6", got)
+	}
+	updated, err := items.GetItemByID(item.ID)
+	if err != nil {
+		t.Fatalf("reload item: %v", err)
+	}
+	if updated.Stock != 6 || updated.ReservedStock != 0 {
+		t.Errorf("item stock = %d reserved %d, want 6 and 0", updated.Stock, updated.ReservedStock)
+	}
+}
+
+// TestShipSerializedSalesOrderFromBin ships serials by number alone; they
+// leave the bin they are in.
+func TestShipSerializedSalesOrderFromBin(t *testing.T) {
+	cfg := openTestDB(t)
+	items := NewItemService(cfg)
+	orders := NewSalesOrderService(cfg)
+	user := createTestUser(t)
+	warehouse, bin := createTestBin(t)
+	
+	prefix := uuid.New().String()[:8]
+	serials := []string{prefix + "-1", prefix + "-2", prefix + "-3"}
+	item := createTestItem(t, items, user, models.CreateItemRequest{
+		Stock:         3,
+		WarehouseID:   warehouse.ID,
+		BinID:         bin.ID,
+		Serialized:    true,
+		SerialNumbers: serials,
+	})
+	
+	shipTestOrder(t, orders, user, warehouse.ID, item.ID, 2, &models.ShipSalesOrderRequest{
+		Lines: []models.ShipLineRequest{{SerialNumbers: serials[:2]}},
+	})
+	
+	if got := binQuantity(t, item.ID, warehouse.ID, bin.ID); got != 1 {
+		t.Errorf("bin stock = %d, want 1", got)
+	}
+	inStock, err := repositories.NewSerialRepository().FindInStockAt(item.ID, warehouse.ID, bin.ID)
+	if err != nil {
+		t.Fatalf("load serials: %v", err)
+	}
+	if len(inStock) != 1 || inStock[0] != serials[2] {
+		t.Errorf("serials in stock = %v, want [%s]", inStock, serials[2])
+	}
+}
\ No newline at end of file
@@ -0,0 +1,181 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// normalizeSerials trims the serial numbers of a request and rejects blanks
+// and repeats, so each one names exactly one unit.
+func normalizeSerials(serials []string) ([]string, error) {
+	seen := make(map[string]bool, len(serials))
+	normalized := make([]string, 0, len(serials))
+	for _, serial := range serials {
+		serial = strings.TrimSpace(serial)
+		if serial == "" {
+			return nil, errors.New("serial numbers cannot be blank")
+		}
+		if seen[serial] {
+			return nil, fmt.Errorf("serial %s is given more than once", serial)
+		}
+		seen[serial] = true
+		normalized = append(normalized, serial)
+	}
+	return normalized, nil
+}
+
+func checkSerialCount(item *models.Item, serials []string, quantity int) error {
+	if quantity < 0 {
+		quantity = -quantity
+	}
+	if len(serials) != quantity {
+		return fmt.Errorf("%s is serialized: %d serial numbers given for a quantity of %d", item.Name, len(serials), quantity)
+	}
+	return nil
+}
+
+// moveSerials registers serials coming into stock at the activity's location
+// or takes them out of stock there, and records the activity in each
+// serial's history. A serial that left earlier may come back in.
+func (s *ItemService) moveSerials(tx *gorm.DB, item *models.Item, activity *models.ActivityLog, serials []string, delta int) error {
+	if err := checkSerialCount(item, serials, delta); err != nil {
+		return err
+	}
+	serialRepo := s.serialRepo.WithTx(tx)
+	
+	existing, err := serialRepo.FindForUpdate(item.ID, serials)
+	if err != nil {
+		return err
+	}
+	
+	rows := make([]*models.SerialNumber, 0, len(serials))
+	for _, number := range serials {
+		serial := existing[number]
+		
+		if delta > 0 {
+			if serial != nil && serial.Status == models.SerialStatusInStock {
+				return fmt.Errorf("serial %s is already in stock", number)
+			}
+			if serial == nil {
+				serial = &models.SerialNumber{ItemID: item.ID, Serial: number}
+			}
+			serial.Status = models.SerialStatusInStock
+			serial.WarehouseID = activity.WarehouseID
+			serial.BinID = activity.BinID
+		} else {
+			if serial == nil || serial.Status != models.SerialStatusInStock {
+				return fmt.Errorf("serial %s is not in stock", number)
+			}
+			if serial.WarehouseID != activity.WarehouseID || serial.BinID != activity.BinID {
+				return fmt.Errorf("serial %s is not at this location", number)
+			}
+			serial.Status = models.SerialStatusOut
+		}
+		
+		if serial.ID == "" {
+			err = serialRepo.Create(serial)
+		} else {
+			err = serialRepo.Update(serial)
+		}
+		if err != nil {
+			return err
+		}
+		rows = append(rows, serial)
+	}
+	
+	return s.recordSerialEvents(tx, rows, activity)
+}
+
+// relocateSerials moves in-stock serials between two locations for a
+// transfer. The history entries are written per leg by the caller.
+func (s *ItemService) relocateSerials(tx *gorm.DB, item *models.Item, serials []string, quantity int, fromWarehouse, fromBin, toWarehouse, toBin string) ([]*models.SerialNumber, error) {
+	if err := checkSerialCount(item, serials, quantity); err != nil {
+		return nil, err
+	}
+	serialRepo := s.serialRepo.WithTx(tx)
+	
+	existing, err := serialRepo.FindForUpdate(item.ID, serials)
+	if err != nil {
+		return nil, err
+	}
+	
+	rows := make([]*models.SerialNumber, 0, len(serials))
+	for _, number := range serials {
+		serial := existing[number]
+		if serial == nil || serial.Status != models.SerialStatusInStock {
+			return nil, fmt.Errorf("serial %s is not in stock", number)
+		}
+		if serial.WarehouseID != fromWarehouse || serial.BinID != fromBin {
+			return nil, fmt.Errorf("serial %s is not at the source location", number)
+		}
+		
+		serial.WarehouseID = toWarehouse
+		serial.BinID = toBin
+		if err := serialRepo.Update(serial); err != nil {
+			return nil, err
+		}
+		rows = append(rows, serial)
+	}
+	return rows, nil
+}
+
+func (s *ItemService) recordSerialEvents(tx *gorm.DB, serials []*models.SerialNumber, activity *models.ActivityLog) error {
+	serialRepo := s.serialRepo.WithTx(tx)
+	
+	for _, serial := range serials {
+		event := &models.SerialEvent{
+			SerialID:      serial.ID,
+			ActivityID:    activity.ID,
+			Action:        activity.Action,
+			Status:        serial.Status,
+			WarehouseID:   activity.WarehouseID,
+			BinID:         activity.BinID,
+			UserID:        activity.UserID,
+			UserName:      activity.UserName,
+			Description:   activity.Description,
+			ReferenceType: activity.ReferenceType,
+			ReferenceID:   activity.ReferenceID,
+		}
+		if err := serialRepo.CreateEvent(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type SerialService struct {
+	serialRepo *repositories.SerialRepository
+	itemRepo   *repositories.ItemRepository
+}
+
+func NewSerialService() *SerialService {
+	return &SerialService{
+		serialRepo: repositories.NewSerialRepository(),
+		itemRepo:   repositories.NewItemRepository(),
+	}
+}
+
+func (s *SerialService) GetItemSerials(itemID, status string) ([]models.SerialNumber, error) {
+	if _, err := s.itemRepo.FindByID(itemID); err != nil {
+		return nil, errors.New("item not found")
+	}
+	return s.serialRepo.FindByItemID(itemID, status)
+}
+
+// LookupSerial finds the units carrying a serial number, with their history.
+func (s *SerialService) LookupSerial(serial string) ([]models.SerialNumber, error) {
+	serials, err := s.serialRepo.FindBySerial(strings.TrimSpace(serial))
+	if err != nil {
+		return nil, err
+	}
+	if len(serials) == 0 {
+		return nil, errors.New("serial not found")
+	}
+	return serials, nil
+}
\ No newline at end of file
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"inventory-api/internal/models"
+)
+
+// TestSerialMovesCheckLocation takes a serial out only where it is, refuses
+// to take in one that is still in stock, and lets a shipped one return.
+func TestSerialMovesCheckLocation(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	warehouse, bin := createTestBin(t)
+	
+	prefix := uuid.New().String()[:8]
+	serials := []string{prefix + "-1", prefix + "-2"}
+	item := createTestItem(t, service, user, models.CreateItemRequest{
+		Stock:         2,
+		WarehouseID:   warehouse.ID,
+		BinID:         bin.ID,
+		Serialized:    true,
+		SerialNumbers: serials,
+	})
+	
+	move := func(kind string, serial, binID string) error {
+		_, err := service.UpdateStock(item.ID, &models.UpdateStockRequest{
+			Quantity:      1,
+			Type:          kind,
+			WarehouseID:   warehouse.ID,
+			BinID:         binID,
+			SerialNumbers: []string{serial},
+		}, user.ID, 0)
+		return err
+	}
+	
+	if err := move("decrement", serials[0], ""); err == nil {
+		t.Error("took a serial out of a location it is not at")
+	}
+	if err := move("decrement", serials[0], bin.ID); err != nil {
+		t.Fatalf("take serial out: %v", err)
+	}
+	if err := move("increment", serials[1], bin.ID); err == nil {
+		t.Error("took in a serial that is already in stock")
+	}
+	if err := move("increment", serials[0], bin.ID); err != nil {
+		t.Fatalf("return serial: %v", err)
+	}
+	
+	if got := binQuantity(t, item.ID, warehouse.ID, bin.ID); got != 2 {
+		t.Errorf("bin stock = %d, want 2", got)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,75 @@
+package services
+
+import (
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type StockAlertService struct {
+	alertRepo *repositories.StockAlertRepository
+}
+
+func NewStockAlertService() *StockAlertService {
+	return &StockAlertService{
+		alertRepo: repositories.NewStockAlertRepository(),
+	}
+}
+
+func (s *StockAlertService) GetAllAlerts(page, limit int, status, alertType, itemID string) ([]models.StockAlert, int64, error) {
+	return s.alertRepo.FindAll(page, limit, status, alertType, itemID)
+}
+
+// SuggestedReorderQuantity is the quantity that brings stock back up to
+// MaxStock, or to MinStock when no usable MaxStock is set.
+func SuggestedReorderQuantity(item *models.Item) int {
+	target := item.MaxStock
+	if target < item.MinStock {
+		target = item.MinStock
+	}
+	if item.Stock >= target {
+		return 0
+	}
+	return target - item.Stock
+}
+
+// evaluateStockAlerts opens, refreshes or resolves the item's alerts for
+// its current stock. It runs in the caller's transaction, after the item
+// row has been locked, so two changes cannot both open the same alert.
+func (s *ItemService) evaluateStockAlerts(tx *gorm.DB, item *models.Item) error {
+	if err := s.syncStockAlert(tx, item, models.StockAlertTypeLowStock,
+		item.Stock < item.MinStock, item.MinStock, SuggestedReorderQuantity(item)); err != nil {
+		return err
+	}
+	
+	return s.syncStockAlert(tx, item, models.StockAlertTypeOverstock,
+		item.MaxStock > 0 && item.Stock > item.MaxStock, item.MaxStock, 0)
+}
+
+func (s *ItemService) syncStockAlert(tx *gorm.DB, item *models.Item, alertType models.StockAlertType, triggered bool, threshold, suggested int) error {
+	alertRepo := s.alertRepo.WithTx(tx)
+	
+	open, err := alertRepo.FindOpen(item.ID, alertType)
+	if err != nil {
+		return err
+	}
+	
+	switch {
+	case triggered && open == nil:
+		return alertRepo.Create(&models.StockAlert{
+			ItemID:            item.ID,
+			Type:              alertType,
+			Status:            models.StockAlertStatusOpen,
+			Threshold:         threshold,
+			Stock:             item.Stock,
+			SuggestedQuantity: suggested,
+		})
+	case triggered:
+		return alertRepo.Refresh(open.ID, item.Stock, suggested)
+	case open != nil:
+		return alertRepo.Resolve(open.ID, item.Stock)
+	}
+	
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+type StockLedgerService struct {
+	itemRepo      *repositories.ItemRepository
+	itemStockRepo *repositories.ItemStockRepository
+	movementRepo  *repositories.StockMovementRepository
+}
+
+func NewStockLedgerService() *StockLedgerService {
+	return &StockLedgerService{
+		itemRepo:      repositories.NewItemRepository(),
+		itemStockRepo: repositories.NewItemStockRepository(),
+		movementRepo:  repositories.NewStockMovementRepository(),
+	}
+}
+
+func (s *StockLedgerService) GetMovements(itemID string, page, limit int, from, to *time.Time) ([]models.StockMovement, int64, error) {
+	if _, err := s.itemRepo.FindByID(itemID); err != nil {
+		return nil, 0, errors.New("item not found")
+	}
+	return s.movementRepo.FindByItemID(itemID, page, limit, from, to)
+}
+
+func (s *StockLedgerService) GetStockAsOf(itemID string, asOf time.Time) (*models.StockAsOf, error) {
+	if _, err := s.itemRepo.FindByID(itemID); err != nil {
+		return nil, errors.New("item not found")
+	}
+	
+	locations, err := s.movementRepo.SumByLocationAsOf(itemID, asOf)
+	if err != nil {
+		return nil, err
+	}
+	
+	result := &models.StockAsOf{
+		ItemID:    itemID,
+		AsOf:      asOf,
+		Locations: locations,
+	}
+	for _, location := range locations {
+		result.Stock += location.Quantity
+	}
+	
+	return result, nil
+}
+
+// ReconcileItem compares the cached Item.Stock and per-location rows with
+// what the ledger says they should be.
+func (s *StockLedgerService) ReconcileItem(itemID string) (*models.StockReconciliation, error) {
+	item, err := s.itemRepo.FindByID(itemID)
+	if err != nil {
+		return nil, errors.New("item not found")
+	}
+	
+	ledger, err := s.movementRepo.SumByLocation(itemID)
+	if err != nil {
+		return nil, err
+	}
+	cached, err := s.itemStockRepo.FindByItemID(itemID)
+	if err != nil {
+		return nil, err
+	}
+	
+	type locationKey struct{ warehouseID, binID string }
+	drifts := map[locationKey]*models.LocationDrift{}
+	var order []locationKey
+	
+	entry := func(warehouseID, binID string) *models.LocationDrift {
+		key := locationKey{warehouseID, binID}
+		if drifts[key] == nil {
+			drifts[key] = &models.LocationDrift{WarehouseID: warehouseID, BinID: binID}
+			order = append(order, key)
+		}
+		return drifts[key]
+	}
+	
+	result := &models.StockReconciliation{
+		ItemID:      item.ID,
+		ItemName:    item.Name,
+		SKU:         item.SKU,
+		CachedStock: item.Stock,
+	}
+	
+	for _, row := range ledger {
+		entry(row.WarehouseID, row.BinID).LedgerStock = row.Quantity
+		result.LedgerStock += row.Quantity
+	}
+	for _, row := range cached {
+		entry(row.WarehouseID, row.BinID).CachedStock = row.Quantity
+	}
+	
+	for _, key := range order {
+		drift := drifts[key]
+		drift.Drift = drift.CachedStock - drift.LedgerStock
+		if drift.Drift != 0 {
+			result.Locations = append(result.Locations, *drift)
+		}
+	}
+	
+	result.Drift = result.CachedStock - result.LedgerStock
+	result.InSync = result.Drift == 0 && len(result.Locations) == 0
+	
+	return result, nil
+}
+
+func (s *StockLedgerService) ReconcileAll(onlyDrifting bool) ([]models.StockReconciliation, error) {
+	return s.movementRepo.FindDrift(onlyDrifting)
+}
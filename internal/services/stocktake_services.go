@@ -0,0 +1,599 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// defaultDeviceID is used for counts submitted without a device, so a
+// single-device session needs no extra field.
+const defaultDeviceID = "default"
+
+type StocktakeService struct {
+	db            *gorm.DB
+	stocktakeRepo *repositories.StocktakeRepository
+	itemStockRepo *repositories.ItemStockRepository
+	itemRepo      *repositories.ItemRepository
+	lotRepo       *repositories.LotRepository
+	serialRepo    *repositories.SerialRepository
+	userRepo      *repositories.UserRepository
+	itemService   *ItemService
+}
+
+func NewStocktakeService(cfg *config.Config) *StocktakeService {
+	return &StocktakeService{
+		db:            database.DB,
+		stocktakeRepo: repositories.NewStocktakeRepository(),
+		itemStockRepo: repositories.NewItemStockRepository(),
+		itemRepo:      repositories.NewItemRepository(),
+		lotRepo:       repositories.NewLotRepository(),
+		serialRepo:    repositories.NewSerialRepository(),
+		userRepo:      repositories.NewUserRepository(),
+		itemService:   NewItemService(cfg),
+	}
+}
+
+func (s *StocktakeService) GetAllStocktakes(page, limit int, status string) ([]models.Stocktake, int64, error) {
+	return s.stocktakeRepo.FindAll(page, limit, status)
+}
+
+func (s *StocktakeService) GetStocktakeByID(id string) (*models.Stocktake, error) {
+	stocktake, err := s.stocktakeRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("stocktake not found")
+	}
+	return stocktake, nil
+}
+
+// CreateStocktake freezes the expected quantity of every location in scope,
+// per lot for lot-tracked items. Movements made while counting do not change
+// what the session expects.
+func (s *StocktakeService) CreateStocktake(req *models.CreateStocktakeRequest, userID string) (*models.Stocktake, error) {
+	var stocktake *models.Stocktake
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		warehouseID, binID := req.WarehouseID, req.BinID
+		if warehouseID != "" || binID != "" {
+			var err error
+			warehouseID, binID, err = s.itemService.resolveLocation(tx, warehouseID, binID)
+			if err != nil {
+				return err
+			}
+		}
+		
+		itemIDs := uniqueStrings(req.ItemIDs)
+		for _, itemID := range itemIDs {
+			if _, err := s.itemRepo.WithTx(tx).FindByID(itemID); err != nil {
+				return fmt.Errorf("item %s not found", itemID)
+			}
+		}
+		
+		stocks, err := s.itemStockRepo.WithTx(tx).FindForStocktake(warehouseID, binID, itemIDs)
+		if err != nil {
+			return err
+		}
+		
+		lines := make([]models.StocktakeLine, 0, len(stocks))
+		covered := map[string]bool{}
+		lotTracked := map[string]bool{}
+		for _, stock := range stocks {
+			covered[stock.ItemID] = true
+			
+			tracked, known := lotTracked[stock.ItemID]
+			if !known {
+				item, err := s.itemRepo.WithTx(tx).FindByID(stock.ItemID)
+				if err != nil {
+					return err
+				}
+				tracked = item.LotTracked
+				lotTracked[stock.ItemID] = tracked
+			}
+			if tracked {
+				lots, err := s.lotRepo.WithTx(tx).FindForStocktake(stock.ItemID, stock.WarehouseID, stock.BinID)
+				if err != nil {
+					return err
+				}
+				for _, lot := range lots {
+					expiresAt := lot.ExpiresAt
+					lines = append(lines, models.StocktakeLine{
+						ItemID:           stock.ItemID,
+						WarehouseID:      stock.WarehouseID,
+						BinID:            stock.BinID,
+						LotNumber:        lot.LotNumber,
+						ExpiresAt:        &expiresAt,
+						ExpectedQuantity: lot.Quantity,
+					})
+				}
+				if len(lots) > 0 {
+					continue
+				}
+			}
+			
+			lines = append(lines, models.StocktakeLine{
+				ItemID:           stock.ItemID,
+				WarehouseID:      stock.WarehouseID,
+				BinID:            stock.BinID,
+				ExpectedQuantity: stock.Quantity,
+			})
+		}
+		if warehouseID != "" {
+			for _, itemID := range itemIDs {
+				if !covered[itemID] {
+					lines = append(lines, models.StocktakeLine{
+						ItemID:      itemID,
+						WarehouseID: warehouseID,
+						BinID:       binID,
+					})
+				}
+			}
+		}
+		if len(lines) == 0 {
+			return errors.New("nothing to count in the given scope")
+		}
+		
+		stocktake = &models.Stocktake{
+			Status:      models.StocktakeStatusCounting,
+			WarehouseID: warehouseID,
+			BinID:       binID,
+			Notes:       req.Notes,
+			CreatedBy:   userID,
+			Lines:       lines,
+		}
+		return s.stocktakeRepo.WithTx(tx).Create(stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.stocktakeRepo.FindByID(stocktake.ID)
+}
+
+// SubmitCounts records what one device counted. Several devices may count
+// the same session; a line's counted quantity is the sum over devices.
+// Lot-tracked items must be counted per lot, so approval knows which lot a
+// surplus goes to, and serialized items by serial number.
+func (s *StocktakeService) SubmitCounts(id string, req *models.SubmitStocktakeCountsRequest, userID string) (*models.Stocktake, error) {
+	deviceID := strings.TrimSpace(req.DeviceID)
+	if deviceID == "" {
+		deviceID = defaultDeviceID
+	}
+	
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		stocktakeRepo := s.stocktakeRepo.WithTx(tx)
+		
+		stocktake, err := stocktakeRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("stocktake not found")
+		}
+		if stocktake.Status != models.StocktakeStatusCounting {
+			return errors.New("only stocktakes that are counting accept counts")
+		}
+		
+		for i, entry := range req.Counts {
+			if entry.Quantity < 0 {
+				return fmt.Errorf("count %d: quantity cannot be negative", i+1)
+			}
+			lotNumber, expiresAt, err := parseLot(entry.LotNumber, entry.ExpiresAt)
+			if err != nil {
+				return fmt.Errorf("count %d: %v", i+1, err)
+			}
+			entry.LotNumber = lotNumber
+			
+			line, err := findStocktakeLine(stocktake.Lines, &entry)
+			if err != nil && entry.LineID == "" && entry.LotNumber != "" {
+				line, err = s.addLotLine(tx, stocktake, &entry, expiresAt)
+			}
+			if err != nil {
+				return fmt.Errorf("count %d: %v", i+1, err)
+			}
+			if line.Item != nil && line.Item.LotTracked && line.LotNumber == "" {
+				return fmt.Errorf("count %d: %s is lot-tracked: count it per lot with lot_number", i+1, line.Item.Name)
+			}
+			serials, err := s.countedSerials(tx, line, deviceID, &entry)
+			if err != nil {
+				return fmt.Errorf("count %d: %v", i+1, err)
+			}
+			
+			err = stocktakeRepo.SaveCount(&models.StocktakeCount{
+				StocktakeID:   stocktake.ID,
+				LineID:        line.ID,
+				DeviceID:      deviceID,
+				Quantity:      entry.Quantity,
+				SerialNumbers: serials,
+				CountedBy:     userID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.stocktakeRepo.FindByID(id)
+}
+
+func findStocktakeLine(lines []models.StocktakeLine, entry *models.StocktakeCountEntry) (*models.StocktakeLine, error) {
+	if entry.LineID != "" {
+		for i := range lines {
+			if lines[i].ID == entry.LineID {
+				return &lines[i], nil
+			}
+		}
+		return nil, fmt.Errorf("line %s does not belong to this stocktake", entry.LineID)
+	}
+	if entry.ItemID == "" {
+		return nil, errors.New("line_id or item_id is required")
+	}
+	
+	var match *models.StocktakeLine
+	for i := range lines {
+		line := &lines[i]
+		if line.ItemID != entry.ItemID {
+			continue
+		}
+		if entry.WarehouseID != "" && line.WarehouseID != entry.WarehouseID {
+			continue
+		}
+		if entry.BinID != "" && line.BinID != entry.BinID {
+			continue
+		}
+		if line.LotNumber != entry.LotNumber {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("item %s is counted at more than one location; give warehouse_id and bin_id", entry.ItemID)
+		}
+		match = line
+	}
+	if match == nil {
+		return nil, fmt.Errorf("item %s is not part of this stocktake", entry.ItemID)
+	}
+	return match, nil
+}
+
+// countedSerials validates the serial numbers of a count. Each counted unit
+// needs one, no other device may have counted it for the line, and it must
+// not be in stock somewhere else.
+func (s *StocktakeService) countedSerials(tx *gorm.DB, line *models.StocktakeLine, deviceID string, entry *models.StocktakeCountEntry) ([]string, error) {
+	serialized := line.Item != nil && line.Item.Serialized
+	if !serialized {
+		if len(entry.SerialNumbers) > 0 {
+			return nil, fmt.Errorf("item %s is not serialized", line.ItemID)
+		}
+		return nil, nil
+	}
+	
+	serials, err := normalizeSerials(entry.SerialNumbers)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSerialCount(line.Item, serials, entry.Quantity); err != nil {
+		return nil, err
+	}
+	
+	counts, err := s.stocktakeRepo.WithTx(tx).FindLineCounts(line.ID)
+	if err != nil {
+		return nil, err
+	}
+	countedBy := map[string]string{}
+	for _, count := range counts {
+		if count.DeviceID == deviceID {
+			continue
+		}
+		for _, serial := range count.SerialNumbers {
+			countedBy[serial] = count.DeviceID
+		}
+	}
+	
+	existing, err := s.serialRepo.WithTx(tx).FindForUpdate(line.ItemID, serials)
+	if err != nil {
+		return nil, err
+	}
+	for _, number := range serials {
+		if device, ok := countedBy[number]; ok {
+			return nil, fmt.Errorf("serial %s is already counted by device %s", number, device)
+		}
+		serial := existing[number]
+		if serial != nil && serial.Status == models.SerialStatusInStock &&
+			(serial.WarehouseID != line.WarehouseID || serial.BinID != line.BinID) {
+			return nil, fmt.Errorf("serial %s is in stock at another location; transfer it first", number)
+		}
+	}
+	return serials, nil
+}
+
+// addLotLine adds a line for a lot counted at a location where the session
+// expected none of it. An unknown lot needs its expiry date.
+func (s *StocktakeService) addLotLine(tx *gorm.DB, stocktake *models.Stocktake, entry *models.StocktakeCountEntry, expiresAt *time.Time) (*models.StocktakeLine, error) {
+	var location *models.StocktakeLine
+	for i := range stocktake.Lines {
+		line := &stocktake.Lines[i]
+		if line.ItemID != entry.ItemID {
+			continue
+		}
+		if entry.WarehouseID != "" && line.WarehouseID != entry.WarehouseID {
+			continue
+		}
+		if entry.BinID != "" && line.BinID != entry.BinID {
+			continue
+		}
+		if location != nil && (location.WarehouseID != line.WarehouseID || location.BinID != line.BinID) {
+			return nil, fmt.Errorf("item %s is counted at more than one location; give warehouse_id and bin_id", entry.ItemID)
+		}
+		location = line
+	}
+	if location == nil {
+		return nil, fmt.Errorf("item %s is not part of this stocktake", entry.ItemID)
+	}
+	if location.Item == nil || !location.Item.LotTracked {
+		return nil, fmt.Errorf("item %s is not lot-tracked", entry.ItemID)
+	}
+	
+	lot, err := s.lotRepo.WithTx(tx).FindAt(entry.ItemID, location.WarehouseID, location.BinID, entry.LotNumber)
+	switch {
+	case err == nil:
+		if expiresAt != nil && expiresAt.Format("2006-01-02") != lot.ExpiresAt.Format("2006-01-02") {
+			return nil, fmt.Errorf("lot %s already exists with expiry date %s", lot.LotNumber, lot.ExpiresAt.Format("2006-01-02"))
+		}
+		expiresAt = &lot.ExpiresAt
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	case expiresAt == nil:
+		return nil, fmt.Errorf("lot %s is not on this stocktake: expires_at is required to add it", entry.LotNumber)
+	}
+	
+	line := models.StocktakeLine{
+		StocktakeID: stocktake.ID,
+		ItemID:      entry.ItemID,
+		Item:        location.Item,
+		WarehouseID: location.WarehouseID,
+		BinID:       location.BinID,
+		LotNumber:   entry.LotNumber,
+		ExpiresAt:   expiresAt,
+	}
+	if err := s.stocktakeRepo.WithTx(tx).AddLine(&line); err != nil {
+		return nil, err
+	}
+	stocktake.Lines = append(stocktake.Lines, line)
+	return &stocktake.Lines[len(stocktake.Lines)-1], nil
+}
+
+// GetVariance compares counted and frozen quantities. Lines nobody counted
+// are listed but carry no variance, and are left alone on approval.
+func (s *StocktakeService) GetVariance(id string, onlyVariances bool) (*models.StocktakeVariance, error) {
+	stocktake, err := s.stocktakeRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("stocktake not found")
+	}
+	
+	devices, err := s.stocktakeRepo.CountDevices(stocktake.ID)
+	if err != nil {
+		return nil, err
+	}
+	
+	report := &models.StocktakeVariance{
+		StocktakeID: stocktake.ID,
+		Number:      stocktake.Number,
+		Status:      stocktake.Status,
+		TotalLines:  len(stocktake.Lines),
+		Lines:       []models.StocktakeVarianceLine{},
+	}
+	for _, line := range stocktake.Lines {
+		entry := models.StocktakeVarianceLine{
+			LineID:      line.ID,
+			ItemID:      line.ItemID,
+			WarehouseID: line.WarehouseID,
+			BinID:       line.BinID,
+			LotNumber:   line.LotNumber,
+			Expected:    line.ExpectedQuantity,
+			Counted:     line.CountedQuantity,
+			Variance:    line.Variance(),
+			Devices:     devices[line.ID],
+		}
+		if line.Item != nil {
+			entry.ItemName = line.Item.Name
+			entry.SKU = line.Item.SKU
+			entry.VarianceValue = roundMoney(float64(entry.Variance) * line.Item.Price)
+		}
+		
+		if line.CountedQuantity == nil {
+			report.UncountedLines++
+		} else {
+			report.CountedLines++
+		}
+		if entry.Variance != 0 {
+			report.LinesWithVariance++
+			report.NetVariance += entry.Variance
+			report.NetVarianceValue += entry.VarianceValue
+		}
+		
+		if onlyVariances && entry.Variance == 0 {
+			continue
+		}
+		report.Lines = append(report.Lines, entry)
+	}
+	report.NetVarianceValue = roundMoney(report.NetVarianceValue)
+	
+	return report, nil
+}
+
+// ApproveStocktake posts each counted variance as a STOCK_ADJUSTMENT at the
+// line's location, all in one transaction with the status change. For
+// serialized items the counted serials are compared with those in stock at
+// the location: the ones found are taken in and the missing ones written off.
+func (s *StocktakeService) ApproveStocktake(id, userID string) (*models.Stocktake, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		stocktakeRepo := s.stocktakeRepo.WithTx(tx)
+		
+		stocktake, err := stocktakeRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("stocktake not found")
+		}
+		if stocktake.Status != models.StocktakeStatusCounting {
+			return errors.New("only stocktakes that are counting can be approved")
+		}
+		
+		for i := range stocktake.Lines {
+			line := &stocktake.Lines[i]
+			if line.CountedQuantity == nil {
+				continue
+			}
+			
+			change := stockChange{
+				ItemID:        line.ItemID,
+				WarehouseID:   line.WarehouseID,
+				BinID:         line.BinID,
+				LotNumber:     line.LotNumber,
+				ExpiresAt:     line.ExpiresAt,
+				Delta:         line.Variance(),
+				ReasonCode:    models.ReasonCodeCorrection,
+				Action:        models.ActivityTypeStockAdjustment,
+				Description:   fmt.Sprintf("Stocktake %s: counted %d, expected %d", stocktake.Number, *line.CountedQuantity, line.ExpectedQuantity),
+				ReferenceType: models.ReferenceTypeStocktake,
+				ReferenceID:   stocktake.ID,
+			}
+			
+			var changes []stockChange
+			if line.Item != nil && line.Item.Serialized {
+				if changes, err = s.serialChanges(tx, line, change); err != nil {
+					return fmt.Errorf("item %s: %v", line.ItemID, err)
+				}
+			} else if change.Delta != 0 {
+				changes = []stockChange{change}
+			}
+			if len(changes) == 0 {
+				continue
+			}
+			
+			adjustment := 0
+			for j := range changes {
+				if _, err := s.itemService.applyStockChange(tx, user, &changes[j]); err != nil {
+					return fmt.Errorf("item %s: %v", line.ItemID, err)
+				}
+				adjustment += changes[j].Delta
+			}
+			
+			if err := stocktakeRepo.SetAdjustment(line.ID, adjustment); err != nil {
+				return err
+			}
+		}
+		
+		now := time.Now()
+		stocktake.Status = models.StocktakeStatusApproved
+		stocktake.ApprovedBy = user.ID
+		stocktake.ApprovedAt = &now
+		stocktake.ClosedAt = &now
+		return stocktakeRepo.Update(stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.stocktakeRepo.FindByID(id)
+}
+
+// serialChanges splits a serialized line's adjustment into the serials found
+// on the shelf but not in stock there, and those in stock but not counted.
+func (s *StocktakeService) serialChanges(tx *gorm.DB, line *models.StocktakeLine, change stockChange) ([]stockChange, error) {
+	counts, err := s.stocktakeRepo.WithTx(tx).FindLineCounts(line.ID)
+	if err != nil {
+		return nil, err
+	}
+	inStock, err := s.serialRepo.WithTx(tx).FindInStockAt(line.ItemID, line.WarehouseID, line.BinID)
+	if err != nil {
+		return nil, err
+	}
+	
+	counted := map[string]bool{}
+	for _, count := range counts {
+		for _, serial := range count.SerialNumbers {
+			counted[serial] = true
+		}
+	}
+	stocked := make(map[string]bool, len(inStock))
+	var missing []string
+	for _, serial := range inStock {
+		stocked[serial] = true
+		if !counted[serial] {
+			missing = append(missing, serial)
+		}
+	}
+	var found []string
+	for _, count := range counts {
+		for _, serial := range count.SerialNumbers {
+			if !stocked[serial] {
+				found = append(found, serial)
+			}
+		}
+	}
+	
+	var changes []stockChange
+	if len(found) > 0 {
+		in := change
+		in.Delta = len(found)
+		in.SerialNumbers = found
+		changes = append(changes, in)
+	}
+	if len(missing) > 0 {
+		out := change
+		out.Delta = -len(missing)
+		out.SerialNumbers = missing
+		changes = append(changes, out)
+	}
+	return changes, nil
+}
+
+func (s *StocktakeService) CancelStocktake(id string) (*models.Stocktake, error) {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		stocktakeRepo := s.stocktakeRepo.WithTx(tx)
+		
+		stocktake, err := stocktakeRepo.FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("stocktake not found")
+		}
+		if stocktake.Status != models.StocktakeStatusCounting {
+			return errors.New("only stocktakes that are counting can be cancelled")
+		}
+		
+		now := time.Now()
+		stocktake.Status = models.StocktakeStatusCancelled
+		stocktake.ClosedAt = &now
+		return stocktakeRepo.Update(stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.stocktakeRepo.FindByID(id)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	var unique []string
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
\ No newline at end of file
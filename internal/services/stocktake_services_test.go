@@ -0,0 +1,187 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+func submitTestCounts(t *testing.T, service *StocktakeService, user *models.User, id, deviceID string, counts ...models.StocktakeCountEntry) {
+	t.Helper()
+	
+	_, err := service.SubmitCounts(id, &models.SubmitStocktakeCountsRequest{DeviceID: deviceID, Counts: counts}, user.ID)
+	if err != nil {
+		t.Fatalf("submit counts: %v", err)
+	}
+}
+
+// TestApproveStocktakePostsVariance counts a bin on two devices and checks
+// that approval adjusts stock by the summed count's variance.
+func TestApproveStocktakePostsVariance(t *testing.T) {
+	cfg := openTestDB(t)
+	items := NewItemService(cfg)
+	service := NewStocktakeService(cfg)
+	user := createTestUser(t)
+	warehouse, bin := createTestBin(t)
+	
+	item := createTestItem(t, items, user, models.CreateItemRequest{
+		Stock:       10,
+		WarehouseID: warehouse.ID,
+		BinID:       bin.ID,
+	})
+	
+	stocktake, err := service.CreateStocktake(&models.CreateStocktakeRequest{
+		WarehouseID: warehouse.ID,
+		BinID:       bin.ID,
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create stocktake: %v", err)
+	}
+	if len(stocktake.Lines) != 1 || stocktake.Lines[0].ExpectedQuantity != 10 {
+		t.Fatalf("lines = %+v, want one expecting 10", stocktake.Lines)
+	}
+	
+	submitTestCounts(t, service, user, stocktake.ID, "a", models.StocktakeCountEntry{ItemID: item.ID, Quantity: 4})
+	submitTestCounts(t, service, user, stocktake.ID, "b", models.StocktakeCountEntry{ItemID: item.ID, Quantity: 3})
+	
+	approved, err := service.ApproveStocktake(stocktake.ID, user.ID)
+	if err != nil {
+		t.Fatalf("approve stocktake: %v", err)
+	}
+	if approved.Status != models.StocktakeStatusApproved || approved.Lines[0].Adjustment != -3 {
+		t.Errorf("stocktake = %s with adjustment %d, want approved with -3", approved.Status, approved.Lines[0].Adjustment)
+	}
+	
+	if got := binQuantity(t, item.ID, warehouse.ID, bin.ID); got != 7 {
+		t.Errorf("bin stock = %d, want 7", got)
+	}
+	corrections := findMovements(t, item.ID, models.ReasonCodeCorrection)
+	if len(corrections) != 1 || corrections[0].Quantity != -3 {
+		t.Errorf("correction movements = %+v, want one of -3", corrections)
+	}
+}
+
+// TestStocktakeCountsLotsSeparately counts a lot-tracked item per lot,
+// including a lot found on the shelf that the session did not expect, and
+// checks that approval adjusts each lot.
+func TestStocktakeCountsLotsSeparately(t *testing.T) {
+	cfg := openTestDB(t)
+	items := NewItemService(cfg)
+	service := NewStocktakeService(cfg)
+	user := createTestUser(t)
+	warehouse, bin := createTestBin(t)
+	
+	known, found := "L-"+uuid.New().String()[:8], "L-"+uuid.New().String()[:8]
+	item := createTestItem(t, items, user, models.CreateItemRequest{
+		Stock:       5,
+		WarehouseID: warehouse.ID,
+		BinID:       bin.ID,
+		LotTracked:  true,
+		LotNumber:   known,
+		ExpiresAt:   "2099-01-01",
+	})
+	
+	stocktake, err := service.CreateStocktake(&models.CreateStocktakeRequest{
+		WarehouseID: warehouse.ID,
+		BinID:       bin.ID,
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create stocktake: %v", err)
+	}
+	if len(stocktake.Lines) != 1 || stocktake.Lines[0].LotNumber != known {
+		t.Fatalf("lines = %+v, want one for lot %s", stocktake.Lines, known)
+	}
+	
+	_, err = service.SubmitCounts(stocktake.ID, &models.SubmitStocktakeCountsRequest{
+		Counts: []models.StocktakeCountEntry{{ItemID: item.ID, Quantity: 6}},
+	}, user.ID)
+	if err == nil {
+		t.Fatal("accepted a count of a lot-tracked item without a lot")
+	}
+	
+	submitTestCounts(t, service, user, stocktake.ID, "",
+		models.StocktakeCountEntry{ItemID: item.ID, LotNumber: known, Quantity: 4},
+		models.StocktakeCountEntry{ItemID: item.ID, LotNumber: found, ExpiresAt: "2099-06-01", Quantity: 2},
+	)
+	if _, err := service.ApproveStocktake(stocktake.ID, user.ID); err != nil {
+		t.Fatalf("approve stocktake: %v", err)
+	}
+	
+	lots, err := repositories.NewLotRepository().FindByItemID(item.ID, false)
+	if err != nil {
+		t.Fatalf("load lots: %v", err)
+	}
+	quantities := map[string]int{}
+	for _, lot := range lots {
+		quantities[lot.LotNumber] = lot.Quantity
+	}
+	if len(quantities) != 2 || quantities[known] != 4 || quantities[found] != 2 {
+		t.Errorf("lots = %v, want %s: 4 and %s: 2", quantities, known, found)
+	}
+	if got := binQuantity(t, item.ID, warehouse.ID, bin.ID); got != 6 {
+		t.Errorf("bin stock = %d, want 6", got)
+	}
+}
+
+// TestStocktakeCountsSerials counts a serialized bin by serial number: a
+// missing unit is written off and a unit found on the shelf taken in, with
+// no change to the total.
+func TestStocktakeCountsSerials(t *testing.T) {
+	cfg := openTestDB(t)
+	items := NewItemService(cfg)
+	service := NewStocktakeService(cfg)
+	user := createTestUser(t)
+	warehouse, bin := createTestBin(t)
+	
+	prefix := uuid.New().String()[:8]
+	serials := []string{prefix + "-1", prefix + "-2", prefix + "-3", prefix + "-4"}
+	item := createTestItem(t, items, user, models.CreateItemRequest{
+		Stock:         3,
+		WarehouseID:   warehouse.ID,
+		BinID:         bin.ID,
+		Serialized:    true,
+		SerialNumbers: serials[:3],
+	})
+	
+	stocktake, err := service.CreateStocktake(&models.CreateStocktakeRequest{
+		WarehouseID: warehouse.ID,
+		BinID:       bin.ID,
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create stocktake: %v", err)
+	}
+	
+	_, err = service.SubmitCounts(stocktake.ID, &models.SubmitStocktakeCountsRequest{
+		Counts: []models.StocktakeCountEntry{{ItemID: item.ID, Quantity: 3}},
+	}, user.ID)
+	if err == nil {
+		t.Fatal("accepted a count of a serialized item without serials")
+	}
+	
+	submitTestCounts(t, service, user, stocktake.ID, "",
+		models.StocktakeCountEntry{ItemID: item.ID, Quantity: 3, SerialNumbers: []string{serials[0], serials[1], serials[3]}},
+	)
+	if _, err := service.ApproveStocktake(stocktake.ID, user.ID); err != nil {
+		t.Fatalf("approve stocktake: %v", err)
+	}
+	
+	inStock, err := repositories.NewSerialRepository().FindInStockAt(item.ID, warehouse.ID, bin.ID)
+	if err != nil {
+		t.Fatalf("load serials: %v", err)
+	}
+	want := map[string]bool{serials[0]: true, serials[1]: true, serials[3]: true}
+	if len(inStock) != len(want) {
+		t.Fatalf("serials in stock = %v, want %v", inStock, want)
+	}
+	for _, serial := range inStock {
+		if !want[serial] {
+			t.Errorf("serials in stock = %v, want %v", inStock, want)
+		}
+	}
+	if got := binQuantity(t, item.ID, warehouse.ID, bin.ID); got != 3 {
+		t.Errorf("bin stock = %d, want 3", got)
+	}
+}
\ No newline at end of file
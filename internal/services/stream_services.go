@@ -0,0 +1,59 @@
+package services
+
+import (
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+	"inventory-api/internal/stream"
+)
+
+const streamReplayLimit = 1000
+
+type StreamService struct {
+	broker     *stream.Broker
+	outboxRepo *repositories.OutboxRepository
+}
+
+func NewStreamService() *StreamService {
+	return &StreamService{
+		broker:     stream.Default,
+		outboxRepo: repositories.NewOutboxRepository(),
+	}
+}
+
+// Subscribe registers a live subscription and, when lastEventID is given,
+// returns the matching events published since then. The subscription is
+// made first so nothing published during the replay query is missed; the
+// caller skips live events already seen in the replay.
+func (s *StreamService) Subscribe(filter *stream.Filter, lastEventID string) (*stream.Subscription, []*stream.Event, error) {
+	sub := s.broker.Subscribe(filter)
+	
+	if lastEventID == "" {
+		return sub, nil, nil
+	}
+	
+	outboxEvents, err := s.outboxRepo.FindPublishedAfter(lastEventID, streamReplayLimit)
+	if err != nil {
+		s.broker.Unsubscribe(sub)
+		return nil, nil, err
+	}
+	
+	replay := make([]*stream.Event, 0, len(outboxEvents))
+	for i := range outboxEvents {
+		if outboxEvents[i].AggregateType != models.AggregateTypeItem {
+			continue
+		}
+		event, err := streamEventFromOutbox(&outboxEvents[i])
+		if err != nil {
+			continue
+		}
+		if filter.Matches(event) {
+			replay = append(replay, event)
+		}
+	}
+	
+	return sub, replay, nil
+}
+
+func (s *StreamService) Unsubscribe(sub *stream.Subscription) {
+	s.broker.Unsubscribe(sub)
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+type SupplierService struct {
+	supplierRepo *repositories.SupplierRepository
+}
+
+func NewSupplierService() *SupplierService {
+	return &SupplierService{
+		supplierRepo: repositories.NewSupplierRepository(),
+	}
+}
+
+func (s *SupplierService) GetAllSuppliers(search string, active *bool) ([]models.Supplier, error) {
+	return s.supplierRepo.FindAll(search, active)
+}
+
+func (s *SupplierService) GetSupplierByID(id string) (*models.Supplier, error) {
+	supplier, err := s.supplierRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("supplier not found")
+	}
+	return supplier, nil
+}
+
+func (s *SupplierService) CreateSupplier(req *models.CreateSupplierRequest) (*models.Supplier, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	
+	existing, err := s.supplierRepo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("supplier code already exists")
+	}
+	
+	supplier := &models.Supplier{
+		Code:        code,
+		Name:        req.Name,
+		ContactName: req.ContactName,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		IsActive:    true,
+	}
+	if err := s.supplierRepo.Create(supplier); err != nil {
+		return nil, err
+	}
+	
+	return supplier, nil
+}
+
+func (s *SupplierService) UpdateSupplier(id string, req *models.UpdateSupplierRequest) (*models.Supplier, error) {
+	supplier, err := s.supplierRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("supplier not found")
+	}
+	
+	if req.Name != "" {
+		supplier.Name = req.Name
+	}
+	if req.ContactName != "" {
+		supplier.ContactName = req.ContactName
+	}
+	if req.Email != "" {
+		supplier.Email = req.Email
+	}
+	if req.Phone != "" {
+		supplier.Phone = req.Phone
+	}
+	if req.Address != "" {
+		supplier.Address = req.Address
+	}
+	if req.IsActive != nil {
+		supplier.IsActive = *req.IsActive
+	}
+	
+	if err := s.supplierRepo.Update(supplier); err != nil {
+		return nil, err
+	}
+	
+	return supplier, nil
+}
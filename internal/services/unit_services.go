@@ -0,0 +1,238 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+func normalizeUnit(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func baseUnit(item *models.Item) string {
+	if item.BaseUnit == "" {
+		return models.DefaultBaseUnit
+	}
+	return item.BaseUnit
+}
+
+// unitFactor returns how many base units one of the given unit holds for an
+// item: 1 for its base unit, the conversion factor for any other.
+func (s *ItemService) unitFactor(tx *gorm.DB, item *models.Item, unit string) (int, error) {
+	if unit == "" || unit == baseUnit(item) {
+		return 1, nil
+	}
+	
+	conversion, err := s.unitRepo.WithTx(tx).FindItemUnit(item.ID, unit)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, fmt.Errorf("%s has no conversion for unit %s", item.Name, unit)
+		}
+		return 0, err
+	}
+	return conversion.Factor, nil
+}
+
+// convertStockChange turns a change given in another unit into base units,
+// keeping what was entered for the activity log. The unit cost is spread
+// over the base units it covers.
+func (s *ItemService) convertStockChange(tx *gorm.DB, item *models.Item, change *stockChange) (*stockChange, error) {
+	unit := normalizeUnit(change.Unit)
+	factor, err := s.unitFactor(tx, item, unit)
+	if err != nil {
+		return nil, err
+	}
+	
+	entered := change.Delta
+	if entered < 0 {
+		entered = -entered
+	}
+	
+	converted := *change
+	converted.Unit = ""
+	converted.Delta = change.Delta * factor
+	converted.UnitCost = change.UnitCost / float64(factor)
+	converted.EnteredQuantity = entered
+	converted.EnteredUnit = unit
+	return &converted, nil
+}
+
+func (s *ItemService) GetItemUnits(id string) (*models.ItemUnits, error) {
+	item, err := s.itemRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("item not found")
+	}
+	
+	conversions, err := s.unitRepo.FindItemUnits(item.ID)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &models.ItemUnits{
+		ItemID:       item.ID,
+		BaseUnit:     baseUnit(item),
+		PurchaseUnit: item.PurchaseUnit,
+		IssueUnit:    item.IssueUnit,
+		Conversions:  conversions,
+	}, nil
+}
+
+// SetItemUnits replaces an item's base, purchase and issue units and its
+// conversions. The base unit can only change while the item has no stock,
+// since stock is counted in it.
+func (s *ItemService) SetItemUnits(id string, req *models.SetItemUnitsRequest, userID string) (*models.ItemUnits, error) {
+	user, err := s.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		item, err := s.itemRepo.WithTx(tx).FindByIDForUpdate(id)
+		if err != nil {
+			return errors.New("item not found")
+		}
+		unitRepo := s.unitRepo.WithTx(tx)
+		
+		base := normalizeUnit(req.BaseUnit)
+		if base == "" {
+			base = baseUnit(item)
+		}
+		if base != baseUnit(item) && item.Stock != 0 {
+			return errors.New("the base unit can only be changed while the item has no stock")
+		}
+		if err := s.requireUnit(unitRepo, base); err != nil {
+			return err
+		}
+		
+		known := map[string]bool{base: true}
+		conversions := make([]models.ItemUnit, 0, len(req.Conversions))
+		for _, entry := range req.Conversions {
+			code := normalizeUnit(entry.Unit)
+			if code == "" {
+				return errors.New("conversion unit is required")
+			}
+			if known[code] {
+				return fmt.Errorf("unit %s is listed more than once or is the base unit", code)
+			}
+			if entry.Factor < 1 {
+				return fmt.Errorf("factor of unit %s must be at least 1", code)
+			}
+			if err := s.requireUnit(unitRepo, code); err != nil {
+				return err
+			}
+			known[code] = true
+			conversions = append(conversions, models.ItemUnit{ItemID: item.ID, UnitCode: code, Factor: entry.Factor})
+		}
+		
+		purchase, issue := normalizeUnit(req.PurchaseUnit), normalizeUnit(req.IssueUnit)
+		if purchase != "" && !known[purchase] {
+			return fmt.Errorf("purchase unit %s has no conversion", purchase)
+		}
+		if issue != "" && !known[issue] {
+			return fmt.Errorf("issue unit %s has no conversion", issue)
+		}
+		
+		if err := unitRepo.ReplaceItemUnits(item.ID, conversions); err != nil {
+			return err
+		}
+		
+		item.BaseUnit = base
+		item.PurchaseUnit = purchase
+		item.IssueUnit = issue
+		if err := s.itemRepo.WithTx(tx).Update(item); err != nil {
+			return err
+		}
+		
+		activity := &models.ActivityLog{
+			UserID:      user.ID,
+			UserName:    user.Name,
+			ItemID:      item.ID,
+			ItemName:    item.Name,
+			Action:      models.ActivityTypeItemUpdated,
+			OldStock:    item.Stock,
+			NewStock:    item.Stock,
+			Description: "Units of measure updated",
+		}
+		return s.logActivity(tx, activity, item.Category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.GetItemUnits(id)
+}
+
+func (s *ItemService) requireUnit(unitRepo *repositories.UnitRepository, code string) error {
+	unit, err := unitRepo.FindByCode(code)
+	if err != nil {
+		return err
+	}
+	if unit == nil {
+		return fmt.Errorf("unit %s is not in the catalog", code)
+	}
+	return nil
+}
+
+type UnitService struct {
+	unitRepo *repositories.UnitRepository
+}
+
+func NewUnitService() *UnitService {
+	return &UnitService{
+		unitRepo: repositories.NewUnitRepository(),
+	}
+}
+
+func (s *UnitService) GetAllUnits() ([]models.Unit, error) {
+	return s.unitRepo.FindAll()
+}
+
+func (s *UnitService) CreateUnit(req *models.CreateUnitRequest) (*models.Unit, error) {
+	code := normalizeUnit(req.Code)
+	
+	existing, err := s.unitRepo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("unit code already exists")
+	}
+	
+	unit := &models.Unit{
+		Code: code,
+		Name: strings.TrimSpace(req.Name),
+	}
+	if err := s.unitRepo.Create(unit); err != nil {
+		return nil, err
+	}
+	
+	return unit, nil
+}
+
+func (s *UnitService) DeleteUnit(code string) error {
+	code = normalizeUnit(code)
+	
+	unit, err := s.unitRepo.FindByCode(code)
+	if err != nil {
+		return err
+	}
+	if unit == nil {
+		return errors.New("unit not found")
+	}
+	
+	used, err := s.unitRepo.CountUsage(code)
+	if err != nil {
+		return err
+	}
+	if used > 0 || code == models.DefaultBaseUnit {
+		return errors.New("unit is still in use")
+	}
+	
+	return s.unitRepo.Delete(code)
+}
\ No newline at end of file
@@ -0,0 +1,42 @@
+package services
+
+import (
+	"testing"
+
+	"inventory-api/internal/models"
+)
+
+// TestStockChangesDefaultToBaseUnit checks a quantity without a unit is
+// taken in base units even when the item buys and issues in boxes.
+func TestStockChangesDefaultToBaseUnit(t *testing.T) {
+	cfg := openTestDB(t)
+	service := NewItemService(cfg)
+	user := createTestUser(t)
+	
+	item := createTestItem(t, service, user, models.CreateItemRequest{})
+	_, err := service.SetItemUnits(item.ID, &models.SetItemUnitsRequest{
+		PurchaseUnit: "BOX",
+		IssueUnit:    "BOX",
+		Conversions:  []models.ItemUnitRequest{{Unit: "BOX", Factor: 12}},
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("set units: %v", err)
+	}
+	
+	item = updateTestStock(t, service, user, item.ID, models.UpdateStockRequest{
+		Quantity: 2,
+		Type:     "increment",
+	})
+	if item.Stock != 2 {
+		t.Errorf("stock after 2 without a unit = %d, want 2", item.Stock)
+	}
+	
+	item = updateTestStock(t, service, user, item.ID, models.UpdateStockRequest{
+		Quantity: 1,
+		Type:     "increment",
+		Unit:     "box",
+	})
+	if item.Stock != 14 {
+		t.Errorf("stock after 1 box = %d, want 14", item.Stock)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	conv "inventory-api/internal/lib"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+)
+
+type UserService struct {
+	userRepo    *repositories.UserRepository
+	roleRepo    *repositories.RoleRepository
+	sessionRepo *repositories.SessionRepository
+}
+
+func NewUserService() *UserService {
+	return &UserService{
+		userRepo:    repositories.NewUserRepository(),
+		roleRepo:    repositories.NewRoleRepository(),
+		sessionRepo: repositories.NewSessionRepository(),
+	}
+}
+
+func (s *UserService) GetAllUsers(page, limit int, search, role string, active *bool) ([]models.User, int64, error) {
+	return s.userRepo.FindAll(page, limit, search, role, active)
+}
+
+func (s *UserService) GetUserByID(id string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func (s *UserService) UpdateUser(id string, req *models.UpdateUserRequest, actorID string) (*models.User, error) {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	name := user.Name
+	if strings.TrimSpace(req.Name) != "" {
+		name = strings.TrimSpace(req.Name)
+	}
+	
+	role := user.Role
+	if req.Role != "" && req.Role != user.Role {
+		if id == actorID {
+			return nil, errors.New("you cannot change your own role")
+		}
+		
+		existing, err := s.roleRepo.FindByName(req.Role)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil {
+			return nil, errors.New("role not found")
+		}
+		role = req.Role
+	}
+	
+	if err := s.userRepo.UpdateProfile(id, name, role); err != nil {
+		return nil, err
+	}
+	
+	return s.userRepo.FindByID(id)
+}
+
+func (s *UserService) SetActive(id string, active bool, actorID string) (*models.User, error) {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return nil, errors.New("user not found")
+	}
+	
+	if !active && id == actorID {
+		return nil, errors.New("you cannot deactivate your own account")
+	}
+	
+	if err := s.userRepo.SetActive(id, active); err != nil {
+		return nil, err
+	}
+	
+	if !active {
+		if err := s.sessionRepo.RevokeAllForUser(id); err != nil {
+			return nil, err
+		}
+	}
+	
+	return s.userRepo.FindByID(id)
+}
+
+// ResetPassword sets a new password chosen by an admin, or a generated one
+// when none is given, and makes the user change it at their next login.
+// The plain-text password is returned so it can be handed over once.
+func (s *UserService) ResetPassword(id string, req *models.ResetPasswordRequest) (string, error) {
+	if _, err := s.userRepo.FindByID(id); err != nil {
+		return "", errors.New("user not found")
+	}
+	
+	password := req.Password
+	if password == "" {
+		generated, err := generateTemporaryPassword()
+		if err != nil {
+			return "", err
+		}
+		password = generated
+	}
+	if len(password) < 6 {
+		return "", errors.New("password must be at least 6 characters")
+	}
+	
+	hashed, err := conv.HashPassword(password)
+	if err != nil {
+		return "", err
+	}
+	
+	if err := s.userRepo.UpdatePassword(id, hashed, true); err != nil {
+		return "", err
+	}
+	
+	if err := s.sessionRepo.RevokeAllForUser(id); err != nil {
+		return "", err
+	}
+	
+	return password, nil
+}
+
+func (s *UserService) ChangePassword(id string, req *models.ChangePasswordRequest) error {
+	user, err := s.userRepo.FindByID(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	
+	if !user.CheckPassword(req.CurrentPassword) {
+		return errors.New("current password is incorrect")
+	}
+	if len(req.NewPassword) < 6 {
+		return errors.New("password must be at least 6 characters")
+	}
+	
+	hashed, err := conv.HashPassword(req.NewPassword)
+	if err != nil {
+		return err
+	}
+	
+	return s.userRepo.UpdatePassword(id, hashed, false)
+}
+
+func generateTemporaryPassword() (string, error) {
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
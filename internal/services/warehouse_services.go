@@ -0,0 +1,167 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"inventory-api/internal/database"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+type WarehouseService struct {
+	db            *gorm.DB
+	warehouseRepo *repositories.WarehouseRepository
+	itemStockRepo *repositories.ItemStockRepository
+}
+
+func NewWarehouseService() *WarehouseService {
+	return &WarehouseService{
+		db:            database.DB,
+		warehouseRepo: repositories.NewWarehouseRepository(),
+		itemStockRepo: repositories.NewItemStockRepository(),
+	}
+}
+
+func (s *WarehouseService) GetAllWarehouses() ([]models.Warehouse, error) {
+	return s.warehouseRepo.FindAll()
+}
+
+func (s *WarehouseService) GetWarehouseByID(id string) (*models.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	return warehouse, nil
+}
+
+func (s *WarehouseService) CreateWarehouse(req *models.CreateWarehouseRequest) (*models.Warehouse, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	
+	existing, err := s.warehouseRepo.FindByCode(code)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, errors.New("warehouse code already exists")
+	}
+	
+	warehouse := &models.Warehouse{
+		Code:      code,
+		Name:      req.Name,
+		Address:   req.Address,
+		IsDefault: req.IsDefault,
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		warehouseRepo := s.warehouseRepo.WithTx(tx)
+		
+		if err := warehouseRepo.Create(warehouse); err != nil {
+			return err
+		}
+		if warehouse.IsDefault {
+			return warehouseRepo.ClearDefault(warehouse.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return warehouse, nil
+}
+
+func (s *WarehouseService) UpdateWarehouse(id string, req *models.UpdateWarehouseRequest) (*models.Warehouse, error) {
+	warehouse, err := s.warehouseRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	
+	if req.Name != "" {
+		warehouse.Name = req.Name
+	}
+	if req.Address != "" {
+		warehouse.Address = req.Address
+	}
+	if req.IsDefault {
+		warehouse.IsDefault = true
+	}
+	
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		warehouseRepo := s.warehouseRepo.WithTx(tx)
+		
+		if err := warehouseRepo.Update(warehouse); err != nil {
+			return err
+		}
+		if warehouse.IsDefault {
+			return warehouseRepo.ClearDefault(warehouse.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	return s.warehouseRepo.FindByID(id)
+}
+
+func (s *WarehouseService) DeleteWarehouse(id string) error {
+	warehouse, err := s.warehouseRepo.FindByID(id)
+	if err != nil {
+		return errors.New("warehouse not found")
+	}
+	
+	if warehouse.IsDefault {
+		return errors.New("the default warehouse cannot be deleted")
+	}
+	
+	stocked, err := s.itemStockRepo.CountByWarehouse(id)
+	if err != nil {
+		return err
+	}
+	if stocked > 0 {
+		return errors.New("warehouse still holds stock")
+	}
+	
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.itemStockRepo.WithTx(tx).DeleteEmptyByWarehouse(id); err != nil {
+			return err
+		}
+		return s.warehouseRepo.WithTx(tx).Delete(id)
+	})
+}
+
+func (s *WarehouseService) CreateBin(warehouseID string, req *models.CreateBinRequest) (*models.Bin, error) {
+	if _, err := s.warehouseRepo.FindByID(warehouseID); err != nil {
+		return nil, errors.New("warehouse not found")
+	}
+	
+	bin := &models.Bin{
+		WarehouseID: warehouseID,
+		Code:        strings.ToUpper(strings.TrimSpace(req.Code)),
+		Description: req.Description,
+	}
+	if err := s.warehouseRepo.CreateBin(bin); err != nil {
+		return nil, errors.New("bin code already exists in this warehouse")
+	}
+	
+	return bin, nil
+}
+
+func (s *WarehouseService) DeleteBin(warehouseID, binID string) error {
+	if _, err := s.warehouseRepo.FindBin(warehouseID, binID); err != nil {
+		return errors.New("bin not found")
+	}
+	
+	stocked, err := s.itemStockRepo.CountByBin(binID)
+	if err != nil {
+		return err
+	}
+	if stocked > 0 {
+		return errors.New("bin still holds stock")
+	}
+	
+	return s.warehouseRepo.DeleteBin(warehouseID, binID)
+}
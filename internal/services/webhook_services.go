@@ -0,0 +1,329 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+	"inventory-api/internal/repositories"
+	"inventory-api/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	webhookBatchSize       = 50
+	webhookMaxBackoff      = 6 * time.Hour
+	webhookMaxResponseBody = 1024
+)
+
+type WebhookService struct {
+	webhookRepo *repositories.WebhookRepository
+	client      *http.Client
+	config      *config.Config
+}
+
+func NewWebhookService(cfg *config.Config) *WebhookService {
+	return &WebhookService{
+		webhookRepo: repositories.NewWebhookRepository(),
+		client: &http.Client{
+			Timeout: time.Duration(cfg.WebhookTimeoutSeconds) * time.Second,
+		},
+		config: cfg,
+	}
+}
+
+func (s *WebhookService) GetAllWebhooks() ([]models.WebhookSubscription, error) {
+	return s.webhookRepo.FindAll()
+}
+
+func (s *WebhookService) GetWebhookByID(id string) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("webhook not found")
+	}
+	return subscription, nil
+}
+
+// CreateWebhook returns the new subscription and its signing secret. The
+// secret is generated when none is given and is not shown again.
+func (s *WebhookService) CreateWebhook(req *models.CreateWebhookRequest, userID string) (*models.WebhookSubscription, string, error) {
+	if err := validateWebhookURL(req.URL); err != nil {
+		return nil, "", err
+	}
+	if err := validateEventTypes(req.EventTypes); err != nil {
+		return nil, "", err
+	}
+	
+	secret := req.Secret
+	if secret == "" {
+		generated, err := utils.GenerateSecret()
+		if err != nil {
+			return nil, "", err
+		}
+		secret = generated
+	}
+	
+	subscription := &models.WebhookSubscription{
+		Name:       req.Name,
+		URL:        req.URL,
+		Secret:     secret,
+		EventTypes: req.EventTypes,
+		Category:   req.Category,
+		ItemID:     req.ItemID,
+		IsActive:   true,
+		CreatedBy:  userID,
+	}
+	if err := s.webhookRepo.Create(subscription); err != nil {
+		return nil, "", err
+	}
+	
+	return subscription, secret, nil
+}
+
+func (s *WebhookService) UpdateWebhook(id string, req *models.UpdateWebhookRequest) (*models.WebhookSubscription, error) {
+	subscription, err := s.webhookRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("webhook not found")
+	}
+	
+	if req.Name != "" {
+		subscription.Name = req.Name
+	}
+	if req.URL != "" {
+		if err := validateWebhookURL(req.URL); err != nil {
+			return nil, err
+		}
+		subscription.URL = req.URL
+	}
+	if req.Secret != "" {
+		subscription.Secret = req.Secret
+	}
+	if req.EventTypes != nil {
+		if err := validateEventTypes(req.EventTypes); err != nil {
+			return nil, err
+		}
+		subscription.EventTypes = req.EventTypes
+	}
+	if req.Category != nil {
+		subscription.Category = *req.Category
+	}
+	if req.ItemID != nil {
+		subscription.ItemID = *req.ItemID
+	}
+	if req.IsActive != nil {
+		subscription.IsActive = *req.IsActive
+	}
+	
+	if err := s.webhookRepo.Update(subscription); err != nil {
+		return nil, err
+	}
+	
+	return subscription, nil
+}
+
+func (s *WebhookService) DeleteWebhook(id string) error {
+	if _, err := s.webhookRepo.FindByID(id); err != nil {
+		return errors.New("webhook not found")
+	}
+	return s.webhookRepo.Delete(id)
+}
+
+func (s *WebhookService) GetDeliveries(subscriptionID string, page, limit int, status string) ([]models.WebhookDelivery, int64, error) {
+	if _, err := s.webhookRepo.FindByID(subscriptionID); err != nil {
+		return nil, 0, errors.New("webhook not found")
+	}
+	return s.webhookRepo.FindDeliveries(subscriptionID, page, limit, status)
+}
+
+func (s *WebhookService) GetDeliveryByID(id string) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.FindDeliveryByID(id)
+	if err != nil {
+		return nil, errors.New("delivery not found")
+	}
+	return delivery, nil
+}
+
+// ReplayDelivery queues the payload of an earlier delivery again, whatever
+// its outcome was. The new delivery keeps the original event ID.
+func (s *WebhookService) ReplayDelivery(id string) (*models.WebhookDelivery, error) {
+	original, err := s.webhookRepo.FindDeliveryByID(id)
+	if err != nil {
+		return nil, errors.New("delivery not found")
+	}
+	
+	replay := &models.WebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         models.WebhookDeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+		ReplayOf:       original.ID,
+	}
+	if err := s.webhookRepo.CreateReplay(replay); err != nil {
+		return nil, err
+	}
+	
+	return replay, nil
+}
+
+// DeliverPending sends every delivery that is due. It is run periodically
+// by the server; failures are recorded on the delivery, not returned.
+func (s *WebhookService) DeliverPending() error {
+	lease := s.client.Timeout + time.Minute
+	
+	deliveries, err := s.webhookRepo.ClaimDue(time.Now(), lease, webhookBatchSize)
+	if err != nil {
+		return err
+	}
+	
+	for i := range deliveries {
+		if err := s.deliver(&deliveries[i]); err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func (s *WebhookService) deliver(delivery *models.WebhookDelivery) error {
+	attempt := delivery.Attempts + 1
+	
+	subscription, err := s.webhookRepo.FindByID(delivery.SubscriptionID)
+	if err != nil || !subscription.IsActive {
+		return s.webhookRepo.UpdateDeliveryResult(delivery.ID, map[string]interface{}{
+			"status":     models.WebhookDeliveryStatusFailed,
+			"last_error": "subscription is missing or inactive",
+		})
+	}
+	
+	started := time.Now()
+	statusCode, body, sendErr := s.send(subscription, delivery)
+	
+	record := &models.WebhookAttempt{
+		DeliveryID:   delivery.ID,
+		Attempt:      attempt,
+		StatusCode:   statusCode,
+		ResponseBody: body,
+		DurationMs:   time.Since(started).Milliseconds(),
+	}
+	if sendErr == nil && (statusCode < 200 || statusCode >= 300) {
+		sendErr = fmt.Errorf("receiver responded with status %d", statusCode)
+	}
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+	if err := s.webhookRepo.CreateAttempt(record); err != nil {
+		return err
+	}
+	
+	updates := map[string]interface{}{
+		"attempts":         attempt,
+		"last_status_code": statusCode,
+		"last_error":       record.Error,
+	}
+	switch {
+	case sendErr == nil:
+		updates["status"] = models.WebhookDeliveryStatusSucceeded
+		updates["delivered_at"] = time.Now()
+	case attempt >= s.config.WebhookMaxAttempts:
+		updates["status"] = models.WebhookDeliveryStatusFailed
+	default:
+		updates["next_attempt_at"] = time.Now().Add(s.backoff(attempt))
+	}
+	
+	return s.webhookRepo.UpdateDeliveryResult(delivery.ID, updates)
+}
+
+func (s *WebhookService) send(subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, string, error) {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+	
+	req, err := http.NewRequest(http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "inventory-api-webhooks/1.0")
+	req.Header.Set("X-Webhook-ID", delivery.ID)
+	req.Header.Set("X-Webhook-Event", string(delivery.EventType))
+	req.Header.Set("X-Webhook-Event-ID", delivery.EventID)
+	req.Header.Set("X-Webhook-Timestamp", fmt.Sprintf("%d", timestamp))
+	req.Header.Set("X-Webhook-Signature", "sha256="+utils.SignPayload(subscription.Secret, timestamp, body))
+	
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	return resp.StatusCode, string(respBody), nil
+}
+
+// backoff doubles the base delay after every failed attempt.
+func (s *WebhookService) backoff(attempt int) time.Duration {
+	base := time.Duration(s.config.WebhookBackoffSeconds) * time.Second
+	delay := time.Duration(float64(base) * math.Pow(2, float64(attempt-1)))
+	if delay <= 0 || delay > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return delay
+}
+
+// enqueueWebhooks queues a delivery of event for every matching
+// subscription. payload is sent verbatim so every subscriber and every
+// retry sees the same bytes.
+func enqueueWebhooks(tx *gorm.DB, event *models.ActivityEvent, payload string) error {
+	webhookRepo := repositories.NewWebhookRepository().WithTx(tx)
+	
+	subscriptions, err := webhookRepo.FindActive()
+	if err != nil {
+		return err
+	}
+	
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(event.Data, event.Category) {
+			continue
+		}
+		
+		err := webhookRepo.CreateDelivery(&models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Payload:        payload,
+			Status:         models.WebhookDeliveryStatusPending,
+			NextAttemptAt:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	
+	return nil
+}
+
+func validateWebhookURL(raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+func validateEventTypes(eventTypes []models.ActivityType) error {
+	for _, eventType := range eventTypes {
+		if !models.ActivityTypes[eventType] {
+			return fmt.Errorf("unknown event type: %s", eventType)
+		}
+	}
+	return nil
+}
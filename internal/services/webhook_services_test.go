@@ -0,0 +1,247 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"inventory-api/internal/config"
+	"inventory-api/internal/models"
+)
+
+// webhookReceiver is a test endpoint that checks the signature of every
+// request and answers with the next of its status codes, repeating the last.
+type webhookReceiver struct {
+	t        *testing.T
+	secret   string
+	statuses []int
+	
+	mu       sync.Mutex
+	requests int
+}
+
+func (rc *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	
+	mac := hmac.New(sha256.New, []byte(rc.secret))
+	mac.Write([]byte(r.Header.Get("X-Webhook-Timestamp") + "."))
+	mac.Write(body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := r.Header.Get("X-Webhook-Signature"); !hmac.Equal([]byte(got), []byte(want)) {
+		rc.t.Errorf("signature = %q, want %q", got, want)
+	}
+	if r.Header.Get("X-Webhook-Event-ID") == "" {
+		rc.t.Error("missing X-Webhook-Event-ID header")
+	}
+	
+	rc.mu.Lock()
+	status := rc.statuses[len(rc.statuses)-1]
+	if rc.requests < len(rc.statuses) {
+		status = rc.statuses[rc.requests]
+	}
+	rc.requests++
+	rc.mu.Unlock()
+	
+	w.WriteHeader(status)
+}
+
+// respond replaces the status codes still to be answered.
+func (rc *webhookReceiver) respond(statuses ...int) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.statuses = statuses
+	rc.requests = 0
+}
+
+func (rc *webhookReceiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.requests
+}
+
+func TestWebhookSendSignsPayload(t *testing.T) {
+	receiver := &webhookReceiver{t: t, secret: "s3cret", statuses: []int{http.StatusNoContent}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	
+	service := NewWebhookService(&config.Config{WebhookTimeoutSeconds: 5})
+	subscription := &models.WebhookSubscription{URL: server.URL, Secret: receiver.secret}
+	delivery := &models.WebhookDelivery{
+		ID:        "delivery-1",
+		EventID:   "event-1",
+		EventType: models.ActivityTypeStockDecrement,
+		Payload:   `{"id":"event-1"}`,
+	}
+	
+	status, _, err := service.send(subscription, delivery)
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Errorf("status = %d, want %d", status, http.StatusNoContent)
+	}
+	if got := receiver.count(); got != 1 {
+		t.Errorf("receiver got %d requests, want 1", got)
+	}
+}
+
+func TestWebhookBackoffDoubles(t *testing.T) {
+	service := NewWebhookService(&config.Config{WebhookBackoffSeconds: 30})
+	
+	cases := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		20: webhookMaxBackoff,
+	}
+	for attempt, want := range cases {
+		if got := service.backoff(attempt); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+// TestWebhookDeliverRetriesNonSuccess checks that a receiver answering with
+// errors gets the delivery again after a growing delay, until it succeeds
+// or the attempts run out.
+func TestWebhookDeliverRetriesNonSuccess(t *testing.T) {
+	cfg := openTestDB(t)
+	user := createTestUser(t)
+	
+	receiver := &webhookReceiver{t: t, secret: "s3cret", statuses: []int{500, 502, 200}}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	
+	testCfg := *cfg
+	testCfg.WebhookTimeoutSeconds = 5
+	testCfg.WebhookBackoffSeconds = 30
+	testCfg.WebhookMaxAttempts = 5
+	service := NewWebhookService(&testCfg)
+	
+	subscription, _, err := service.CreateWebhook(&models.CreateWebhookRequest{
+		Name:   "retry test",
+		URL:    server.URL,
+		Secret: receiver.secret,
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	t.Cleanup(func() { service.DeleteWebhook(subscription.ID) })
+	
+	delivery := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        "retry-test-event",
+		EventType:      models.ActivityTypeStockDecrement,
+		Payload:        `{"id":"retry-test-event"}`,
+		Status:         models.WebhookDeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+	}
+	if err := service.webhookRepo.CreateDelivery(delivery); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+	
+	for attempt, wantDelay := range []time.Duration{30 * time.Second, time.Minute} {
+		current, err := service.GetDeliveryByID(delivery.ID)
+		if err != nil {
+			t.Fatalf("reload delivery: %v", err)
+		}
+		before := time.Now()
+		if err := service.deliver(current); err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		
+		current, _ = service.GetDeliveryByID(delivery.ID)
+		if current.Status != models.WebhookDeliveryStatusPending || current.Attempts != attempt+1 {
+			t.Fatalf("after attempt %d: status %s with %d attempts", attempt+1, current.Status, current.Attempts)
+		}
+		if want := []int{500, 502}[attempt]; current.LastStatusCode != want {
+			t.Errorf("last status code = %d, want %d", current.LastStatusCode, want)
+		}
+		delay := current.NextAttemptAt.Sub(before)
+		if delay < wantDelay || delay > wantDelay+5*time.Second {
+			t.Errorf("after attempt %d: next attempt in %s, want %s", attempt+1, delay, wantDelay)
+		}
+	}
+	
+	current, _ := service.GetDeliveryByID(delivery.ID)
+	if err := service.deliver(current); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	current, _ = service.GetDeliveryByID(delivery.ID)
+	if current.Status != models.WebhookDeliveryStatusSucceeded || current.DeliveredAt == nil {
+		t.Errorf("final status = %s, want %s", current.Status, models.WebhookDeliveryStatusSucceeded)
+	}
+	if len(current.AttemptLog) != 3 {
+		t.Errorf("attempt log has %d entries, want 3", len(current.AttemptLog))
+	}
+	
+	testCfg.WebhookMaxAttempts = 1
+	failing := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        "retry-test-exhausted",
+		EventType:      models.ActivityTypeStockDecrement,
+		Payload:        `{"id":"retry-test-exhausted"}`,
+		Status:         models.WebhookDeliveryStatusPending,
+		NextAttemptAt:  time.Now(),
+	}
+	receiver.respond(503)
+	if err := service.webhookRepo.CreateDelivery(failing); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+	if err := service.deliver(failing); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	current, _ = service.GetDeliveryByID(failing.ID)
+	if current.Status != models.WebhookDeliveryStatusFailed {
+		t.Errorf("exhausted delivery status = %s, want %s", current.Status, models.WebhookDeliveryStatusFailed)
+	}
+}
+
+func TestReplayDeliveryTwice(t *testing.T) {
+	cfg := openTestDB(t)
+	user := createTestUser(t)
+	service := NewWebhookService(cfg)
+	
+	subscription, _, err := service.CreateWebhook(&models.CreateWebhookRequest{
+		Name: "replay test",
+		URL:  "http://127.0.0.1:1/hook",
+	}, user.ID)
+	if err != nil {
+		t.Fatalf("create webhook: %v", err)
+	}
+	t.Cleanup(func() { service.DeleteWebhook(subscription.ID) })
+	service.UpdateWebhook(subscription.ID, &models.UpdateWebhookRequest{IsActive: new(bool)})
+	
+	original := &models.WebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventID:        "replay-test-event",
+		EventType:      models.ActivityTypeStockDecrement,
+		Payload:        `{"id":"replay-test-event"}`,
+		Status:         models.WebhookDeliveryStatusFailed,
+		NextAttemptAt:  time.Now(),
+	}
+	if err := service.webhookRepo.CreateDelivery(original); err != nil {
+		t.Fatalf("create delivery: %v", err)
+	}
+	
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		replay, err := service.ReplayDelivery(original.ID)
+		if err != nil {
+			t.Fatalf("replay %d: %v", i+1, err)
+		}
+		if seen[replay.ID] {
+			t.Fatalf("replay %d reused delivery %s", i+1, replay.ID)
+		}
+		seen[replay.ID] = true
+		if _, err := service.GetDeliveryByID(replay.ID); err != nil {
+			t.Errorf("replay %d was not stored: %v", i+1, err)
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package stream
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"inventory-api/internal/models"
+)
+
+const (
+	EventItemCreated  = "item-created"
+	EventItemUpdated  = "item-updated"
+	EventItemDeleted  = "item-deleted"
+	EventStockChanged = "stock-changed"
+)
+
+// subscriberBuffer is how many events a slow client may fall behind before
+// it is disconnected. Reconnecting with Last-Event-ID recovers the gap.
+const subscriberBuffer = 256
+
+// Event is one message pushed to stream clients. ID is the outbox event ID
+// and is what clients send back as Last-Event-ID.
+type Event struct {
+	ID         string              `json:"id"`
+	Type       string              `json:"type"`
+	ItemID     string              `json:"item_id"`
+	Category   string              `json:"category,omitempty"`
+	OccurredAt time.Time           `json:"occurred_at"`
+	Activity   *models.ActivityLog `json:"activity"`
+}
+
+// EventType maps an activity to the stream event it is published as.
+func EventType(action models.ActivityType) string {
+	switch action {
+	case models.ActivityTypeItemCreated:
+		return EventItemCreated
+	case models.ActivityTypeItemUpdated:
+		return EventItemUpdated
+	case models.ActivityTypeItemDeleted:
+		return EventItemDeleted
+	default:
+		return EventStockChanged
+	}
+}
+
+// Filter narrows a subscription. Empty fields match everything.
+type Filter struct {
+	ItemIDs    map[string]bool
+	Categories map[string]bool
+	Types      map[string]bool
+}
+
+// ParseFilter builds a filter from comma-separated query values. The values
+// are copied, as request strings must not outlive the handler.
+func ParseFilter(itemIDs, categories, types string) *Filter {
+	return &Filter{
+		ItemIDs:    splitSet(itemIDs),
+		Categories: splitSet(categories),
+		Types:      splitSet(types),
+	}
+}
+
+func (f *Filter) Matches(event *Event) bool {
+	if len(f.ItemIDs) > 0 && !f.ItemIDs[event.ItemID] {
+		return false
+	}
+	if len(f.Categories) > 0 && !f.Categories[event.Category] {
+		return false
+	}
+	if len(f.Types) > 0 && !f.Types[event.Type] {
+		return false
+	}
+	return true
+}
+
+func splitSet(value string) map[string]bool {
+	set := map[string]bool{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			set[strings.Clone(part)] = true
+		}
+	}
+	return set
+}
+
+type Subscription struct {
+	Events <-chan *Event
+	events chan *Event
+	filter *Filter
+}
+
+// Broker fans events out to the subscribers of this process.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscription]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: map[*Subscription]struct{}{}}
+}
+
+// Default is the broker shared by the outbox dispatcher and the stream
+// endpoint.
+var Default = NewBroker()
+
+func (b *Broker) Subscribe(filter *Filter) *Subscription {
+	events := make(chan *Event, subscriberBuffer)
+	sub := &Subscription{Events: events, events: events, filter: filter}
+	
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+	
+	return sub
+}
+
+// Unsubscribe closes the subscription's channel. It is safe to call more
+// than once.
+func (b *Broker) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	if _, ok := b.subscribers[sub]; ok {
+		delete(b.subscribers, sub)
+		close(sub.events)
+	}
+}
+
+// Publish never blocks. A subscriber whose buffer is full is dropped and
+// sees its channel closed.
+func (b *Broker) Publish(event *Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
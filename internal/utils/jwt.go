@@ -4,39 +4,51 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 type Claims struct {
-	UserID string `json:"user_id"`
-	Email  string `json:"email"`
-	UserName string `json:"user_name"`
-	Role   string `json:"role"`
+	UserID    string `json:"user_id"`
+	Email     string `json:"email"`
+	UserName  string `json:"user_name"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-func GenerateJWT(userID, email, userName, role, secret string, expireHours int) (string, error) {
-	expirationTime := time.Now().Add(time.Duration(expireHours) * time.Hour)
+func GenerateJWT(userID, email, userName, role, sessionID, secret string, expireMinutes int) (string, *Claims, error) {
+	now := time.Now()
+	expirationTime := now.Add(time.Duration(expireMinutes) * time.Minute)
 	
 	claims := &Claims{
-		UserID: userID,
-		Email:  email,
-		UserName: userName,
-		Role:   role,
+		UserID:    userID,
+		Email:     email,
+		UserName:  userName,
+		Role:      role,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Issuer:    "inventory-api",
 		},
 	}
 	
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		return "", nil, err
+	}
+	return signed, claims, nil
 }
 
 func ValidateJWT(tokenString, secret string) (*Claims, error) {
 	claims := &Claims{}
 	
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
 		return []byte(secret), nil
 	})
 	
@@ -49,4 +61,4 @@ func ValidateJWT(tokenString, secret string) (*Claims, error) {
 	}
 	
 	return claims, nil
-}
\ No newline at end of file
+}